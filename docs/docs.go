@@ -15,6 +15,942 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/reload": {
+            "post": {
+                "description": "Loads the configs again from the environment variables, the .env file, and the config file, and recreates the clients of the sources whose configs changed. If the new configs are invalid, the current ones are kept. Requires the ACTIONS_SECRET as a bearer token or the AUTH_TRUSTED_HEADER.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Reload configs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer ACTIONS_SECRET.",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Configs reloaded",
+                        "schema": {
+                            "$ref": "#/definitions/routes.ReloadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid configs",
+                        "schema": {
+                            "$ref": "#/definitions/sources.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/sources.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/alarms/ingest": {
+            "post": {
+                "description": "Receives alarms from external tools, shown by the webhook alarms source. The body can be an Alertmanager webhook payload (also sent by the Grafana alerting webhook), an alarm, or a list of alarms. An alarm is shown until it's resolved, or until its TTL expires if it's not sent again. Requires the ACTIONS_SECRET as a bearer token or the AUTH_TRUSTED_HEADER.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Ingest alarms",
+                "parameters": [
+                    {
+                        "description": "An Alertmanager webhook payload, an alarm, or a list of alarms.",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/alarms.IngestAlarm"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "example": "Grafana",
+                        "description": "Source name of the alarms without one. Defaults to Alertmanager for Alertmanager payloads and Webhook for the others.",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer ACTIONS_SECRET.",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Alarms received",
+                        "schema": {
+                            "$ref": "#/definitions/alarms.IngestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload",
+                        "schema": {
+                            "$ref": "#/definitions/sources.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/sources.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/badge/alarms.svg": {
+            "get": {
+                "description": "Returns an SVG badge with the number of alarms by severity, colored with the highest severity, or 'all clear'.",
+                "produces": [
+                    "image/svg+xml"
+                ],
+                "summary": "Alarms SVG badge",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "netdata,radarr,radarr:4k",
+                        "description": "Alarms to show. Available values: netdata, radarr, lidarr, sonarr, prowlarr, speedtest-tracker, pihole, kavita, kaizoku, changedetectionio, backrest, openarchiver, webhook. Use name:instance to get alarms from a named instance, like radarr:4k.",
+                        "name": "alarms",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Sort alarms in descending order. Defaults to false.",
+                        "name": "sort_desc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Show only alarms that match or not the regex. Default to true.",
+                        "name": "regex_include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Show viewed alarms from changedetection.io. Defaults to true.",
+                        "name": "changedetectionio_show_viewed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "resolved",
+                        "description": "'active' shows the current alarms, 'resolved' shows the alarms resolved in the last 'since' duration, the last resolved first. The resolved mode requires the alarms history. Defaults to active.",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "12h",
+                        "description": "In the resolved mode, how long ago the alarms can be resolved. Defaults to 24h.",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "hide",
+                        "description": "'dim' shows the acknowledged alarms dimmed, 'hide' hides them. The snoozed alarms are always hidden. Defaults to dim.",
+                        "name": "acknowledged",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "error",
+                        "description": "Show only alarms with this severity or higher. The severities are ok, info, warning, error, and critical, normalized from the status of each source.",
+                        "name": "min_severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "warning,critical",
+                        "description": "Show only alarms with these severities, comma separated.",
+                        "name": "severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "severity=critical OR source=Netdata",
+                        "description": "Filter expression, like 'summary~\\",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "ops",
+                        "description": "Comma separated names of the filter presets set in ALARMS_FILTER_PRESET_\u003cNAME\u003e.",
+                        "name": "filter_preset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "homelab",
+                        "description": "Text on the left of the badge. Defaults to alarms.",
+                        "name": "label",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "SVG badge",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/calendar/media.ics": {
+            "get": {
+                "description": "Returns the media releases from Radarr/Sonarr/Lidarr as an iCalendar (ICS) feed, to be subscribed by calendar apps like Google Calendar and Nextcloud. Movies and albums are all-day events, and episodes are events at their air time. Defaults to the releases from 14 days before today to 60 days after today.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "summary": "Media releases iCalendar feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "inCinemas,digital",
+                        "description": "Filter movies get from Radarr. Can be 'inCinemas', 'physical', 'digital', or multiple separated by comma. Defaults to 'inCinemas,physical,digital'",
+                        "name": "radarrReleaseType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "Specify if show unmonitored media. Defaults to false.",
+                        "name": "showUnmonitored",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 7,
+                        "description": "Number of days before today to show. Defaults to 14.",
+                        "name": "days_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 30,
+                        "description": "Number of days after today to show. Defaults to 60.",
+                        "name": "days_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-10-01",
+                        "description": "First day to show, like '2026-10-01'. Must be set with end, and can't be used with days_before and days_after.",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-10-31",
+                        "description": "Last day to show, like '2026-10-31'. The calendar can have at most 92 days.",
+                        "name": "end",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/alarms": {
+            "get": {
+                "description": "Get the alarms as JSON, in the same order as the iFrame. A source that fails or times out returns an ERROR alarm with the error in the property field.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the alarms data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "netdata,radarr,radarr:4k",
+                        "description": "Alarms to show. Available values: netdata, radarr, lidarr, sonarr, prowlarr, speedtest-tracker, pihole, kavita, kaizoku, changedetectionio, backrest, openarchiver, webhook. Use name:instance to get alarms from a named instance, like radarr:4k.",
+                        "name": "alarms",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Sort alarms in descending order. Defaults to false.",
+                        "name": "sort_desc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Show only alarms that match or not the regex. Default to true.",
+                        "name": "regex_include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Show viewed alarms from changedetection.io. Defaults to true.",
+                        "name": "changedetectionio_show_viewed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "resolved",
+                        "description": "'active' shows the current alarms, 'resolved' shows the alarms resolved in the last 'since' duration, the last resolved first. The resolved mode requires the alarms history. Defaults to active.",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "12h",
+                        "description": "In the resolved mode, how long ago the alarms can be resolved. Defaults to 24h.",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "hide",
+                        "description": "'dim' shows the acknowledged alarms dimmed, 'hide' hides them. The snoozed alarms are always hidden. Defaults to dim.",
+                        "name": "acknowledged",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "error",
+                        "description": "Show only alarms with this severity or higher. The severities are ok, info, warning, error, and critical, normalized from the status of each source.",
+                        "name": "min_severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "warning,critical",
+                        "description": "Show only alarms with these severities, comma separated.",
+                        "name": "severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "severity=critical OR source=Netdata",
+                        "description": "Filter expression, like 'summary~\\",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "ops",
+                        "description": "Comma separated names of the filter presets set in ALARMS_FILTER_PRESET_\u003cNAME\u003e.",
+                        "name": "filter_preset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "summary,source",
+                        "description": "Group the alarms with the same keys in a collapsible card. Comma separated list of: summary (similar summaries), property, source (the same source or ALARMS_SOURCE_GROUP_\u003cNAME\u003e), and severity.",
+                        "name": "group_by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/alarms.Data"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/cinemark": {
+            "get": {
+                "description": "Get the on display movies in specific Cinemark theaters as JSON, in the same order as the iFrame.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the Cinemark movies data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "715, 1222, 4555",
+                        "description": "The theater IDs to get movies from. Example: 'theaterIds=715, 1222, 4555'",
+                        "name": "theaterIds",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 5,
+                        "description": "Limits the number of items.",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cinemark.Data"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/linkwarden": {
+            "get": {
+                "description": "Get the Linkwarden bookmarks as JSON, in the same order as the iFrame.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the Linkwarden bookmarks data",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Get bookmarks only from this collection.",
+                        "name": "collectionId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 5,
+                        "description": "Limits the number of items.",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/linkwarden.Data"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/media_releases": {
+            "get": {
+                "description": "Get the media releases from Radarr/Sonarr/Lidarr as JSON, in the same order as the iFrame. Defaults to the releases of today.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the media releases data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "inCinemas,digital",
+                        "description": "Filter movies get from Radarr. Can be 'inCinemas', 'physical', 'digital', or multiple separated by comma. Defaults to 'inCinemas,physical,digital'",
+                        "name": "radarrReleaseType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "Specify if show unmonitored media. Defaults to false.",
+                        "name": "showUnmonitored",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Number of days before today to show. Defaults to 0.",
+                        "name": "days_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 6,
+                        "description": "Number of days after today to show. Defaults to 0.",
+                        "name": "days_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-10-01",
+                        "description": "First day to show, like '2026-10-01'. Must be set with end, and can't be used with days_before and days_after.",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-10-31",
+                        "description": "Last day to show, like '2026-10-31'. The calendar can have at most 92 days.",
+                        "name": "end",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/media.Data"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/media_requests": {
+            "get": {
+                "description": "Get the Overseerr/Jellyseerr media requests as JSON, in the same order as the iFrame.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the media requests data",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 5,
+                        "description": "Limits the number of items.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "all",
+                        "description": "Filters for request status and media status. Available values: all, approved, available, pending, processing, unavailable, failed, deleted, completed, allavaliable (showMedia=true). Defaults to all",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "added",
+                        "description": "Available values: added, modified, mediaAdded (showMedia=true). Defaults to added",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "1",
+                        "description": "If specified, only requests from that particular overseerr user ID will be returned.",
+                        "name": "requestedByOverseerr",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "1",
+                        "description": "If specified, only requests from that particular jellyseerr user ID will be returned.",
+                        "name": "requestedByJellyseerr",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "true",
+                        "description": "If true, shows the requests' media data, not the requests and media data. Defaults to false.",
+                        "name": "showMedia",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/mediarequets.Data"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/uptimekuma": {
+            "get": {
+                "description": "Get the number of up and down sites of an Uptime Kuma status page as JSON.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the Uptime Kuma sites status data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "uptime-kuma-slug",
+                        "description": "Slug of the Uptime Kuma status page.",
+                        "name": "slug",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/uptimekuma.Data"
+                        }
+                    }
+                }
+            }
+        },
+        "/data/vikunja": {
+            "get": {
+                "description": "Get the not done Vikunja tasks as JSON, in the same order as the iFrame. The due and end dates are omitted if the task doesn't have them.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Get the Vikunja tasks data",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 5,
+                        "description": "Limits the number of items.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Project ID to get tasks from. Inbox tasks = 1, Favorite tasks = -1.",
+                        "name": "project_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "1,5,7",
+                        "description": "Project IDs to NOT get tasks from.",
+                        "name": "exclude_project_ids",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/vikunja.Data"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{name}": {
+            "get": {
+                "description": "Server-Sent Events stream used by the iFrames to update without reloading. Sends an \"update\" event with the iFrame HTML as a JSON string when the iFrame changes, and an \"error\" event if the iFrame can't be created. Accepts the same query parameters as the iFrame route.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "summary": "iFrame live updates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "vikunja",
+                        "description": "iFrame name, like vikunja or media_releases.",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/feed/alarms.atom": {
+            "get": {
+                "description": "Returns the alarms as an Atom feed. Each entry has an ID derived from the alarm fingerprint and links to the alarm URL.",
+                "produces": [
+                    "text/xml"
+                ],
+                "summary": "Alarms Atom feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "netdata,radarr,radarr:4k",
+                        "description": "Alarms to show. Available values: netdata, radarr, lidarr, sonarr, prowlarr, speedtest-tracker, pihole, kavita, kaizoku, changedetectionio, backrest, openarchiver, webhook. Use name:instance to get alarms from a named instance, like radarr:4k.",
+                        "name": "alarms",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Sort alarms in descending order. Defaults to false.",
+                        "name": "sort_desc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Show only alarms that match or not the regex. Default to true.",
+                        "name": "regex_include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Show viewed alarms from changedetection.io. Defaults to true.",
+                        "name": "changedetectionio_show_viewed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "resolved",
+                        "description": "'active' shows the current alarms, 'resolved' shows the alarms resolved in the last 'since' duration, the last resolved first. The resolved mode requires the alarms history. Defaults to active.",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "12h",
+                        "description": "In the resolved mode, how long ago the alarms can be resolved. Defaults to 24h.",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "hide",
+                        "description": "'dim' shows the acknowledged alarms dimmed, 'hide' hides them. The snoozed alarms are always hidden. Defaults to dim.",
+                        "name": "acknowledged",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "error",
+                        "description": "Show only alarms with this severity or higher. The severities are ok, info, warning, error, and critical, normalized from the status of each source.",
+                        "name": "min_severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "warning,critical",
+                        "description": "Show only alarms with these severities, comma separated.",
+                        "name": "severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "severity=critical OR source=Netdata",
+                        "description": "Filter expression, like 'summary~\\",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "ops",
+                        "description": "Comma separated names of the filter presets set in ALARMS_FILTER_PRESET_\u003cNAME\u003e.",
+                        "name": "filter_preset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Atom feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/feed/alarms.json": {
+            "get": {
+                "description": "Returns the alarms as a JSON Feed 1.1. Each item has an ID derived from the alarm fingerprint and links to the alarm URL.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Alarms JSON feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "netdata,radarr,radarr:4k",
+                        "description": "Alarms to show. Available values: netdata, radarr, lidarr, sonarr, prowlarr, speedtest-tracker, pihole, kavita, kaizoku, changedetectionio, backrest, openarchiver, webhook. Use name:instance to get alarms from a named instance, like radarr:4k.",
+                        "name": "alarms",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Sort alarms in descending order. Defaults to false.",
+                        "name": "sort_desc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Show only alarms that match or not the regex. Default to true.",
+                        "name": "regex_include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Show viewed alarms from changedetection.io. Defaults to true.",
+                        "name": "changedetectionio_show_viewed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "resolved",
+                        "description": "'active' shows the current alarms, 'resolved' shows the alarms resolved in the last 'since' duration, the last resolved first. The resolved mode requires the alarms history. Defaults to active.",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "12h",
+                        "description": "In the resolved mode, how long ago the alarms can be resolved. Defaults to 24h.",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "hide",
+                        "description": "'dim' shows the acknowledged alarms dimmed, 'hide' hides them. The snoozed alarms are always hidden. Defaults to dim.",
+                        "name": "acknowledged",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "error",
+                        "description": "Show only alarms with this severity or higher. The severities are ok, info, warning, error, and critical, normalized from the status of each source.",
+                        "name": "min_severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "warning,critical",
+                        "description": "Show only alarms with these severities, comma separated.",
+                        "name": "severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "severity=critical OR source=Netdata",
+                        "description": "Filter expression, like 'summary~\\",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "ops",
+                        "description": "Comma separated names of the filter presets set in ALARMS_FILTER_PRESET_\u003cNAME\u003e.",
+                        "name": "filter_preset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "JSON feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/feed/alarms.rss": {
+            "get": {
+                "description": "Returns the alarms as an RSS 2.0 feed. Each item has a GUID derived from the alarm fingerprint and links to the alarm URL.",
+                "produces": [
+                    "text/xml"
+                ],
+                "summary": "Alarms RSS feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "netdata,radarr,radarr:4k",
+                        "description": "Alarms to show. Available values: netdata, radarr, lidarr, sonarr, prowlarr, speedtest-tracker, pihole, kavita, kaizoku, changedetectionio, backrest, openarchiver, webhook. Use name:instance to get alarms from a named instance, like radarr:4k.",
+                        "name": "alarms",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Sort alarms in descending order. Defaults to false.",
+                        "name": "sort_desc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Show only alarms that match or not the regex. Default to true.",
+                        "name": "regex_include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Show viewed alarms from changedetection.io. Defaults to true.",
+                        "name": "changedetectionio_show_viewed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "resolved",
+                        "description": "'active' shows the current alarms, 'resolved' shows the alarms resolved in the last 'since' duration, the last resolved first. The resolved mode requires the alarms history. Defaults to active.",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "12h",
+                        "description": "In the resolved mode, how long ago the alarms can be resolved. Defaults to 24h.",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "hide",
+                        "description": "'dim' shows the acknowledged alarms dimmed, 'hide' hides them. The snoozed alarms are always hidden. Defaults to dim.",
+                        "name": "acknowledged",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "error",
+                        "description": "Show only alarms with this severity or higher. The severities are ok, info, warning, error, and critical, normalized from the status of each source.",
+                        "name": "min_severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "warning,critical",
+                        "description": "Show only alarms with these severities, comma separated.",
+                        "name": "severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "severity=critical OR source=Netdata",
+                        "description": "Filter expression, like 'summary~\\",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "ops",
+                        "description": "Comma separated names of the filter presets set in ALARMS_FILTER_PRESET_\u003cNAME\u003e.",
+                        "name": "filter_preset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "RSS feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/hash/alarms": {
             "get": {
                 "description": "Get the hash of the alarms. Used by the iFrames to check updates and reload the iframe.",
@@ -25,8 +961,8 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "netdata,radarr,sonarr",
-                        "description": "Alarms to show. Available values: netdata, radarr, lidarr, sonarr, prowlarr, speedtest-tracker, pihole, kavita, kaizoku, changedetectionio, backrest, openarchiver",
+                        "example": "netdata,radarr,radarr:4k",
+                        "description": "Alarms to show. Available values: netdata, radarr, lidarr, sonarr, prowlarr, speedtest-tracker, pihole, kavita, kaizoku, changedetectionio, backrest, openarchiver, webhook. Use name:instance to get alarms from a named instance, like radarr:4k.",
                         "name": "alarms",
                         "in": "query",
                         "required": true
@@ -51,13 +987,62 @@ const docTemplate = `{
                         "description": "Show viewed alarms from changedetection.io. Defaults to true.",
                         "name": "changedetectionio_show_viewed",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "resolved",
+                        "description": "'active' shows the current alarms, 'resolved' shows the alarms resolved in the last 'since' duration, the last resolved first. The resolved mode requires the alarms history. Defaults to active.",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "12h",
+                        "description": "In the resolved mode, how long ago the alarms can be resolved. Defaults to 24h.",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "hide",
+                        "description": "'dim' shows the acknowledged alarms dimmed, 'hide' hides them. The snoozed alarms are always hidden. Defaults to dim.",
+                        "name": "acknowledged",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "error",
+                        "description": "Show only alarms with this severity or higher. The severities are ok, info, warning, error, and critical, normalized from the status of each source.",
+                        "name": "min_severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "warning,critical",
+                        "description": "Show only alarms with these severities, comma separated.",
+                        "name": "severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "severity=critical OR source=Netdata",
+                        "description": "Filter expression, like 'summary~\\",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "ops",
+                        "description": "Comma separated names of the filter presets set in ALARMS_FILTER_PRESET_\u003cNAME\u003e.",
+                        "name": "filter_preset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.hashResponse"
+                            "$ref": "#/definitions/sources.HashResponse"
                         }
                     }
                 }
@@ -91,7 +1076,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.hashResponse"
+                            "$ref": "#/definitions/sources.HashResponse"
                         }
                     }
                 }
@@ -124,7 +1109,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.hashResponse"
+                            "$ref": "#/definitions/sources.HashResponse"
                         }
                     }
                 }
@@ -151,13 +1136,41 @@ const docTemplate = `{
                         "description": "Specify if show unmonitored media. Defaults to false.",
                         "name": "showUnmonitored",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Number of days before today to show. Defaults to 0.",
+                        "name": "days_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 6,
+                        "description": "Number of days after today to show. Defaults to 0.",
+                        "name": "days_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-10-01",
+                        "description": "First day to show, like '2026-10-01'. Must be set with end, and can't be used with days_before and days_after.",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-10-31",
+                        "description": "Last day to show, like '2026-10-31'. The calendar can have at most 92 days.",
+                        "name": "end",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.hashResponse"
+                            "$ref": "#/definitions/sources.HashResponse"
                         }
                     }
                 }
@@ -218,7 +1231,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.hashResponse"
+                            "$ref": "#/definitions/sources.HashResponse"
                         }
                     }
                 }
@@ -245,7 +1258,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.hashResponse"
+                            "$ref": "#/definitions/sources.HashResponse"
                         }
                     }
                 }
@@ -278,7 +1291,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.hashResponse"
+                            "$ref": "#/definitions/sources.HashResponse"
                         }
                     }
                 }
@@ -319,15 +1332,15 @@ const docTemplate = `{
                     {
                         "type": "string",
                         "example": "https://sub.domain.com",
-                        "description": "API URL used by your browser. Use by the iFrames to check any update, if there is an update, the iFrame reloads. If not specified, the iFrames will never try to reload.",
+                        "description": "API URL used by your browser. Used by the iFrames to receive updates, if there is an update, the iFrame updates the changed items without reloading. If not specified, the iFrames will never try to reload.",
                         "name": "api_url",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "example": "netdata,radarr,sonarr",
-                        "description": "Alarms to show. Available values: netdata, radarr, lidarr, sonarr, prowlarr, speedtest-tracker, pihole, kavita, kaizoku, changedetectionio, backrest, openarchiver",
+                        "example": "netdata,radarr,radarr:4k",
+                        "description": "Alarms to show. Available values: netdata, radarr, lidarr, sonarr, prowlarr, speedtest-tracker, pihole, kavita, kaizoku, changedetectionio, backrest, openarchiver, webhook. Use name:instance to get alarms from a named instance, like radarr:4k.",
                         "name": "alarms",
                         "in": "query",
                         "required": true
@@ -352,6 +1365,76 @@ const docTemplate = `{
                         "description": "Show viewed alarms from changedetection.io. Defaults to true.",
                         "name": "changedetectionio_show_viewed",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "resolved",
+                        "description": "'active' shows the current alarms, 'resolved' shows the alarms resolved in the last 'since' duration, the last resolved first. The resolved mode requires the alarms history. Defaults to active.",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "12h",
+                        "description": "In the resolved mode, how long ago the alarms can be resolved. Defaults to 24h.",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "hide",
+                        "description": "'dim' shows the acknowledged alarms dimmed, 'hide' hides them. The snoozed alarms are always hidden. Defaults to dim.",
+                        "name": "acknowledged",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "error",
+                        "description": "Show only alarms with this severity or higher. The severities are ok, info, warning, error, and critical, normalized from the status of each source.",
+                        "name": "min_severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "warning,critical",
+                        "description": "Show only alarms with these severities, comma separated.",
+                        "name": "severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "severity=critical OR source=Netdata",
+                        "description": "Filter expression, like 'summary~\\",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "ops",
+                        "description": "Comma separated names of the filter presets set in ALARMS_FILTER_PRESET_\u003cNAME\u003e.",
+                        "name": "filter_preset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "summary,source",
+                        "description": "Group the alarms with the same keys in a collapsible card. Comma separated list of: summary (similar summaries), property, source (the same source or ALARMS_SOURCE_GROUP_\u003cNAME\u003e), and severity.",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "summary",
+                        "description": "'cards' shows a card for each alarm, 'summary' shows a compact summary with the number of alarms of each source by severity, or 'All clear'. Defaults to cards.",
+                        "name": "layout",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "https://homarr.domain.com/board/alarms",
+                        "description": "In the summary layout, URL opened when the summary is clicked. Defaults to the alarms iFrame with the same query parameters.",
+                        "name": "link",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -364,6 +1447,165 @@ const docTemplate = `{
                 }
             }
         },
+        "/iframe/alarms/acknowledge": {
+            "post": {
+                "description": "Acknowledge an alarm, which is dimmed or hidden in the iFrame until it changes. Requires the action token of the alarm in the X-Action-Token header (embedded in the iFrame), the ACTIONS_SECRET as a bearer token, or the AUTH_TRUSTED_HEADER.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Acknowledge an alarm",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "3f2a9c1d8e7b6a50",
+                        "description": "The alarm fingerprint, from the data route.",
+                        "name": "fingerprint",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action token of the alarm.",
+                        "name": "X-Action-Token",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer ACTIONS_SECRET.",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Alarm acknowledged",
+                        "schema": {
+                            "$ref": "#/definitions/sources.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/sources.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Origin not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/sources.MessageResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove the acknowledgment or snooze of an alarm. Requires the action token of the alarm in the X-Action-Token header (embedded in the iFrame), the ACTIONS_SECRET as a bearer token, or the AUTH_TRUSTED_HEADER.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Remove the acknowledgment of an alarm",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "3f2a9c1d8e7b6a50",
+                        "description": "The alarm fingerprint, from the data route.",
+                        "name": "fingerprint",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action token of the alarm.",
+                        "name": "X-Action-Token",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer ACTIONS_SECRET.",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Alarm unacknowledged",
+                        "schema": {
+                            "$ref": "#/definitions/sources.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/sources.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Origin not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/sources.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/iframe/alarms/snooze": {
+            "post": {
+                "description": "Hide an alarm from the iFrame for a duration or until it changes. Requires the action token of the alarm in the X-Action-Token header (embedded in the iFrame), the ACTIONS_SECRET as a bearer token, or the AUTH_TRUSTED_HEADER.",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Snooze an alarm",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "3f2a9c1d8e7b6a50",
+                        "description": "The alarm fingerprint, from the data route.",
+                        "name": "fingerprint",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "1h",
+                        "description": "How long to snooze the alarm, like '1h' or '1d', or 'changed' to snooze it until it changes.",
+                        "name": "duration",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action token of the alarm.",
+                        "name": "X-Action-Token",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer ACTIONS_SECRET.",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Alarm snoozed",
+                        "schema": {
+                            "$ref": "#/definitions/sources.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/sources.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Origin not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/sources.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/iframe/cinemark": {
             "get": {
                 "description": "Returns an iFrame with the on display movies in specific Cinemark theaters. I recommend you to get the movies from the theaters of your city.",
@@ -397,7 +1639,7 @@ const docTemplate = `{
                     {
                         "type": "string",
                         "example": "https://sub.domain.com",
-                        "description": "API URL used by your browser. Use by the iFrames to check any update, if there is an update, the iFrame reloads. If not specified, the iFrames will never try to reload.",
+                        "description": "API URL used by your browser. Used by the iFrames to receive updates, if there is an update, the iFrame updates the changed items without reloading. If not specified, the iFrames will never try to reload.",
                         "name": "api_url",
                         "in": "query",
                         "required": true
@@ -445,7 +1687,7 @@ const docTemplate = `{
                     {
                         "type": "string",
                         "example": "https://sub.domain.com",
-                        "description": "API URL used by your browser. Use by the iFrames to check any update, if there is an update, the iFrame reloads. If not specified, the iFrames will never try to reload.",
+                        "description": "API URL used by your browser. Used by the iFrames to receive updates, if there is an update, the iFrame updates the changed items without reloading. If not specified, the iFrames will never try to reload.",
                         "name": "api_url",
                         "in": "query",
                         "required": true
@@ -491,7 +1733,7 @@ const docTemplate = `{
         },
         "/iframe/linkwarden/delete_link": {
             "delete": {
-                "description": "Deletes a Linkwarden bookmark. After deleting, the iFrame will reload if the ` + "`" + `api_url` + "`" + ` query parameter is provided.",
+                "description": "Deletes a Linkwarden bookmark. After deleting, the iFrame will reload if the ` + "`" + `api_url` + "`" + ` query parameter is provided. Requires the action token of the bookmark in the X-Action-Token header (embedded in the iFrame), the ACTIONS_SECRET as a bearer token, or the AUTH_TRUSTED_HEADER.",
                 "produces": [
                     "application/json"
                 ],
@@ -504,13 +1746,37 @@ const docTemplate = `{
                         "name": "linkId",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action token of the bookmark.",
+                        "name": "X-Action-Token",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer ACTIONS_SECRET.",
+                        "name": "Authorization",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Bookmark deleted",
                         "schema": {
-                            "$ref": "#/definitions/routes.messsageResponse"
+                            "$ref": "#/definitions/sources.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/sources.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Origin not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/sources.MessageResponse"
                         }
                     }
                 }
@@ -518,7 +1784,7 @@ const docTemplate = `{
         },
         "/iframe/media_releases": {
             "get": {
-                "description": "Returns an iFrame with the media releases of today. The media releases are from Radarr/Sonarr/Lidarr.",
+                "description": "Returns an iFrame with the media releases of today, or of a range of days. The media releases are from Radarr/Sonarr/Lidarr.",
                 "produces": [
                     "text/html"
                 ],
@@ -534,7 +1800,7 @@ const docTemplate = `{
                     {
                         "type": "string",
                         "example": "https://sub.domain.com",
-                        "description": "API URL used by your browser. Use by the iFrames to check any update, if there is an update, the iFrame reloads. If not specified, the iFrames will never try to reload. Also used by the button to set the task done, if not provided, the button will not appear.",
+                        "description": "API URL used by your browser. Used by the iFrames to receive updates, if there is an update, the iFrame updates the changed items without reloading. If not specified, the iFrames will never try to reload. Also used by the button to set the task done, if not provided, the button will not appear.",
                         "name": "api_url",
                         "in": "query",
                         "required": true
@@ -559,6 +1825,41 @@ const docTemplate = `{
                         "description": "Specify if show the episodes' (Sonarr) release hour and minute. Defaults to true.",
                         "name": "showEpisodesHour",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Number of days before today to show. Defaults to 0.",
+                        "name": "days_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 6,
+                        "description": "Number of days after today to show. Defaults to 0.",
+                        "name": "days_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-10-01",
+                        "description": "First day to show, like '2026-10-01'. Must be set with end, and can't be used with days_before and days_after.",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2026-10-31",
+                        "description": "Last day to show, like '2026-10-31'. The calendar can have at most 92 days.",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "grid",
+                        "description": "How to show the releases. Can be 'list' (a card per release), 'agenda' (the cards grouped by day), or 'grid' (a grid of days with the releases' posters, 7 days per row). Defaults to 'list'.",
+                        "name": "layout",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -589,7 +1890,7 @@ const docTemplate = `{
                     {
                         "type": "string",
                         "example": "https://sub.domain.com",
-                        "description": "API URL used by your browser. Use by the iFrames to check any update, if there is an update, the iFrame reloads. If not specified, the iFrames will never try to reload. Also used by the button to set the task done, if not provided, the button will not appear.",
+                        "description": "API URL used by your browser. Used by the iFrames to receive updates, if there is an update, the iFrame updates the changed items without reloading. If not specified, the iFrames will never try to reload. Also used by the button to set the task done, if not provided, the button will not appear.",
                         "name": "api_url",
                         "in": "query",
                         "required": true
@@ -752,7 +2053,7 @@ const docTemplate = `{
                     {
                         "type": "string",
                         "example": "https://sub.domain.com",
-                        "description": "API URL used by your browser. Use by the iFrames to check any update, if there is an update, the iFrame reloads. If not specified, the iFrames will never try to reload.",
+                        "description": "API URL used by your browser. Used by the iFrames to receive updates, if there is an update, the iFrame updates the changed items without reloading. If not specified, the iFrames will never try to reload.",
                         "name": "api_url",
                         "in": "query",
                         "required": true
@@ -821,7 +2122,7 @@ const docTemplate = `{
                     {
                         "type": "string",
                         "example": "https://sub.domain.com",
-                        "description": "API URL used by your browser. Use by the iFrames to check any update, if there is an update, the iFrame reloads. If not specified, the iFrames will never try to reload. Also used by the button to set the task done, if not provided, the button will not appear (the button doesn't appear in repeating tasks.)",
+                        "description": "API URL used by your browser. Used by the iFrames to receive updates, if there is an update, the iFrame updates the changed items without reloading. If not specified, the iFrames will never try to reload. Also used by the button to set the task done, if not provided, the button will not appear (the button doesn't appear in repeating tasks.)",
                         "name": "api_url",
                         "in": "query",
                         "required": true
@@ -902,7 +2203,7 @@ const docTemplate = `{
         },
         "/iframe/vikunja/set_task_done": {
             "patch": {
-                "description": "Set a Vikunja task as done.",
+                "description": "Set a Vikunja task as done. Requires the action token of the task in the X-Action-Token header (embedded in the iFrame), the ACTIONS_SECRET as a bearer token, or the AUTH_TRUSTED_HEADER.",
                 "produces": [
                     "application/json"
                 ],
@@ -915,13 +2216,67 @@ const docTemplate = `{
                         "name": "taskId",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action token of the task.",
+                        "name": "X-Action-Token",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer ACTIONS_SECRET.",
+                        "name": "Authorization",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Task done",
                         "schema": {
-                            "$ref": "#/definitions/routes.messsageResponse"
+                            "$ref": "#/definitions/sources.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/sources.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Origin not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/sources.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/status": {
+            "get": {
+                "description": "Checks every configured integration instance and returns if its config is complete, if it's reachable, if the credentials are valid, its version, and the request latency. Used to find out why an iFrame doesn't work. Requires the ACTIONS_SECRET as a bearer token or the AUTH_TRUSTED_HEADER.",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "summary": "Integrations status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "table",
+                        "description": "Response format, 'json' or 'table'. Defaults to json.",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/sources.Status"
+                            }
                         }
                     }
                 }
@@ -929,7 +2284,423 @@ const docTemplate = `{
         }
     },
     "definitions": {
-        "routes.hashResponse": {
+        "alarms.AlarmData": {
+            "type": "object",
+            "properties": {
+                "acknowledged": {
+                    "description": "Acknowledged is true if the alarm is acknowledged",
+                    "type": "boolean"
+                },
+                "duration_seconds": {
+                    "description": "DurationSeconds is how long the alarm is or was active. Omitted if the alarms history is disabled.",
+                    "type": "integer"
+                },
+                "fingerprint": {
+                    "description": "Fingerprint is used to acknowledge and snooze the alarm. Omitted in the resolved mode.",
+                    "type": "string"
+                },
+                "first_seen": {
+                    "description": "FirstSeen is when the alarm was first seen. Omitted if the alarms history is disabled.",
+                    "type": "string"
+                },
+                "group": {
+                    "description": "Group is the ID of the group of the alarm, with the group_by query parameter. Omitted if the alarm isn't grouped.",
+                    "type": "string"
+                },
+                "instance": {
+                    "description": "Instance is the source instance name, like \"4k\". Omitted for the default instance.",
+                    "type": "string"
+                },
+                "maintenance": {
+                    "description": "Maintenance is the name of the maintenance window the alarm is in. Omitted if it's not in one.",
+                    "type": "string"
+                },
+                "property": {
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "description": "ResolvedAt is when the alarm was resolved, only in the resolved mode",
+                    "type": "string"
+                },
+                "severity": {
+                    "description": "Severity is the normalized status: \"ok\", \"info\", \"warning\", \"error\", or \"critical\"",
+                    "type": "string",
+                    "enum": [
+                        "ok",
+                        "info",
+                        "warning",
+                        "error",
+                        "critical"
+                    ]
+                },
+                "source": {
+                    "description": "Source is like \"Netdata\" or \"Radarr\"",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is like \"CLEAR\", \"WARNING\", \"ERROR\", or \"CRITICAL\"",
+                    "type": "string"
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "time": {
+                    "description": "Time is omitted if the alarm doesn't have a time, like the alarm of an unreachable source",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "alarms.Data": {
+            "type": "object",
+            "properties": {
+                "alarms": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/alarms.AlarmData"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "alarms.IngestAlarm": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID identifies the alarm, so sending it again replaces it. Defaults to the source and summary.",
+                    "type": "string"
+                },
+                "property": {
+                    "type": "string"
+                },
+                "resolved": {
+                    "description": "Resolved removes the alarm",
+                    "type": "boolean"
+                },
+                "source": {
+                    "description": "Source defaults to the source query parameter, or \"Webhook\"",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is like \"WARNING\", \"ERROR\", or \"CRITICAL\". Defaults to \"WARNING\".",
+                    "type": "string"
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "ttl": {
+                    "description": "TTL is how long the alarm is shown if it's not sent again, like \"1h\". Defaults to ALARMS_INGEST_TTL.",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "alarms.IngestResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "received": {
+                    "description": "Received is the number of alarms added or replaced",
+                    "type": "integer"
+                },
+                "resolved": {
+                    "description": "Resolved is the number of alarms resolved",
+                    "type": "integer"
+                }
+            }
+        },
+        "cinemark.Data": {
+            "type": "object",
+            "properties": {
+                "movies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cinemark.MovieData"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cinemark.MovieData": {
+            "type": "object",
+            "properties": {
+                "age_rating": {
+                    "description": "AgeRating can be: L, 12, 14, 16, 18",
+                    "type": "string"
+                },
+                "cover_img_url": {
+                    "type": "string"
+                },
+                "genre": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "linkwarden.CollectionData": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "linkwarden.Data": {
+            "type": "object",
+            "properties": {
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/linkwarden.LinkData"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "linkwarden.LinkData": {
+            "type": "object",
+            "properties": {
+                "collection": {
+                    "$ref": "#/definitions/linkwarden.CollectionData"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "media.AlbumData": {
+            "type": "object",
+            "properties": {
+                "artist_name": {
+                    "type": "string"
+                },
+                "artist_url": {
+                    "type": "string"
+                },
+                "total_track_count": {
+                    "type": "integer"
+                },
+                "track_file_count": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "media.Data": {
+            "type": "object",
+            "properties": {
+                "releases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/media.ReleaseData"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "media.EpisodeData": {
+            "type": "object",
+            "properties": {
+                "episode_number": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "season_number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "media.ReleaseData": {
+            "type": "object",
+            "properties": {
+                "album": {
+                    "description": "Album is only set for Lidarr releases",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/media.AlbumData"
+                        }
+                    ]
+                },
+                "cover_image_url": {
+                    "type": "string"
+                },
+                "episode": {
+                    "description": "Episode is only set for Sonarr releases",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/media.EpisodeData"
+                        }
+                    ]
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "is_downloaded": {
+                    "type": "boolean"
+                },
+                "poster_image_url": {
+                    "type": "string"
+                },
+                "release_date": {
+                    "type": "string"
+                },
+                "should_be_downloaded": {
+                    "type": "boolean"
+                },
+                "source": {
+                    "description": "Source is \"Radarr\", \"Sonarr\", or \"Lidarr\"",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "mediarequets.Data": {
+            "type": "object",
+            "properties": {
+                "requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/mediarequets.RequestData"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "mediarequets.RequestData": {
+            "type": "object",
+            "properties": {
+                "media": {
+                    "$ref": "#/definitions/mediarequets.RequestMediaData"
+                },
+                "request": {
+                    "$ref": "#/definitions/mediarequets.RequestUserData"
+                },
+                "status": {
+                    "description": "Status is like \"Pending\", \"Available\", or \"Processing\"",
+                    "type": "string"
+                }
+            }
+        },
+        "mediarequets.RequestMediaData": {
+            "type": "object",
+            "properties": {
+                "backdrop_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "poster_url": {
+                    "type": "string"
+                },
+                "tmdb_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "Type is \"movie\" or \"tv\"",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "year": {
+                    "type": "string"
+                }
+            }
+        },
+        "mediarequets.RequestUserData": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "user_profile_url": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "routes.ReloadResponse": {
+            "type": "object",
+            "properties": {
+                "changed": {
+                    "description": "Changed are the names of the integrations whose configs changed",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "sources.HashResponse": {
             "type": "object",
             "properties": {
                 "hash": {
@@ -937,13 +2708,153 @@ const docTemplate = `{
                 }
             }
         },
-        "routes.messsageResponse": {
+        "sources.MessageResponse": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string"
                 }
             }
+        },
+        "sources.Status": {
+            "type": "object",
+            "properties": {
+                "authenticated": {
+                    "description": "Authenticated is true if an authenticated request worked, and nil if it wasn't checked,\nbecause the integration doesn't have a check or the instance isn't reachable",
+                    "type": "boolean"
+                },
+                "configured": {
+                    "description": "Configured is true if the required variables of the instance are set",
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "instance": {
+                    "description": "Instance is the instance name, \"\" for the default instance",
+                    "type": "string"
+                },
+                "integration": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "description": "LatencyMS is the round-trip time of the reachability request in milliseconds",
+                    "type": "integer"
+                },
+                "reachable": {
+                    "description": "Reachable is true if the address of the instance responded to a request",
+                    "type": "boolean"
+                },
+                "version": {
+                    "description": "Version is the version of the source, if the integration can get it",
+                    "type": "string"
+                }
+            }
+        },
+        "uptimekuma.Data": {
+            "type": "object",
+            "properties": {
+                "down": {
+                    "description": "Down is the number of sites down in the last heartbeat of the status page",
+                    "type": "integer"
+                },
+                "up": {
+                    "description": "Up is the number of sites up in the last heartbeat of the status page",
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "vikunja.Data": {
+            "type": "object",
+            "properties": {
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/vikunja.TaskData"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "vikunja.LabelData": {
+            "type": "object",
+            "properties": {
+                "hex_color": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "vikunja.ProjectData": {
+            "type": "object",
+            "properties": {
+                "hex_color": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "vikunja.TaskData": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "done": {
+                    "type": "boolean"
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_favorite": {
+                    "type": "boolean"
+                },
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/vikunja.LabelData"
+                    }
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "project": {
+                    "$ref": "#/definitions/vikunja.ProjectData"
+                },
+                "repeat_after": {
+                    "type": "integer"
+                },
+                "repeat_mode": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
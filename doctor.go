@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// doctor checks every configured integration and prints the result as a table, or as JSON with --json.
+// It returns 1 if any integration has a problem, so it can be used in scripts.
+func doctor(args []string) int {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the result as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	statuses := sources.Statuses()
+	if *asJSON {
+		if statuses == nil {
+			statuses = []sources.Status{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(statuses); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	} else {
+		if len(statuses) == 0 {
+			fmt.Println("No integration is configured.")
+			return 0
+		}
+		if err := sources.WriteStatusTable(os.Stdout, statuses); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	for _, status := range statuses {
+		if !status.OK() {
+			return 1
+		}
+	}
+
+	return 0
+}
@@ -2,10 +2,14 @@
 package main
 
 import (
+	"log/slog"
 	"os"
 
 	api "github.com/diogovalentte/homarr-iframes/src"
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/logging"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+	"github.com/diogovalentte/homarr-iframes/src/sources/alarms"
 )
 
 func init() {
@@ -16,11 +20,22 @@ func init() {
 	if err := config.SetConfigs(filePath); err != nil {
 		panic(err)
 	}
+	logging.Setup(config.Current().Log)
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "doctor" {
+		os.Exit(doctor(os.Args[2:]))
+	}
+
+	go sources.WatchConfigs()
+	go alarms.PollAlarms()
+
 	router := api.SetupRouter()
 	router.SetTrustedProxies(nil)
 
-	router.Run(":" + os.Getenv("PORT"))
+	if err := router.Run(":" + os.Getenv("PORT")); err != nil {
+		slog.Error("error running the API", "error", err)
+		os.Exit(1)
+	}
 }
@@ -12,7 +12,8 @@ import (
 
 // SetupRouter sets up the API routes
 func SetupRouter() *gin.Engine {
-	router := gin.Default()
+	router := gin.New()
+	router.Use(routes.RequestLogger(), gin.Recovery())
 
 	docs.SwaggerInfo.Title = "Homarr iFrames API"
 	docs.SwaggerInfo.Description = "iFrames of many applications to use in Homarr"
@@ -22,6 +23,7 @@ func SetupRouter() *gin.Engine {
 	v1 := router.Group("/v1")
 	{
 		routes.HealthCheckRoute(v1)
+		routes.StatusRoute(v1)
 	}
 	{
 		routes.IFrameRoutes(v1)
@@ -29,6 +31,29 @@ func SetupRouter() *gin.Engine {
 	{
 		routes.HashRoutes(v1)
 	}
+	{
+		routes.DataRoutes(v1)
+	}
+	{
+		routes.EventsRoutes(v1)
+	}
+	{
+		routes.AdminRoutes(v1)
+	}
+	{
+		routes.AlarmsRoutes(v1)
+	}
+	{
+		routes.FeedRoutes(v1)
+	}
+	{
+		routes.BadgeRoutes(v1)
+	}
+	{
+		routes.CalendarRoutes(v1)
+	}
+
+	routes.MetricsRoute(router)
 
 	v1.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 
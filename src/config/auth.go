@@ -0,0 +1,108 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// minActionsSecretLength is the minimum length of ACTIONS_SECRET
+const minActionsSecretLength = 16
+
+// AuthConfigs are the configs of the authentication of the action routes, like deleting a Linkwarden bookmark
+type AuthConfigs struct {
+	// ActionsSecret signs the action tokens embedded in the iFrames, and can be used
+	// as a bearer token by other clients. If not set, a random one is generated.
+	ActionsSecret string
+	// TrustedHeader is a header set by an authentication proxy, like "Remote-User".
+	// Requests from the TrustedProxies with this header are authenticated.
+	TrustedHeader string
+	// TrustedProxies are the addresses of the reverse proxies, like the authentication proxy.
+	// Their TrustedHeader and X-Forwarded-For headers are trusted.
+	TrustedProxies []*net.IPNet
+	// Required is true if the read routes (iFrames, events, hashes, and data) require authentication too
+	Required bool
+	// AllowedOrigins are origins allowed to request the action routes besides the API's own origin,
+	// like "https://homarr.domain.com"
+	AllowedOrigins []string
+	// generatedSecret is true if ActionsSecret was generated, because ACTIONS_SECRET is not set
+	generatedSecret bool
+}
+
+func loadAuthConfigs(getenv func(string) string) (AuthConfigs, error) {
+	authConfigs := AuthConfigs{
+		ActionsSecret: getenv("ACTIONS_SECRET"),
+		TrustedHeader: getenv("AUTH_TRUSTED_HEADER"),
+	}
+
+	if authConfigs.ActionsSecret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return authConfigs, fmt.Errorf("error generating actions secret: %w", err)
+		}
+		authConfigs.ActionsSecret = hex.EncodeToString(secret)
+		authConfigs.generatedSecret = true
+	} else if len(authConfigs.ActionsSecret) < minActionsSecretLength {
+		return authConfigs, fmt.Errorf("ACTIONS_SECRET must have at least %d characters", minActionsSecretLength)
+	}
+
+	if trustedProxies := getenv("AUTH_TRUSTED_PROXIES"); trustedProxies != "" {
+		for _, proxy := range splitList(trustedProxies) {
+			if !strings.Contains(proxy, "/") {
+				if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+					proxy += "/32"
+				} else {
+					proxy += "/128"
+				}
+			}
+			_, network, err := net.ParseCIDR(proxy)
+			if err != nil {
+				return authConfigs, fmt.Errorf("AUTH_TRUSTED_PROXIES must be a comma separated list of IP addresses or CIDRs, like '172.18.0.2, 10.0.0.0/8', invalid address: %s", proxy)
+			}
+			authConfigs.TrustedProxies = append(authConfigs.TrustedProxies, network)
+		}
+	}
+	if authConfigs.TrustedHeader != "" && len(authConfigs.TrustedProxies) == 0 {
+		return authConfigs, fmt.Errorf("AUTH_TRUSTED_PROXIES must be set with AUTH_TRUSTED_HEADER, otherwise any client could send the header")
+	}
+
+	if required := getenv("AUTH_REQUIRED"); required != "" {
+		value, err := strconv.ParseBool(required)
+		if err != nil {
+			return authConfigs, fmt.Errorf("AUTH_REQUIRED must be 'true' or 'false'")
+		}
+		authConfigs.Required = value
+	}
+
+	if allowedOrigins := getenv("ACTIONS_ALLOWED_ORIGINS"); allowedOrigins != "" {
+		for _, origin := range strings.Split(allowedOrigins, ",") {
+			origin = strings.TrimSuffix(strings.TrimSpace(origin), "/")
+			u, err := url.Parse(origin)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" {
+				return authConfigs, fmt.Errorf("ACTIONS_ALLOWED_ORIGINS must be a comma separated list of origins, like 'https://homarr.domain.com', invalid origin: %s", origin)
+			}
+			authConfigs.AllowedOrigins = append(authConfigs.AllowedOrigins, origin)
+		}
+	}
+
+	return authConfigs, nil
+}
+
+// TrustedProxy returns true if the IP address is one of the TrustedProxies
+func (a AuthConfigs) TrustedProxy(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range a.TrustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
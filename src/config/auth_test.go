@@ -0,0 +1,44 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAuthConfigs(t *testing.T) {
+	env := map[string]string{
+		"ACTIONS_SECRET":       "test-actions-secret",
+		"AUTH_TRUSTED_HEADER":  "Remote-User",
+		"AUTH_TRUSTED_PROXIES": "172.18.0.2, 10.0.0.0/8, fd00::1",
+		"AUTH_REQUIRED":        "true",
+	}
+	getenv := func(key string) string { return env[key] }
+	authConfigs, err := loadAuthConfigs(getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !authConfigs.Required || len(authConfigs.TrustedProxies) != 3 {
+		t.Errorf("unexpected auth configs: %+v", authConfigs)
+	}
+	for address, expected := range map[string]bool{
+		"172.18.0.2": true,
+		"172.18.0.3": false,
+		"10.1.2.3":   true,
+		"fd00::1":    true,
+		"fd00::2":    false,
+		"invalid":    false,
+	} {
+		if actual := authConfigs.TrustedProxy(address); actual != expected {
+			t.Errorf("TrustedProxy(%s): expected %v, got %v", address, expected, actual)
+		}
+	}
+
+	env["AUTH_TRUSTED_PROXIES"] = ""
+	if _, err := loadAuthConfigs(getenv); err == nil || !strings.Contains(err.Error(), "AUTH_TRUSTED_PROXIES") {
+		t.Errorf("expected an error about the missing AUTH_TRUSTED_PROXIES, got %v", err)
+	}
+	env["AUTH_TRUSTED_PROXIES"] = "proxy.local"
+	if _, err := loadAuthConfigs(getenv); err == nil {
+		t.Error("expected an error about the invalid proxy address")
+	}
+}
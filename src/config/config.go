@@ -2,249 +2,295 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
+	"sync/atomic"
+	"time"
 
-	"github.com/joho/godotenv"
+	"github.com/diogovalentte/homarr-iframes/src/filter"
 )
 
 var (
-	GlobalConfigs                            *Configs
-	defaultChangeDetectionIOChangedLastHours = 24
-	DefaultBackgroundImageURL                = "https://i.imgur.com/jMy7evE.jpeg"
+	current                       atomic.Pointer[Configs]
+	DefaultBackgroundImageURL     = "https://i.imgur.com/jMy7evE.jpeg"
+	defaultAlarmsSourceTimeout    = 10 * time.Second
+	defaultCacheRefreshInterval   = 30 * time.Second
+	defaultAlarmsPollInterval     = time.Minute
+	defaultAlarmsHistoryRetention = 30 * 24 * time.Hour
+	defaultAlarmsIngestTTL        = 24 * time.Hour
+	schemas                       = map[string]Schema{}
+	registeredWidgets             = map[string]bool{}
 )
 
 type Configs struct {
-	Linkwarden              linkwardenConfigs
-	Vikunja                 vikunjaConfigs
-	Overseerr               overseerrConfigs
-	Sonarr                  sonarrConfigs
-	Radarr                  radarrConfigs
-	Lidarr                  lidarrConfigs
-	Prowlarr                prowlarrConfigs
-	UptimeKumaConfigs       uptimeKumaConfigs
-	NetdataConfigs          netdataConfigs
-	SpeedTestTrackerConfigs speedTestTrackerConfigs
-	Pihole                  piholeConfigs
-	Kavita                  kavitaConfigs
-	Kaizoku                 kaizokuConfigs
-	Jellyseerr              jellyseerrConfigs
-	ChangeDetectionIO       changedetectionIOConfigs
-	Backrest                BackrestConfigs
-	OpenArchiver            OpenArchiverConfigs
-	IFrames                 iframesConfigs
+	// Sources has the configs of each instance of the registered sources, by source name and instance name.
+	// The default instance name is "".
+	Sources map[string]map[string]SourceConfigs
+	IFrames iframesConfigs
+	HTTP    HTTPConfigs
+	Auth    AuthConfigs
+	Log     LogConfigs
+	// Notifiers are the targets where the new and resolved alarms are sent, sorted by name
+	Notifiers []NotifierConfigs
+	// Maintenance are the windows when some alarms are expected, sorted by name
+	Maintenance []MaintenanceWindow
+	// Widgets are the default query parameters of the iFrames set in the config file, by iFrame name
+	Widgets map[string]url.Values
+	// secrets are the values of the secret variables of the sources, redacted from the logs and error messages
+	secrets []string
 }
 
 type iframesConfigs struct {
 	AlarmsRegex *regexp.Regexp
+	// AlarmsFilter is the filter expression of every alarms iFrame, like `NOT source=Pi-hole`. Nil shows every alarm.
+	AlarmsFilter *filter.Expr
+	// AlarmsFilterPresets are the named filter expressions used with the filter_preset query parameter, by lowercase name
+	AlarmsFilterPresets map[string]*filter.Expr
+	// AlarmsSourceGroups are the sources of each group used to group the alarms, like "arr" with sonarr and radarr, by lowercase name
+	AlarmsSourceGroups map[string][]string
+	// AlarmsSourceTimeout is how long the alarms iFrame waits for each source
+	AlarmsSourceTimeout time.Duration
+	// CacheRefreshInterval is how often the cached iFrames data is refreshed. Zero disables the cache.
+	CacheRefreshInterval time.Duration
+	// AlarmsHistoryFile is the file where the alarms history is stored. Empty disables the history.
+	AlarmsHistoryFile string
+	// AlarmsHistoryRetention is how long the resolved alarms are kept in the history
+	AlarmsHistoryRetention time.Duration
+	// AlarmsPollInterval is how often the alarms of every configured source are got in the background,
+	// so the history is updated even when no alarms iFrame is open. Zero disables it.
+	AlarmsPollInterval time.Duration
+	// AlarmsIngestTTL is how long an alarm received by the ingest route is shown if it's not sent again or resolved
+	AlarmsIngestTTL time.Duration
 }
 
-type linkwardenConfigs struct {
-	Address          string
-	InternalAddress  string
-	Token            string
-	BackgroundImgURL string
+// Source returns the configs of the default instance of a source. It never returns nil.
+func (c *Configs) Source(name string) SourceConfigs {
+	return c.Instance(name, "")
 }
 
-type vikunjaConfigs struct {
-	Address          string
-	InternalAddress  string
-	Token            string
-	BackgroundImgURL string
-}
+// Instance returns the configs of an instance of a source. It never returns nil.
+func (c *Configs) Instance(name, instance string) SourceConfigs {
+	if sourceConfigs, ok := c.Sources[name][instance]; ok {
+		return sourceConfigs
+	}
 
-type overseerrConfigs struct {
-	Address         string
-	InternalAddress string
-	APIKey          string
+	return SourceConfigs{}
 }
 
-type sonarrConfigs struct {
-	Address         string
-	InternalAddress string
-	APIKey          string
-}
+// Instances returns the names of the configured instances of a source, sorted.
+// The default instance ("") is always the first one.
+func (c *Configs) Instances(name string) []string {
+	instances := []string{""}
+	for instance := range c.Sources[name] {
+		if instance != "" {
+			instances = append(instances, instance)
+		}
+	}
+	sort.Strings(instances)
 
-type radarrConfigs struct {
-	Address         string
-	InternalAddress string
-	APIKey          string
+	return instances
 }
 
-type lidarrConfigs struct {
-	Address         string
-	InternalAddress string
-	APIKey          string
+// LoadSource returns the configs of the default instance of a source and checks if its required variables are set
+func (c *Configs) LoadSource(name string) (SourceConfigs, error) {
+	return c.LoadInstance(name, "")
 }
 
-type prowlarrConfigs struct {
-	Address         string
-	InternalAddress string
-	APIKey          string
-}
+// LoadInstance returns the configs of an instance of a source and checks if its required variables are set
+func (c *Configs) LoadInstance(name, instance string) (SourceConfigs, error) {
+	sourceConfigs := c.Instance(name, instance)
+	if err := schemas[name].ForInstance(instance).CheckRequired(sourceConfigs); err != nil {
+		return nil, err
+	}
 
-type uptimeKumaConfigs struct {
-	Address         string
-	InternalAddress string
+	return sourceConfigs, nil
 }
 
-type netdataConfigs struct {
-	Address         string
-	InternalAddress string
-	Token           string
+// WidgetDefaults returns the default query parameters of an iFrame, used when the request doesn't have them
+func (c *Configs) WidgetDefaults(name string) url.Values {
+	return c.Widgets[name]
 }
 
-type speedTestTrackerConfigs struct {
-	Address         string
-	InternalAddress string
-	Token           string
+// RegisterSchema registers the configuration schema of a source.
+// Should be called before SetConfigs, usually by the source package init function.
+// The HTTP variables, like <PREFIX>_TIMEOUT, are added to the schema.
+func RegisterSchema(name string, schema Schema) {
+	if _, exists := schemas[name]; exists {
+		panic("config: schema already registered for source " + name)
+	}
+	schema.Vars = append(append([]Var{}, schema.Vars...), httpVars...)
+	schemas[name] = schema
 }
 
-type piholeConfigs struct {
-	Address         string
-	InternalAddress string
-	Token           string // <v6.0
-	Password        string
+// RegisterWidget registers the name of an iFrame, so it can have default query parameters in the config file.
+// Should be called before SetConfigs.
+func RegisterWidget(name string) {
+	registeredWidgets[name] = true
 }
 
-type kavitaConfigs struct {
-	Address         string
-	InternalAddress string
-	Username        string
-	Password        string
+// GetSchema returns the configuration schema of a source.
+func GetSchema(name string) (Schema, bool) {
+	schema, ok := schemas[name]
+	return schema, ok
 }
 
-type kaizokuConfigs struct {
-	Address         string
-	InternalAddress string
+// Current returns the current configs. The configs are replaced when they are reloaded,
+// so they should be got again for each operation, instead of being stored.
+func Current() *Configs {
+	return current.Load()
 }
 
-type jellyseerrConfigs struct {
-	Address         string
-	InternalAddress string
-	APIKey          string
+// Set replaces the current configs, like in tests
+func Set(configs *Configs) {
+	current.Store(configs)
 }
 
-type changedetectionIOConfigs struct {
-	Address          string
-	InternalAddress  string
-	APIKey           string
-	ChangedLastHours int
-}
+// SetConfigs loads the configs from the environment variables and the config file set in CONFIG_FILE.
+// If filePath isn't empty, the .env file is loaded first. The environment variables override the config file.
+func SetConfigs(filePath string) error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
 
-type BackrestConfigs struct {
-	Address         string
-	InternalAddress string
-	Username        string
-	Password        string
-}
+	envFilePath = filePath
+	envFileVars = map[string]bool{}
+	if err := loadEnvFile(); err != nil {
+		return err
+	}
+
+	configs, err := loadConfigs()
+	if err != nil {
+		return err
+	}
+	current.Store(configs)
 
-type OpenArchiverConfigs struct {
-	Address         string
-	InternalAddress string
-	SuperAPIKey     string
+	return nil
 }
 
-func SetConfigs(filePath string) error {
-	GlobalConfigs = &Configs{}
+// loadConfigs loads the configs from the environment variables and the config file set in CONFIG_FILE
+func loadConfigs() (*Configs, error) {
+	configs := &Configs{
+		Sources: map[string]map[string]SourceConfigs{},
+	}
 
-	var err error
-	if filePath != "" {
-		err = godotenv.Load(filePath)
-		if err != nil {
-			return err
-		}
+	file, err := readConfigFile(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		return nil, err
 	}
+	getenv := file.getenv
+	configs.Widgets = file.widgets
 
-	GlobalConfigs.Linkwarden.Address = os.Getenv("LINKWARDEN_ADDRESS")
-	GlobalConfigs.Linkwarden.InternalAddress = os.Getenv("INTERNAL_LINKWARDEN_ADDRESS")
-	GlobalConfigs.Linkwarden.Token = os.Getenv("LINKWARDEN_TOKEN")
-	GlobalConfigs.Linkwarden.BackgroundImgURL = os.Getenv("LINKWARDEN_BACKGROUND_IMG_URL")
-
-	GlobalConfigs.Vikunja.Address = os.Getenv("VIKUNJA_ADDRESS")
-	GlobalConfigs.Vikunja.InternalAddress = os.Getenv("INTERNAL_VIKUNJA_ADDRESS")
-	GlobalConfigs.Vikunja.Token = os.Getenv("VIKUNJA_TOKEN")
-	GlobalConfigs.Vikunja.BackgroundImgURL = os.Getenv("VIKUNJA_BACKGROUND_IMG_URL")
-
-	GlobalConfigs.Overseerr.Address = os.Getenv("OVERSEERR_ADDRESS")
-	GlobalConfigs.Overseerr.InternalAddress = os.Getenv("INTERNAL_OVERSEERR_ADDRESS")
-	GlobalConfigs.Overseerr.APIKey = os.Getenv("OVERSEERR_API_KEY")
-
-	GlobalConfigs.Jellyseerr.Address = os.Getenv("JELLYSEERR_ADDRESS")
-	GlobalConfigs.Jellyseerr.InternalAddress = os.Getenv("INTERNAL_JELLYSEERR_ADDRESS")
-	GlobalConfigs.Jellyseerr.APIKey = os.Getenv("JELLYSEERR_API_KEY")
-
-	GlobalConfigs.Sonarr.Address = os.Getenv("SONARR_ADDRESS")
-	GlobalConfigs.Sonarr.InternalAddress = os.Getenv("INTERNAL_SONARR_ADDRESS")
-	GlobalConfigs.Sonarr.APIKey = os.Getenv("SONARR_API_KEY")
-
-	GlobalConfigs.Radarr.Address = os.Getenv("RADARR_ADDRESS")
-	GlobalConfigs.Radarr.InternalAddress = os.Getenv("INTERNAL_RADARR_ADDRESS")
-	GlobalConfigs.Radarr.APIKey = os.Getenv("RADARR_API_KEY")
-
-	GlobalConfigs.Lidarr.Address = os.Getenv("LIDARR_ADDRESS")
-	GlobalConfigs.Lidarr.InternalAddress = os.Getenv("INTERNAL_LIDARR_ADDRESS")
-	GlobalConfigs.Lidarr.APIKey = os.Getenv("LIDARR_API_KEY")
-
-	GlobalConfigs.Prowlarr.Address = os.Getenv("PROWLARR_ADDRESS")
-	GlobalConfigs.Prowlarr.InternalAddress = os.Getenv("INTERNAL_PROWLARR_ADDRESS")
-	GlobalConfigs.Prowlarr.APIKey = os.Getenv("PROWLARR_API_KEY")
-
-	GlobalConfigs.UptimeKumaConfigs.Address = os.Getenv("UPTIMEKUMA_ADDRESS")
-	GlobalConfigs.UptimeKumaConfigs.InternalAddress = os.Getenv("INTERNAL_UPTIMEKUMA_ADDRESS")
-
-	GlobalConfigs.NetdataConfigs.Address = os.Getenv("NETDATA_ADDRESS")
-	GlobalConfigs.NetdataConfigs.InternalAddress = os.Getenv("INTERNAL_NETDATA_ADDRESS")
-	GlobalConfigs.NetdataConfigs.Token = os.Getenv("NETDATA_TOKEN")
-
-	GlobalConfigs.SpeedTestTrackerConfigs.Address = os.Getenv("SPEEDTEST_TRACKER_ADDRESS")
-	GlobalConfigs.SpeedTestTrackerConfigs.InternalAddress = os.Getenv("INTERNAL_SPEEDTEST_TRACKER_ADDRESS")
-	GlobalConfigs.SpeedTestTrackerConfigs.Token = os.Getenv("SPEEDTEST_TRACKER_TOKEN")
-
-	GlobalConfigs.Pihole.Address = os.Getenv("PIHOLE_ADDRESS")
-	GlobalConfigs.Pihole.InternalAddress = os.Getenv("INTERNAL_PIHOLE_ADDRESS")
-	GlobalConfigs.Pihole.Token = os.Getenv("PIHOLE_TOKEN")
-	GlobalConfigs.Pihole.Password = os.Getenv("PIHOLE_PASSWORD")
-
-	GlobalConfigs.Kavita.Address = os.Getenv("KAVITA_ADDRESS")
-	GlobalConfigs.Kavita.InternalAddress = os.Getenv("INTERNAL_KAVITA_ADDRESS")
-	GlobalConfigs.Kavita.Username = os.Getenv("KAVITA_USERNAME")
-	GlobalConfigs.Kavita.Password = os.Getenv("KAVITA_PASSWORD")
-
-	GlobalConfigs.Kaizoku.Address = os.Getenv("KAIZOKU_ADDRESS")
-	GlobalConfigs.Kaizoku.InternalAddress = os.Getenv("INTERNAL_KAIZOKU_ADDRESS")
-
-	GlobalConfigs.ChangeDetectionIO.Address = os.Getenv("CHANGEDETECTIONIO_ADDRESS")
-	GlobalConfigs.ChangeDetectionIO.InternalAddress = os.Getenv("INTERNAL_CHANGEDETECTIONIO_ADDRESS")
-	GlobalConfigs.ChangeDetectionIO.APIKey = os.Getenv("CHANGEDETECTIONIO_API_KEY")
-	changedLastHours := os.Getenv("CHANGEDETECTIONIO_CHANGED_LAST_HOURS")
-	if changedLastHours == "" {
-		GlobalConfigs.ChangeDetectionIO.ChangedLastHours = defaultChangeDetectionIOChangedLastHours
-	} else {
-		GlobalConfigs.ChangeDetectionIO.ChangedLastHours, err = strconv.Atoi(changedLastHours)
-		if err != nil {
-			return err
-		}
+	configs.HTTP, err = loadHTTPConfigs(getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	configs.Auth, err = loadAuthConfigs(getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	configs.Log, err = loadLogConfigs(getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	configs.Notifiers, err = loadNotifierConfigs(getenv, file.environ())
+	if err != nil {
+		return nil, err
+	}
+
+	configs.Maintenance, err = loadMaintenanceWindows(getenv, file.environ())
+	if err != nil {
+		return nil, err
 	}
 
-	GlobalConfigs.Backrest.Address = os.Getenv("BACKREST_ADDRESS")
-	GlobalConfigs.Backrest.InternalAddress = os.Getenv("INTERNAL_BACKREST_ADDRESS")
-	GlobalConfigs.Backrest.Username = os.Getenv("BACKREST_USERNAME")
-	GlobalConfigs.Backrest.Password = os.Getenv("BACKREST_PASSWORD")
+	for name, schema := range schemas {
+		instances := []string{""}
+		if schema.MultiInstance {
+			instances = append(instances, schema.instanceNames(file.environ())...)
+		}
 
-	GlobalConfigs.OpenArchiver.Address = os.Getenv("OPENARCHIVER_ADDRESS")
-	GlobalConfigs.OpenArchiver.InternalAddress = os.Getenv("INTERNAL_OPENARCHIVER_ADDRESS")
-	GlobalConfigs.OpenArchiver.SuperAPIKey = os.Getenv("OPENARCHIVER_SUPER_API_KEY")
+		configs.Sources[name] = map[string]SourceConfigs{}
+		for _, instance := range instances {
+			sourceConfigs, err := schema.ForInstance(instance).load(getenv)
+			if err != nil {
+				return nil, err
+			}
+			configs.Sources[name][instance] = sourceConfigs
+		}
+	}
+	if err := file.checkInstances(configs.Sources); err != nil {
+		return nil, err
+	}
+	configs.secrets = configs.collectSecrets()
 
-	alarmsRegex := os.Getenv("ALARMS_REGEX")
+	alarmsRegex := getenv("ALARMS_REGEX")
 	if alarmsRegex != "" {
 		re, err := regexp.Compile(alarmsRegex)
 		if err != nil {
-			return fmt.Errorf("ALARMS_REGEX must be a valid regex: %w", err)
+			return nil, fmt.Errorf("ALARMS_REGEX must be a valid regex: %w", err)
 		}
-		GlobalConfigs.IFrames.AlarmsRegex = re
+		configs.IFrames.AlarmsRegex = re
 	}
 
-	return nil
+	configs.IFrames.AlarmsFilter, configs.IFrames.AlarmsFilterPresets, err = loadAlarmFilters(getenv, file.environ())
+	if err != nil {
+		return nil, err
+	}
+
+	configs.IFrames.AlarmsSourceGroups = loadSourceGroups(getenv, file.environ())
+
+	configs.IFrames.AlarmsSourceTimeout = defaultAlarmsSourceTimeout
+	alarmsSourceTimeout := getenv("ALARMS_SOURCE_TIMEOUT")
+	if alarmsSourceTimeout != "" {
+		timeout, err := time.ParseDuration(alarmsSourceTimeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("ALARMS_SOURCE_TIMEOUT must be a positive duration, like '10s'")
+		}
+		configs.IFrames.AlarmsSourceTimeout = timeout
+	}
+
+	configs.IFrames.CacheRefreshInterval = defaultCacheRefreshInterval
+	cacheRefreshInterval := getenv("CACHE_REFRESH_INTERVAL")
+	if cacheRefreshInterval != "" {
+		interval, err := time.ParseDuration(cacheRefreshInterval)
+		if err != nil || interval < 0 {
+			return nil, fmt.Errorf("CACHE_REFRESH_INTERVAL must be a duration, like '30s', or '0' to disable the cache")
+		}
+		configs.IFrames.CacheRefreshInterval = interval
+	}
+
+	configs.IFrames.AlarmsHistoryFile = getenv("ALARMS_HISTORY_FILE")
+	configs.IFrames.AlarmsHistoryRetention = defaultAlarmsHistoryRetention
+	if alarmsHistoryDays := getenv("ALARMS_HISTORY_DAYS"); alarmsHistoryDays != "" {
+		days, err := strconv.Atoi(alarmsHistoryDays)
+		if err != nil || days <= 0 {
+			return nil, fmt.Errorf("ALARMS_HISTORY_DAYS must be a positive integer")
+		}
+		configs.IFrames.AlarmsHistoryRetention = time.Duration(days) * 24 * time.Hour
+	}
+
+	configs.IFrames.AlarmsPollInterval = defaultAlarmsPollInterval
+	alarmsPollInterval := getenv("ALARMS_POLL_INTERVAL")
+	if alarmsPollInterval != "" {
+		interval, err := time.ParseDuration(alarmsPollInterval)
+		if err != nil || interval < 0 {
+			return nil, fmt.Errorf("ALARMS_POLL_INTERVAL must be a duration, like '1m', or '0' to disable it")
+		}
+		configs.IFrames.AlarmsPollInterval = interval
+	}
+
+	configs.IFrames.AlarmsIngestTTL = defaultAlarmsIngestTTL
+	if alarmsIngestTTL := getenv("ALARMS_INGEST_TTL"); alarmsIngestTTL != "" {
+		ttl, err := time.ParseDuration(alarmsIngestTTL)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("ALARMS_INGEST_TTL must be a positive duration, like '24h'")
+		}
+		configs.IFrames.AlarmsIngestTTL = ttl
+	}
+
+	return configs, nil
 }
@@ -0,0 +1,359 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// fileSections maps the keys of the config file sections, besides "sources", "notifiers", "alarms_maintenance", "alarms_filter_presets", "alarms_source_groups", and "widgets",
+// to the environment variables they set
+var fileSections = map[string]map[string]string{
+	"http": {
+		"timeout":              "HTTP_TIMEOUT",
+		"ca_file":              "HTTP_CA_FILE",
+		"insecure_skip_verify": "HTTP_INSECURE_SKIP_VERIFY",
+		"proxy_url":            "HTTP_PROXY_URL",
+		"retries":              "HTTP_RETRIES",
+		"retry_backoff":        "HTTP_RETRY_BACKOFF",
+	},
+	"auth": {
+		"actions_secret":  "ACTIONS_SECRET",
+		"trusted_header":  "AUTH_TRUSTED_HEADER",
+		"trusted_proxies": "AUTH_TRUSTED_PROXIES",
+		"required":        "AUTH_REQUIRED",
+		"allowed_origins": "ACTIONS_ALLOWED_ORIGINS",
+	},
+	"log": {
+		"level":  "LOG_LEVEL",
+		"format": "LOG_FORMAT",
+	},
+	"iframes": {
+		"alarms_regex":           "ALARMS_REGEX",
+		"alarms_filter":          "ALARMS_FILTER",
+		"alarms_source_timeout":  "ALARMS_SOURCE_TIMEOUT",
+		"cache_refresh_interval": "CACHE_REFRESH_INTERVAL",
+		"alarms_history_file":    "ALARMS_HISTORY_FILE",
+		"alarms_history_days":    "ALARMS_HISTORY_DAYS",
+		"alarms_poll_interval":   "ALARMS_POLL_INTERVAL",
+		"alarms_ingest_ttl":      "ALARMS_INGEST_TTL",
+	},
+}
+
+// listVars are the variables that can be a list in the config file, joined with commas
+var listVars = map[string]bool{
+	"ACTIONS_ALLOWED_ORIGINS": true,
+	"AUTH_TRUSTED_PROXIES":    true,
+}
+
+// fileConfigs are the configs read from the YAML or TOML config file set in CONFIG_FILE.
+// The environment variables override the file values.
+type fileConfigs struct {
+	path string
+	// vars are the file values by environment variable name, like "RADARR_4K_API_KEY"
+	vars map[string]string
+	// instances are the source instances in the file, by source name. Their required
+	// variables are checked when the configs are loaded, not only when they are used.
+	instances map[string][]string
+	// widgets are the default query parameters of the iFrames, by iFrame name
+	widgets map[string]url.Values
+}
+
+// getenv returns the value of an environment variable, or the file value if it's not set or empty
+func (f *fileConfigs) getenv(name string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+
+	return f.vars[name]
+}
+
+// environ returns the environment variables with the file values, used to find the instances of the sources
+func (f *fileConfigs) environ() []string {
+	environ := os.Environ()
+	for name, value := range f.vars {
+		environ = append(environ, name+"="+value)
+	}
+
+	return environ
+}
+
+// readConfigFile reads a config file. The format is chosen by the extension: .yaml, .yml, or .toml.
+// If path is empty, it returns empty configs.
+func readConfigFile(path string) (*fileConfigs, error) {
+	f := &fileConfigs{
+		path:      path,
+		vars:      map[string]string{},
+		instances: map[string][]string{},
+		widgets:   map[string]url.Values{},
+	}
+	if path == "" {
+		return f, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	var values map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &values)
+	case ".toml":
+		err = toml.Unmarshal(content, &values)
+	default:
+		return nil, fmt.Errorf("config file %s must have the .yaml, .yml, or .toml extension", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+
+	for _, key := range sortedKeys(values) {
+		section, err := f.table(values[key], key)
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case "sources":
+			err = f.parseSources(section)
+		case "notifiers":
+			err = f.parseNamedTables(section, "notifiers", "notifier", notifierKeys, notifierEnvName)
+		case "alarms_maintenance":
+			err = f.parseNamedTables(section, "alarms_maintenance", "maintenance window", maintenanceKeys, maintenanceEnvName)
+		case "alarms_filter_presets":
+			err = f.parseNamedVars(section, "alarms_filter_presets", filterPresetPrefix)
+		case "alarms_source_groups":
+			err = f.parseNamedVars(section, "alarms_source_groups", sourceGroupPrefix)
+		case "widgets":
+			err = f.parseWidgets(section)
+		default:
+			vars, ok := fileSections[key]
+			if !ok {
+				return nil, f.unknownKey(key, append(sortedKeys(fileSections), "alarms_filter_presets", "alarms_maintenance", "alarms_source_groups", "notifiers", "sources", "widgets"))
+			}
+			err = f.parseVars(section, key, vars)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+func (f *fileConfigs) parseSources(sources map[string]any) error {
+	for _, name := range sortedKeys(sources) {
+		schema, ok := schemas[name]
+		if !ok {
+			return f.unknownKey("sources."+name, sortedKeys(schemas))
+		}
+		source, err := f.table(sources[name], "sources."+name)
+		if err != nil {
+			return err
+		}
+
+		vars := map[string]string{}
+		for _, v := range schema.Vars {
+			vars[strings.ToLower(v.Key)] = schema.EnvName(v.Key)
+		}
+		instances := source["instances"]
+		delete(source, "instances")
+		if err := f.parseVars(source, "sources."+name, vars); err != nil {
+			return err
+		}
+		if len(source) > 0 {
+			f.instances[name] = append(f.instances[name], "")
+		}
+
+		if instances == nil {
+			continue
+		}
+		if !schema.MultiInstance {
+			return fmt.Errorf("config file %s: sources.%s doesn't support instances", f.path, name)
+		}
+		instancesTable, err := f.table(instances, "sources."+name+".instances")
+		if err != nil {
+			return err
+		}
+		for _, instanceKey := range sortedKeys(instancesTable) {
+			key := "sources." + name + ".instances." + instanceKey
+			instance := strings.ToLower(instanceKey)
+			if !isInstanceName(strings.ToUpper(instance)) {
+				return fmt.Errorf("config file %s: %s: instance names can only have letters and numbers", f.path, key)
+			}
+			instanceTable, err := f.table(instancesTable[instanceKey], key)
+			if err != nil {
+				return err
+			}
+			instanceSchema := schema.ForInstance(instance)
+			vars := map[string]string{}
+			for _, v := range instanceSchema.Vars {
+				vars[strings.ToLower(v.Key)] = instanceSchema.EnvName(v.Key)
+			}
+			if err := f.parseVars(instanceTable, key, vars); err != nil {
+				return err
+			}
+			f.instances[name] = append(f.instances[name], instance)
+		}
+	}
+
+	return nil
+}
+
+// parseNamedTables parses a section whose keys are names with a table of variables, like the "phone" key
+// of the "notifiers" section, with the "url" key set in NOTIFIER_PHONE_URL. kind is used in the errors.
+func (f *fileConfigs) parseNamedTables(section map[string]any, sectionKey, kind string, keys []string, envName func(name, key string) string) error {
+	for _, name := range sortedKeys(section) {
+		key := sectionKey + "." + name
+		if !isInstanceName(strings.ToUpper(name)) {
+			return fmt.Errorf("config file %s: %s: %s names can only have letters and numbers", f.path, key, kind)
+		}
+		table, err := f.table(section[name], key)
+		if err != nil {
+			return err
+		}
+
+		vars := map[string]string{}
+		for _, varKey := range keys {
+			vars[strings.ToLower(varKey)] = envName(name, varKey)
+		}
+		if err := f.parseVars(table, key, vars); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseNamedVars parses a section whose keys are names set in the variables with the prefix,
+// like the "ops" key of the "alarms_filter_presets" section, set in ALARMS_FILTER_PRESET_OPS
+func (f *fileConfigs) parseNamedVars(section map[string]any, key, prefix string) error {
+	vars := map[string]string{}
+	for _, name := range sortedKeys(section) {
+		if !isInstanceName(strings.ToUpper(name)) {
+			return fmt.Errorf("config file %s: %s.%s: names can only have letters and numbers", f.path, key, name)
+		}
+		vars[name] = prefix + strings.ToUpper(name)
+	}
+
+	return f.parseVars(section, key, vars)
+}
+
+func (f *fileConfigs) parseWidgets(widgets map[string]any) error {
+	for _, name := range sortedKeys(widgets) {
+		if !registeredWidgets[name] {
+			return f.unknownKey("widgets."+name, sortedKeys(registeredWidgets))
+		}
+		params, err := f.table(widgets[name], "widgets."+name)
+		if err != nil {
+			return err
+		}
+
+		f.widgets[name] = url.Values{}
+		for _, param := range sortedKeys(params) {
+			values, err := f.values(params[param], "widgets."+name+"."+param)
+			if err != nil {
+				return err
+			}
+			f.widgets[name][param] = values
+		}
+	}
+
+	return nil
+}
+
+// parseVars sets the environment variables of the keys of a section
+func (f *fileConfigs) parseVars(section map[string]any, prefix string, vars map[string]string) error {
+	for _, key := range sortedKeys(section) {
+		name, ok := vars[key]
+		if !ok {
+			return f.unknownKey(prefix+"."+key, sortedKeys(vars))
+		}
+		values, err := f.values(section[key], prefix+"."+key)
+		if err != nil {
+			return err
+		}
+		if len(values) > 1 && !isListVar(name) {
+			return fmt.Errorf("config file %s: %s must be a single value", f.path, prefix+"."+key)
+		}
+		f.vars[name] = strings.Join(values, ",")
+	}
+
+	return nil
+}
+
+// values returns a scalar or a list of scalars as strings
+func (f *fileConfigs) values(value any, key string) ([]string, error) {
+	switch value := value.(type) {
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			itemValues, err := f.values(item, key)
+			if err != nil {
+				return nil, err
+			}
+			if len(itemValues) != 1 {
+				return nil, fmt.Errorf("config file %s: %s can't have nested lists", f.path, key)
+			}
+			values = append(values, itemValues[0])
+		}
+		return values, nil
+	case map[string]any:
+		return nil, fmt.Errorf("config file %s: %s must be a value or a list, not a table", f.path, key)
+	case nil:
+		return []string{""}, nil
+	default:
+		return []string{fmt.Sprint(value)}, nil
+	}
+}
+
+func (f *fileConfigs) table(value any, key string) (map[string]any, error) {
+	if value == nil {
+		return map[string]any{}, nil
+	}
+	table, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("config file %s: %s must be a table", f.path, key)
+	}
+
+	return table, nil
+}
+
+func (f *fileConfigs) unknownKey(key string, valid []string) error {
+	return fmt.Errorf("config file %s: unknown key %s, valid keys are: %s", f.path, key, strings.Join(valid, ", "))
+}
+
+// checkInstances checks if the source instances in the file have their required variables,
+// as configuring a source in the file without them is a mistake
+func (f *fileConfigs) checkInstances(sources map[string]map[string]SourceConfigs) error {
+	for _, name := range sortedKeys(f.instances) {
+		for _, instance := range f.instances[name] {
+			key := "sources." + name
+			if instance != "" {
+				key += ".instances." + instance
+			}
+			if err := schemas[name].ForInstance(instance).CheckRequired(sources[name][instance]); err != nil {
+				return fmt.Errorf("config file %s: %s: %w", f.path, key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
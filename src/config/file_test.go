@@ -0,0 +1,160 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func init() {
+	RegisterSchema("filetest", Schema{
+		Prefix:        "FILETEST",
+		MultiInstance: true,
+		Vars: []Var{
+			{Key: "ADDRESS", Required: true, Validate: ValidateURL},
+			{Key: "INTERNAL_ADDRESS", Validate: ValidateURL},
+			{Key: "API_KEY", Required: true, Secret: true},
+		},
+	})
+	RegisterWidget("filetest")
+}
+
+func writeConfigFile(t *testing.T, name, content string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+}
+
+func TestConfigFile(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+http:
+  timeout: 5s
+  retries: 1
+auth:
+  actions_secret: file-actions-secret
+  allowed_origins:
+    - https://homarr.domain.com
+    - https://other.domain.com
+sources:
+  filetest:
+    address: https://filetest.domain.com
+    api_key: key
+    instances:
+      4K:
+        address: https://filetest-4k.domain.com
+        internal_address: http://filetest-4k:8080
+        api_key: key-4k
+widgets:
+  filetest:
+    limit: 5
+    theme: dark
+`,
+		"config.toml": `
+[http]
+timeout = "5s"
+retries = 1
+
+[auth]
+actions_secret = "file-actions-secret"
+allowed_origins = ["https://homarr.domain.com", "https://other.domain.com"]
+
+[sources.filetest]
+address = "https://filetest.domain.com"
+api_key = "key"
+
+[sources.filetest.instances.4K]
+address = "https://filetest-4k.domain.com"
+internal_address = "http://filetest-4k:8080"
+api_key = "key-4k"
+
+[widgets.filetest]
+limit = 5
+theme = "dark"
+`,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			writeConfigFile(t, name, content)
+			t.Setenv("FILETEST_API_KEY", "env-key")
+			if err := SetConfigs(""); err != nil {
+				t.Fatal(err)
+			}
+
+			if timeout := Current().HTTP.Timeout.String(); timeout != "5s" {
+				t.Errorf("expected timeout 5s, got %s", timeout)
+			}
+			if Current().HTTP.Retries != 1 {
+				t.Errorf("expected 1 retry, got %d", Current().HTTP.Retries)
+			}
+			expectedOrigins := []string{"https://homarr.domain.com", "https://other.domain.com"}
+			if !slices.Equal(Current().Auth.AllowedOrigins, expectedOrigins) {
+				t.Errorf("expected origins %v, got %v", expectedOrigins, Current().Auth.AllowedOrigins)
+			}
+			if key := Current().Source("filetest").Get("API_KEY"); key != "env-key" {
+				t.Errorf("environment variables should override the file, got %q", key)
+			}
+			if address := Current().Instance("filetest", "4k").Get("INTERNAL_ADDRESS"); address != "http://filetest-4k:8080" {
+				t.Errorf("expected the 4k instance internal address, got %q", address)
+			}
+			if limit := Current().WidgetDefaults("filetest").Get("limit"); limit != "5" {
+				t.Errorf("expected widget default limit 5, got %q", limit)
+			}
+		})
+	}
+}
+
+func TestConfigFileErrors(t *testing.T) {
+	tests := map[string]struct {
+		content  string
+		expected string
+	}{
+		"unknown section": {
+			content:  "htp:\n  timeout: 5s\n",
+			expected: "unknown key htp",
+		},
+		"unknown key": {
+			content:  "http:\n  timout: 5s\n",
+			expected: "unknown key http.timout",
+		},
+		"unknown source": {
+			content:  "sources:\n  filetset:\n    address: https://domain.com\n",
+			expected: "unknown key sources.filetset",
+		},
+		"unknown widget": {
+			content:  "widgets:\n  filetset:\n    limit: 5\n",
+			expected: "unknown key widgets.filetset",
+		},
+		"bad URL": {
+			content:  "sources:\n  filetest:\n    address: filetest.domain.com\n    api_key: key\n",
+			expected: "FILETEST_ADDRESS: must be a valid URL",
+		},
+		"missing credentials": {
+			content:  "sources:\n  filetest:\n    instances:\n      backup:\n        address: https://domain.com\n",
+			expected: "sources.filetest.instances.backup: FILETEST_BACKUP_ADDRESS and FILETEST_BACKUP_API_KEY variables should be set",
+		},
+		"list value": {
+			content:  "http:\n  timeout: [5s, 10s]\n",
+			expected: "http.timeout must be a single value",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			writeConfigFile(t, "config.yml", test.content)
+			err := SetConfigs("")
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("expected error containing %q, got %q", test.expected, err)
+			}
+		})
+	}
+}
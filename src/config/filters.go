@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diogovalentte/homarr-iframes/src/filter"
+)
+
+// filterPresetPrefix is the prefix of the alarm filter presets variables, like ALARMS_FILTER_PRESET_OPS
+const filterPresetPrefix = "ALARMS_FILTER_PRESET_"
+
+// ValidateFilter can be used as the Validate function of an alarm filter expression variable
+func ValidateFilter(value string) error {
+	_, err := filter.Parse(value)
+	return err
+}
+
+// filterPresetNames returns the names of the alarm filter presets found in the environment variables, in lowercase.
+// Preset names can only have letters and numbers. The empty variables are ignored.
+func filterPresetNames(environ []string) []string {
+	found := map[string]bool{}
+	for _, env := range environ {
+		name, value, _ := strings.Cut(env, "=")
+		preset, ok := strings.CutPrefix(name, filterPresetPrefix)
+		if ok && value != "" && isInstanceName(preset) {
+			found[strings.ToLower(preset)] = true
+		}
+	}
+
+	return sortedKeys(found)
+}
+
+// loadAlarmFilters loads the global alarm filter and the filter presets
+func loadAlarmFilters(getenv func(string) string, environ []string) (*filter.Expr, map[string]*filter.Expr, error) {
+	var global *filter.Expr
+	if text := getenv("ALARMS_FILTER"); text != "" {
+		var err error
+		global, err = filter.Parse(text)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ALARMS_FILTER: %w", err)
+		}
+	}
+
+	presets := map[string]*filter.Expr{}
+	for _, name := range filterPresetNames(environ) {
+		envName := filterPresetPrefix + strings.ToUpper(name)
+		expr, err := filter.Parse(getenv(envName))
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", envName, err)
+		}
+		presets[name] = expr
+	}
+
+	return global, presets, nil
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAlarmFilters(t *testing.T) {
+	writeConfigFile(t, "config.yaml", `
+iframes:
+  alarms_filter: NOT source=Pi-hole
+alarms_filter_presets:
+  ops: severity=error OR severity=critical
+sources:
+  filetest:
+    address: https://filetest.domain.com
+    api_key: key
+`)
+	t.Setenv("ALARMS_FILTER_PRESET_HOME", `summary~"disk"`)
+	if err := SetConfigs(""); err != nil {
+		t.Fatal(err)
+	}
+	defer Set(nil)
+
+	iframesConfigs := Current().IFrames
+	if iframesConfigs.AlarmsFilter == nil || iframesConfigs.AlarmsFilter.String() != "NOT source=Pi-hole" {
+		t.Errorf("expected the ALARMS_FILTER from the file, got %v", iframesConfigs.AlarmsFilter)
+	}
+	if len(iframesConfigs.AlarmsFilterPresets) != 2 || iframesConfigs.AlarmsFilterPresets["ops"] == nil || iframesConfigs.AlarmsFilterPresets["home"] == nil {
+		t.Errorf("expected the ops and home presets, got %v", iframesConfigs.AlarmsFilterPresets)
+	}
+}
+
+func TestInvalidAlarmFilters(t *testing.T) {
+	for _, name := range []string{"ALARMS_FILTER", "ALARMS_FILTER_PRESET_OPS"} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("CONFIG_FILE", "")
+			t.Setenv(name, `summary~"disk" AND`)
+			_, err := loadConfigs()
+			if err == nil || !strings.Contains(err.Error(), name) {
+				t.Errorf("expected an error about %s, got %v", name, err)
+			}
+		})
+	}
+}
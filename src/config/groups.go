@@ -0,0 +1,23 @@
+package config
+
+import "strings"
+
+// sourceGroupPrefix is the prefix of the alarm source groups variables, like ALARMS_SOURCE_GROUP_ARR
+const sourceGroupPrefix = "ALARMS_SOURCE_GROUP_"
+
+// loadSourceGroups loads the alarm source groups, used to group the alarms of similar sources, like Sonarr and Radarr.
+// It returns the sources of each group, in lowercase, by lowercase group name.
+// Group names can only have letters and numbers. The empty variables are ignored.
+func loadSourceGroups(getenv func(string) string, environ []string) map[string][]string {
+	groups := map[string][]string{}
+	for _, env := range environ {
+		name, value, _ := strings.Cut(env, "=")
+		group, ok := strings.CutPrefix(name, sourceGroupPrefix)
+		if !ok || value == "" || !isInstanceName(group) {
+			continue
+		}
+		groups[strings.ToLower(group)] = splitList(strings.ToLower(getenv(name)))
+	}
+
+	return groups
+}
@@ -0,0 +1,23 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSourceGroups(t *testing.T) {
+	writeConfigFile(t, "config.yaml", `
+alarms_source_groups:
+  arr: [Sonarr, radarr, lidarr]
+`)
+	t.Setenv("ALARMS_SOURCE_GROUP_MEDIA", "jellyfin, kavita")
+	if err := SetConfigs(""); err != nil {
+		t.Fatal(err)
+	}
+	defer Set(nil)
+
+	groups := Current().IFrames.AlarmsSourceGroups
+	if !slices.Equal(groups["arr"], []string{"sonarr", "radarr", "lidarr"}) || !slices.Equal(groups["media"], []string{"jellyfin", "kavita"}) {
+		t.Errorf("expected the arr and media groups, got %v", groups)
+	}
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+var (
+	defaultHTTPTimeout      = 30 * time.Second
+	defaultHTTPRetries      = 2
+	defaultHTTPRetryBackoff = 500 * time.Millisecond
+)
+
+// httpVars are added to every source schema, so each source
+// can override the global HTTP configs, like RADARR_TIMEOUT.
+var httpVars = []Var{
+	{Key: "TIMEOUT", Validate: ValidateDuration},
+	{Key: "CA_FILE", Validate: validateFile},
+	{Key: "INSECURE_SKIP_VERIFY", Validate: ValidateBool},
+}
+
+// HTTPConfigs are the configs of the HTTP clients used to request the sources
+type HTTPConfigs struct {
+	// Timeout of each request, including retries
+	Timeout time.Duration
+	// CAFile is a PEM file with certificates to trust besides the system ones
+	CAFile             string
+	InsecureSkipVerify bool
+	// ProxyURL is used for all requests. If nil, the HTTP_PROXY, HTTPS_PROXY and NO_PROXY variables are used.
+	ProxyURL *url.URL
+	// Retries is how many times an idempotent request is retried after a network error or a 5xx/429 response
+	Retries int
+	// RetryBackoff is the wait before the first retry, doubled for each next retry
+	RetryBackoff time.Duration
+}
+
+// SourceHTTP returns the HTTP configs of a source instance, which are the global
+// HTTP configs with the overrides of the source, like RADARR_4K_TIMEOUT.
+// If the configs weren't loaded, like in some tests, it returns the default HTTP configs.
+func (c *Configs) SourceHTTP(name, instance string) HTTPConfigs {
+	if c == nil {
+		return defaultHTTPConfigs()
+	}
+	httpConfigs := c.HTTP
+	sourceConfigs := c.Instance(name, instance)
+	if timeout := sourceConfigs.Get("TIMEOUT"); timeout != "" {
+		httpConfigs.Timeout, _ = time.ParseDuration(timeout)
+	}
+	if caFile := sourceConfigs.Get("CA_FILE"); caFile != "" {
+		httpConfigs.CAFile = caFile
+	}
+	if insecureSkipVerify := sourceConfigs.Get("INSECURE_SKIP_VERIFY"); insecureSkipVerify != "" {
+		httpConfigs.InsecureSkipVerify, _ = strconv.ParseBool(insecureSkipVerify)
+	}
+
+	return httpConfigs
+}
+
+func defaultHTTPConfigs() HTTPConfigs {
+	return HTTPConfigs{
+		Timeout:      defaultHTTPTimeout,
+		Retries:      defaultHTTPRetries,
+		RetryBackoff: defaultHTTPRetryBackoff,
+	}
+}
+
+func loadHTTPConfigs(getenv func(string) string) (HTTPConfigs, error) {
+	httpConfigs := defaultHTTPConfigs()
+
+	if timeout := getenv("HTTP_TIMEOUT"); timeout != "" {
+		if err := ValidateDuration(timeout); err != nil {
+			return httpConfigs, fmt.Errorf("HTTP_TIMEOUT: %w", err)
+		}
+		httpConfigs.Timeout, _ = time.ParseDuration(timeout)
+	}
+
+	if caFile := getenv("HTTP_CA_FILE"); caFile != "" {
+		if err := validateFile(caFile); err != nil {
+			return httpConfigs, fmt.Errorf("HTTP_CA_FILE: %w", err)
+		}
+		httpConfigs.CAFile = caFile
+	}
+
+	if insecureSkipVerify := getenv("HTTP_INSECURE_SKIP_VERIFY"); insecureSkipVerify != "" {
+		value, err := strconv.ParseBool(insecureSkipVerify)
+		if err != nil {
+			return httpConfigs, fmt.Errorf("HTTP_INSECURE_SKIP_VERIFY must be a boolean")
+		}
+		httpConfigs.InsecureSkipVerify = value
+	}
+
+	if proxyURL := getenv("HTTP_PROXY_URL"); proxyURL != "" {
+		value, err := url.Parse(proxyURL)
+		if err != nil || value.Host == "" {
+			return httpConfigs, fmt.Errorf("HTTP_PROXY_URL must be a valid URL like 'http://proxy:3128'")
+		}
+		httpConfigs.ProxyURL = value
+	}
+
+	if retries := getenv("HTTP_RETRIES"); retries != "" {
+		value, err := strconv.Atoi(retries)
+		if err != nil || value < 0 {
+			return httpConfigs, fmt.Errorf("HTTP_RETRIES must be a non-negative integer")
+		}
+		httpConfigs.Retries = value
+	}
+
+	if retryBackoff := getenv("HTTP_RETRY_BACKOFF"); retryBackoff != "" {
+		if err := ValidateDuration(retryBackoff); err != nil {
+			return httpConfigs, fmt.Errorf("HTTP_RETRY_BACKOFF: %w", err)
+		}
+		httpConfigs.RetryBackoff, _ = time.ParseDuration(retryBackoff)
+	}
+
+	return httpConfigs, nil
+}
+
+func validateFile(value string) error {
+	if _, err := os.Stat(value); err != nil {
+		return fmt.Errorf("couldn't read file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,11 @@
+package config
+
+import "testing"
+
+func TestSourceHTTPWithoutConfigs(t *testing.T) {
+	var configs *Configs
+	httpConfigs := configs.SourceHTTP("cinemark", "")
+	if httpConfigs.Timeout != defaultHTTPTimeout || httpConfigs.Retries != defaultHTTPRetries || httpConfigs.RetryBackoff != defaultHTTPRetryBackoff {
+		t.Errorf("expected the default HTTP configs, got %+v", httpConfigs)
+	}
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// LogConfigs are the configs of the API logs
+type LogConfigs struct {
+	// Level is the minimum level of the logs, like slog.LevelInfo
+	Level slog.Level
+	// Format is "text" or "json"
+	Format string
+}
+
+func loadLogConfigs(getenv func(string) string) (LogConfigs, error) {
+	logConfigs := LogConfigs{
+		Level:  slog.LevelInfo,
+		Format: "text",
+	}
+
+	if level := getenv("LOG_LEVEL"); level != "" {
+		if err := logConfigs.Level.UnmarshalText([]byte(level)); err != nil {
+			return logConfigs, fmt.Errorf("LOG_LEVEL must be 'debug', 'info', 'warn', or 'error'")
+		}
+	}
+
+	if format := strings.ToLower(getenv("LOG_FORMAT")); format != "" {
+		if format != "text" && format != "json" {
+			return logConfigs, fmt.Errorf("LOG_FORMAT must be 'text' or 'json'")
+		}
+		logConfigs.Format = format
+	}
+
+	return logConfigs, nil
+}
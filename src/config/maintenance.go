@@ -0,0 +1,246 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/filter"
+)
+
+var (
+	// maintenanceKeys are the keys of the maintenance window variables, like ALARMS_MAINTENANCE_BACKUPS_SCHEDULE
+	maintenanceKeys = []string{"SCHEDULE", "TIMEZONE", "SOURCES", "FILTER", "ACTION"}
+	// maintenanceListKeys are the maintenance window keys that can be a list in the config file
+	maintenanceListKeys = []string{"SCHEDULE", "SOURCES"}
+	// MaintenanceActions are what is done with the alarms in a maintenance window, from the strongest
+	MaintenanceActions = []string{"hide", "dim", "mark"}
+	weekdays           = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// maintenancePrefix is the prefix of the maintenance window variables
+const maintenancePrefix = "ALARMS_MAINTENANCE_"
+
+// MaintenanceWindow is a schedule when some alarms are expected, like during the nightly backups.
+// The alarms in the window are hidden, dimmed, or marked in the alarms iFrame, and they are not notified.
+// It's configured with variables like ALARMS_MAINTENANCE_BACKUPS_SCHEDULE for the window "backups".
+type MaintenanceWindow struct {
+	// Name is the window name in lowercase, like "backups"
+	Name string
+	// Location is the time zone of the schedule, defaults to the TZ time zone
+	Location *time.Location
+	// Sources are the sources of the alarms in the window, like "backrest" or "radarr:4k". Empty is every source.
+	Sources []string
+	// Filter is the filter expression of the alarms in the window. Nil is every alarm.
+	Filter *filter.Expr
+	// Action is "hide", "dim", or "mark"
+	Action   string
+	schedule []timeRange
+}
+
+// timeRange is a time range of a maintenance window schedule, like "sat-sun 01:00-05:00"
+type timeRange struct {
+	// days are the weekdays when the range starts. Empty is every day.
+	days []time.Weekday
+	// date is the only date when the range starts, like "2026-10-20". Empty is every day.
+	date string
+	// start and end are minutes since midnight. If end is before start, the range ends on the next day.
+	start, end int
+}
+
+// Active returns true if the time is in the window schedule
+func (w MaintenanceWindow) Active(now time.Time) bool {
+	now = now.In(w.Location)
+	minute := now.Hour()*60 + now.Minute()
+	yesterday := now.AddDate(0, 0, -1)
+	for _, r := range w.schedule {
+		if r.start < r.end {
+			if r.startsOn(now) && minute >= r.start && minute < r.end {
+				return true
+			}
+			continue
+		}
+		if (r.startsOn(now) && minute >= r.start) || (r.startsOn(yesterday) && minute < r.end) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Matches returns true if an alarm of a source instance is in the window.
+// get returns the alarm fields used by the filter expression.
+func (w MaintenanceWindow) Matches(name, instance string, get func(string) string) bool {
+	return routesSource(w.Sources, name, instance) && (w.Filter == nil || w.Filter.Match(get))
+}
+
+func (r timeRange) startsOn(day time.Time) bool {
+	if r.date != "" {
+		return day.Format(time.DateOnly) == r.date
+	}
+
+	return len(r.days) == 0 || slices.Contains(r.days, day.Weekday())
+}
+
+// parseSchedule parses a comma separated list of time ranges, like "02:00-04:00, sat-sun 01:00-06:00, 2026-10-20 22:00-02:00".
+// A range starts with a weekday, a range of weekdays, or a date, or every day if it's not set.
+func parseSchedule(schedule string) ([]timeRange, error) {
+	var ranges []timeRange
+	for _, text := range splitList(strings.ToLower(schedule)) {
+		fields := strings.Fields(text)
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, fmt.Errorf("invalid time range '%s', expected a range like '02:00-04:00' or 'mon-fri 22:00-06:00'", text)
+		}
+
+		var r timeRange
+		var err error
+		if len(fields) == 2 {
+			if _, err := time.Parse(time.DateOnly, fields[0]); err == nil {
+				r.date = fields[0]
+			} else if r.days, err = parseWeekdays(fields[0]); err != nil {
+				return nil, err
+			}
+		}
+		times := fields[len(fields)-1]
+		start, end, ok := strings.Cut(times, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid time range '%s', expected a range like '02:00-04:00'", times)
+		}
+		if r.start, err = parseClock(start, false); err != nil {
+			return nil, err
+		}
+		if r.end, err = parseClock(end, true); err != nil {
+			return nil, err
+		}
+		if r.start == r.end {
+			return nil, fmt.Errorf("invalid time range '%s', the start and end are the same", times)
+		}
+		ranges = append(ranges, r)
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("the schedule is empty")
+	}
+
+	return ranges, nil
+}
+
+// parseWeekdays parses a weekday, like "sat", or a range of weekdays, like "mon-fri" or "fri-mon"
+func parseWeekdays(text string) ([]time.Weekday, error) {
+	first, last, isRange := strings.Cut(text, "-")
+	if !isRange {
+		last = first
+	}
+	start, end := slices.Index(weekdays, first), slices.Index(weekdays, last)
+	if start == -1 || end == -1 {
+		return nil, fmt.Errorf("invalid day '%s', expected a date like '2026-10-20', a weekday like 'sat', or a range like 'mon-fri'. The weekdays are: %s", text, strings.Join(weekdays, ", "))
+	}
+
+	var days []time.Weekday
+	for day := start; ; day = (day + 1) % len(weekdays) {
+		days = append(days, time.Weekday(day))
+		if day == end {
+			return days, nil
+		}
+	}
+}
+
+// parseClock parses a time like "02:30" and returns the minutes since midnight. The end of a range can be "24:00".
+func parseClock(text string, end bool) (int, error) {
+	if end && text == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time '%s', expected a time like '02:30'", text)
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// maintenanceEnvName returns the environment variable name of a maintenance window key, like ALARMS_MAINTENANCE_BACKUPS_SCHEDULE
+func maintenanceEnvName(name, key string) string {
+	return maintenancePrefix + strings.ToUpper(name) + "_" + key
+}
+
+// maintenanceNames returns the names of the maintenance windows found in the environment variables, sorted and in lowercase.
+// Window names can only have letters and numbers. The empty variables are ignored.
+func maintenanceNames(environ []string) []string {
+	found := map[string]bool{}
+	for _, env := range environ {
+		name, value, _ := strings.Cut(env, "=")
+		rest, ok := strings.CutPrefix(name, maintenancePrefix)
+		if !ok || value == "" {
+			continue
+		}
+		for _, key := range maintenanceKeys {
+			if window, ok := strings.CutSuffix(rest, "_"+key); ok && isInstanceName(window) {
+				found[strings.ToLower(window)] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func loadMaintenanceWindows(getenv func(string) string, environ []string) ([]MaintenanceWindow, error) {
+	var windows []MaintenanceWindow
+	for _, name := range maintenanceNames(environ) {
+		window, err := loadMaintenanceWindow(getenv, name)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+
+	return windows, nil
+}
+
+func loadMaintenanceWindow(getenv func(string) string, name string) (MaintenanceWindow, error) {
+	get := func(key string) string {
+		return strings.TrimSpace(getenv(maintenanceEnvName(name, key)))
+	}
+	window := MaintenanceWindow{
+		Name:     name,
+		Location: time.Local,
+		Sources:  splitList(strings.ToLower(get("SOURCES"))),
+		Action:   strings.ToLower(get("ACTION")),
+	}
+
+	schedule := get("SCHEDULE")
+	if schedule == "" {
+		return window, fmt.Errorf("%s variable should be set", maintenanceEnvName(name, "SCHEDULE"))
+	}
+	var err error
+	if window.schedule, err = parseSchedule(schedule); err != nil {
+		return window, fmt.Errorf("%s: %w", maintenanceEnvName(name, "SCHEDULE"), err)
+	}
+
+	if timezone := get("TIMEZONE"); timezone != "" {
+		if window.Location, err = time.LoadLocation(timezone); err != nil {
+			return window, fmt.Errorf("%s must be a time zone, like 'America/Sao_Paulo': %w", maintenanceEnvName(name, "TIMEZONE"), err)
+		}
+	}
+
+	if text := get("FILTER"); text != "" {
+		if window.Filter, err = filter.Parse(text); err != nil {
+			return window, fmt.Errorf("%s: %w", maintenanceEnvName(name, "FILTER"), err)
+		}
+	}
+
+	if window.Action == "" {
+		window.Action = "mark"
+	}
+	if !slices.Contains(MaintenanceActions, window.Action) {
+		return window, fmt.Errorf("%s must be one of: %s", maintenanceEnvName(name, "ACTION"), strings.Join(MaintenanceActions, ", "))
+	}
+
+	return window, nil
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindows(t *testing.T) {
+	writeConfigFile(t, "config.yaml", `
+alarms_maintenance:
+  backups:
+    schedule:
+      - 02:00-04:00
+      - sat-sun 01:00-06:00
+    timezone: America/Sao_Paulo
+    sources: [filetest, filetest:4k]
+    action: hide
+`)
+	t.Setenv("ALARMS_MAINTENANCE_UPGRADE_SCHEDULE", "2026-10-20 22:00-02:00")
+	t.Setenv("ALARMS_MAINTENANCE_UPGRADE_FILTER", "severity=warning")
+	if err := SetConfigs(""); err != nil {
+		t.Fatal(err)
+	}
+	defer Set(nil)
+
+	windows := Current().Maintenance
+	if len(windows) != 2 || windows[0].Name != "backups" || windows[1].Name != "upgrade" {
+		t.Fatalf("expected the backups and upgrade windows, got %v", windows)
+	}
+	backups, upgrade := windows[0], windows[1]
+	if backups.Action != "hide" || upgrade.Action != "mark" || backups.Location.String() != "America/Sao_Paulo" {
+		t.Errorf("unexpected windows configs: %+v, %+v", backups, upgrade)
+	}
+
+	saoPaulo := backups.Location
+	active := map[time.Time]bool{
+		time.Date(2026, 10, 21, 2, 0, 0, 0, saoPaulo):   true,  // Wednesday
+		time.Date(2026, 10, 21, 3, 59, 0, 0, saoPaulo):  true,  // Wednesday
+		time.Date(2026, 10, 21, 4, 0, 0, 0, saoPaulo):   false, // Wednesday
+		time.Date(2026, 10, 21, 5, 0, 0, 0, time.UTC):   true,  // 02:00 in São Paulo
+		time.Date(2026, 10, 24, 5, 30, 0, 0, saoPaulo):  true,  // Saturday
+		time.Date(2026, 10, 23, 5, 30, 0, 0, saoPaulo):  false, // Friday
+		time.Date(2026, 10, 25, 0, 30, 0, 0, saoPaulo):  false, // Sunday
+		time.Date(2026, 10, 23, 23, 30, 0, 0, saoPaulo): false,
+	}
+	for now, expected := range active {
+		if actual := backups.Active(now); actual != expected {
+			t.Errorf("backups.Active(%s): expected %v, got %v", now, expected, actual)
+		}
+	}
+	for now, expected := range map[time.Time]bool{
+		time.Date(2026, 10, 20, 23, 0, 0, 0, time.Local): true,
+		time.Date(2026, 10, 21, 1, 59, 0, 0, time.Local): true,
+		time.Date(2026, 10, 21, 2, 0, 0, 0, time.Local):  false,
+		time.Date(2026, 10, 27, 23, 0, 0, 0, time.Local): false,
+	} {
+		if actual := upgrade.Active(now); actual != expected {
+			t.Errorf("upgrade.Active(%s): expected %v, got %v", now, expected, actual)
+		}
+	}
+
+	fields := map[string]string{"severity": "warning"}
+	get := func(field string) string { return fields[field] }
+	if !backups.Matches("filetest", "4k", get) || backups.Matches("filetest", "8k", get) || !upgrade.Matches("radarr", "", get) {
+		t.Errorf("unexpected windows matches")
+	}
+	fields["severity"] = "critical"
+	if upgrade.Matches("radarr", "", get) {
+		t.Errorf("expected the upgrade window to not match critical alarms")
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	ranges, err := parseSchedule("fri-mon 22:00-24:00, sun 00:00-01:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ranges[0].days, []time.Weekday{time.Friday, time.Saturday, time.Sunday, time.Monday}) || ranges[0].end != 24*60 {
+		t.Errorf("unexpected ranges: %+v", ranges)
+	}
+
+	for _, schedule := range []string{"", "02:00", "25:00-04:00", "02:00-02:00", "someday 02:00-04:00", "mon 02:00-04:00 utc", "2026-13-01 02:00-04:00"} {
+		if _, err := parseSchedule(schedule); err == nil {
+			t.Errorf("parseSchedule(%q): expected an error", schedule)
+		}
+	}
+
+	t.Setenv("ALARMS_MAINTENANCE_BAD_SCHEDULE", "02:00-04:00")
+	t.Setenv("ALARMS_MAINTENANCE_BAD_ACTION", "silence")
+	if err := SetConfigs(""); err == nil || !strings.Contains(err.Error(), "ALARMS_MAINTENANCE_BAD_ACTION") {
+		t.Errorf("expected an error about ALARMS_MAINTENANCE_BAD_ACTION, got %v", err)
+	}
+}
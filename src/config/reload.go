@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"sync"
+
+	"github.com/joho/godotenv"
+)
+
+var (
+	reloadMu sync.Mutex
+	// envFilePath is the .env file passed to SetConfigs, loaded again on reload
+	envFilePath string
+	// envFileVars are the environment variables set from the .env file. The other ones
+	// were set before the API started, and are not changed by the .env file.
+	envFileVars map[string]bool
+)
+
+// Reload loads the configs again, from the environment variables, the .env file, and the config file.
+// If the new configs are invalid, the current configs are kept and the error is returned.
+// It returns the old and new configs, so the callers can check what changed.
+// The generated ACTIONS_SECRET is kept, so the action tokens in the opened iFrames keep working.
+func Reload() (oldConfigs, newConfigs *Configs, err error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	if err := loadEnvFile(); err != nil {
+		return nil, nil, err
+	}
+
+	newConfigs, err = loadConfigs()
+	if err != nil {
+		return nil, nil, err
+	}
+	oldConfigs = current.Load()
+	if oldConfigs != nil && oldConfigs.Auth.generatedSecret && newConfigs.Auth.generatedSecret {
+		newConfigs.Auth.ActionsSecret = oldConfigs.Auth.ActionsSecret
+	}
+	current.Store(newConfigs)
+
+	return oldConfigs, newConfigs, nil
+}
+
+// FilePath returns the path of the config file set in CONFIG_FILE, or "" if not set
+func FilePath() string {
+	return os.Getenv("CONFIG_FILE")
+}
+
+// loadEnvFile sets the environment variables from the .env file, if there is one. Variables
+// set before the API started are not overridden, and the ones removed from the file are unset.
+func loadEnvFile() error {
+	if envFilePath == "" {
+		return nil
+	}
+	vars, err := godotenv.Read(envFilePath)
+	if err != nil {
+		return err
+	}
+
+	for name := range envFileVars {
+		if _, ok := vars[name]; !ok {
+			os.Unsetenv(name)
+			delete(envFileVars, name)
+		}
+	}
+	for name, value := range vars {
+		if _, ok := os.LookupEnv(name); ok && !envFileVars[name] {
+			continue
+		}
+		os.Setenv(name, value)
+		envFileVars[name] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestReload(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("ACTIONS_SECRET", "")
+	t.Setenv("HTTP_RETRIES", "1")
+	if err := SetConfigs(""); err != nil {
+		t.Fatal(err)
+	}
+	secret := Current().Auth.ActionsSecret
+
+	t.Setenv("HTTP_RETRIES", "3")
+	oldConfigs, newConfigs, err := Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oldConfigs.HTTP.Retries != 1 || newConfigs.HTTP.Retries != 3 || Current().HTTP.Retries != 3 {
+		t.Errorf("expected the retries to change from 1 to 3, got %d to %d", oldConfigs.HTTP.Retries, newConfigs.HTTP.Retries)
+	}
+	if Current().Auth.ActionsSecret != secret {
+		t.Error("the generated actions secret should be kept")
+	}
+
+	t.Setenv("HTTP_RETRIES", "many")
+	if _, _, err := Reload(); err == nil {
+		t.Fatal("expected error")
+	}
+	if Current().HTTP.Retries != 3 {
+		t.Errorf("the current configs should be kept if the new ones are invalid, got %d retries", Current().HTTP.Retries)
+	}
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema describes the variables used to configure a source
+type Schema struct {
+	// Prefix is the prefix of all the source variables, like "RADARR"
+	Prefix string
+	Vars   []Var
+	// MultiInstance sources can have named instances besides the default one,
+	// configured with variables like RADARR_4K_ADDRESS and INTERNAL_RADARR_4K_ADDRESS.
+	MultiInstance bool
+}
+
+// Var is a source configuration variable
+type Var struct {
+	// Key is the variable name without the source prefix, like "API_KEY" for RADARR_API_KEY.
+	// Keys starting with "INTERNAL_" are read from INTERNAL_<PREFIX>_<KEY>, like INTERNAL_RADARR_ADDRESS.
+	Key string
+	// Required variables are checked when the source client is created, not when the configs are loaded,
+	// as every source is optional.
+	Required bool
+	// Secret variables should never be shown to the user
+	Secret bool
+	// Default is used when the variable is not set
+	Default string
+	// Validate is called when the configs are loaded and the variable is not empty
+	Validate func(value string) error
+}
+
+// EnvName returns the environment variable name of a key
+func (s Schema) EnvName(key string) string {
+	if rest, ok := strings.CutPrefix(key, "INTERNAL_"); ok {
+		return "INTERNAL_" + s.Prefix + "_" + rest
+	}
+
+	return s.Prefix + "_" + key
+}
+
+// ForInstance returns the schema of a named instance, where the variables
+// have the instance name after the prefix, like RADARR_4K_API_KEY for the instance "4k".
+// The default instance is "".
+func (s Schema) ForInstance(instance string) Schema {
+	if instance == "" {
+		return s
+	}
+	s.Prefix += "_" + strings.ToUpper(instance)
+
+	return s
+}
+
+// instanceNames returns the names of the instances found in the environment variables, sorted and in lowercase.
+// Instance names can only have letters and numbers. The empty variables, like RADARR_4K_ADDRESS= in a
+// compose file, don't add an instance.
+func (s Schema) instanceNames(environ []string) []string {
+	defaultNames := map[string]bool{}
+	for _, v := range s.Vars {
+		defaultNames[s.EnvName(v.Key)] = true
+	}
+
+	found := map[string]bool{}
+	for _, env := range environ {
+		name, value, _ := strings.Cut(env, "=")
+		if defaultNames[name] || value == "" {
+			continue
+		}
+		for _, v := range s.Vars {
+			before, after, _ := strings.Cut(s.EnvName(v.Key), s.Prefix)
+			rest, ok := strings.CutPrefix(name, before+s.Prefix+"_")
+			if !ok {
+				continue
+			}
+			instance, ok := strings.CutSuffix(rest, after)
+			if ok && isInstanceName(instance) {
+				found[strings.ToLower(instance)] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func isInstanceName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s Schema) load(getenv func(string) string) (SourceConfigs, error) {
+	sourceConfigs := SourceConfigs{}
+	for _, v := range s.Vars {
+		value := getenv(s.EnvName(v.Key))
+		if value == "" {
+			value = v.Default
+		}
+		if value != "" && v.Validate != nil {
+			if err := v.Validate(value); err != nil {
+				return nil, fmt.Errorf("%s: %w", s.EnvName(v.Key), err)
+			}
+		}
+		sourceConfigs[v.Key] = value
+	}
+
+	return sourceConfigs, nil
+}
+
+// CheckRequired returns a *MissingVarsError if any required variable of the schema is empty
+func (s Schema) CheckRequired(sourceConfigs SourceConfigs) error {
+	var missing bool
+	var names []string
+	for _, v := range s.Vars {
+		if !v.Required {
+			continue
+		}
+		names = append(names, s.EnvName(v.Key))
+		if sourceConfigs.Get(v.Key) == "" {
+			missing = true
+		}
+	}
+	if missing {
+		return &MissingVarsError{Vars: names}
+	}
+
+	return nil
+}
+
+// SourceConfigs has the values of a source variables, by key
+type SourceConfigs map[string]string
+
+// Get returns the value of a key, like "ADDRESS"
+func (s SourceConfigs) Get(key string) string {
+	return s[key]
+}
+
+// Int returns the value of a key as an int.
+// The value should be validated with ValidateInt when the configs are loaded.
+func (s SourceConfigs) Int(key string) int {
+	value, _ := strconv.Atoi(s[key])
+	return value
+}
+
+// ValidateInt can be used as the Validate function of an integer variable
+func ValidateInt(value string) error {
+	_, err := strconv.Atoi(value)
+	return err
+}
+
+// ValidateBool can be used as the Validate function of a boolean variable
+func ValidateBool(value string) error {
+	if _, err := strconv.ParseBool(value); err != nil {
+		return fmt.Errorf("must be a boolean")
+	}
+
+	return nil
+}
+
+// ValidateDuration can be used as the Validate function of a duration variable, like "10s"
+func ValidateDuration(value string) error {
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return fmt.Errorf("must be a positive duration, like '10s'")
+	}
+
+	return nil
+}
+
+// ValidateURL can be used as the Validate function of a URL variable, like "https://sub.domain.com"
+func ValidateURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be a valid URL, like 'https://sub.domain.com'")
+	}
+
+	return nil
+}
+
+// MissingVarsError is returned when a source can't be used because some of its variables are not set
+type MissingVarsError struct {
+	Vars []string
+}
+
+func (e *MissingVarsError) Error() string {
+	if len(e.Vars) == 1 {
+		return e.Vars[0] + " variable should be set"
+	}
+
+	return strings.Join(e.Vars[:len(e.Vars)-1], ", ") + " and " + e.Vars[len(e.Vars)-1] + " variables should be set"
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestInstanceNames(t *testing.T) {
+	schema := Schema{
+		Prefix: "PIHOLE",
+		Vars: []Var{
+			{Key: "ADDRESS"},
+			{Key: "INTERNAL_ADDRESS"},
+			{Key: "TOKEN"},
+			{Key: "API_TOKEN"},
+		},
+	}
+	environ := []string{
+		"PIHOLE_ADDRESS=http://pihole",
+		"PIHOLE_API_TOKEN=token",
+		"PIHOLE_SECONDARY_ADDRESS=http://pihole2",
+		"INTERNAL_PIHOLE_BACKUP_ADDRESS=http://pihole3",
+		"PIHOLE_4K_API_TOKEN=token",
+		"PIHOLE_NOT_VALID_ADDRESS=http://pihole4",
+		"PIHOLE_EMPTY_ADDRESS=",
+		"INTERNAL_PIHOLE_EMPTY_ADDRESS=",
+		"RADARR_OTHER_ADDRESS=http://radarr",
+	}
+
+	expected := []string{"4k", "backup", "secondary"}
+	actual := schema.instanceNames(environ)
+	if !slices.Equal(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestForInstance(t *testing.T) {
+	schema := Schema{Prefix: "RADARR"}.ForInstance("4k")
+
+	if name := schema.EnvName("ADDRESS"); name != "RADARR_4K_ADDRESS" {
+		t.Errorf("Expected RADARR_4K_ADDRESS but got %s", name)
+	}
+	if name := schema.EnvName("INTERNAL_ADDRESS"); name != "INTERNAL_RADARR_4K_ADDRESS" {
+		t.Errorf("Expected INTERNAL_RADARR_4K_ADDRESS but got %s", name)
+	}
+}
@@ -0,0 +1,296 @@
+// Package filter parses and matches the alarm filter expressions, like `summary~"disk" AND source=Netdata`
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Fields are the alarm fields that can be used in the expressions
+var Fields = []string{"source", "instance", "summary", "status", "severity", "property", "value", "url"}
+
+// Expr is a parsed filter expression.
+//
+// The conditions are "field=value" (equal, case insensitive), "field!=value", "field~regex",
+// and "field!~regex". The values with spaces or parentheses must be quoted, like "disk full".
+// The conditions can be combined with AND, OR, NOT, and parentheses. AND has precedence over OR.
+type Expr struct {
+	text string
+	root node
+}
+
+// Parse parses a filter expression
+func Parse(text string) (*Expr, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", text, err)
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("invalid filter %q: the filter is empty", text)
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %s", p.tokens[p.pos])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", text, err)
+	}
+
+	return &Expr{text: text, root: root}, nil
+}
+
+// Match returns true if the fields returned by get match the expression.
+// get returns the value of a field, like "Netdata" for "source".
+func (e *Expr) Match(get func(field string) string) bool {
+	return e.root.match(get)
+}
+
+// String returns the expression text
+func (e *Expr) String() string {
+	return e.text
+}
+
+type node interface {
+	match(get func(field string) string) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) match(get func(string) string) bool { return n.left.match(get) && n.right.match(get) }
+
+type orNode struct{ left, right node }
+
+func (n orNode) match(get func(string) string) bool { return n.left.match(get) || n.right.match(get) }
+
+type notNode struct{ node node }
+
+func (n notNode) match(get func(string) string) bool { return !n.node.match(get) }
+
+type conditionNode struct {
+	field string
+	// op is "=", "!=", "~", or "!~"
+	op    string
+	value string
+	regex *regexp.Regexp
+}
+
+func (n conditionNode) match(get func(string) string) bool {
+	value := get(n.field)
+	switch n.op {
+	case "=":
+		return strings.EqualFold(value, n.value)
+	case "!=":
+		return !strings.EqualFold(value, n.value)
+	case "~":
+		return n.regex.MatchString(value)
+	default:
+		return !n.regex.MatchString(value)
+	}
+}
+
+type tokenKind int
+
+const (
+	wordToken tokenKind = iota
+	stringToken
+	opToken
+	openToken
+	closeToken
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func (t token) String() string {
+	if t.kind == stringToken {
+		return fmt.Sprintf("%q", t.value)
+	}
+
+	return "'" + t.value + "'"
+}
+
+// keyword returns the keyword of a word token, like "AND", or "" if it's not a keyword
+func (t token) keyword() string {
+	if t.kind != wordToken {
+		return ""
+	}
+	if keyword := strings.ToUpper(t.value); keyword == "AND" || keyword == "OR" || keyword == "NOT" {
+		return keyword
+	}
+
+	return ""
+}
+
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{openToken, "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{closeToken, ")"})
+			i++
+		case r == '=' || r == '~':
+			tokens = append(tokens, token{opToken, string(r)})
+			i++
+		case r == '!':
+			if i+1 >= len(runes) || (runes[i+1] != '=' && runes[i+1] != '~') {
+				return nil, fmt.Errorf("'!' must be followed by '=' or '~'")
+			}
+			tokens = append(tokens, token{opToken, string(runes[i : i+2])})
+			i += 2
+		case r == '"':
+			var value strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\') {
+					i++
+				}
+				value.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, token{stringToken, value.String()})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`()=~!"`, runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{wordToken, string(runes[start:i])})
+		}
+	}
+
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) next() (token, error) {
+	t, ok := p.peek()
+	if !ok {
+		return t, fmt.Errorf("unexpected end of the filter")
+	}
+	p.pos++
+
+	return t, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.keyword() != "OR" {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.keyword() != "AND" {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case t.keyword() == "NOT":
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{n}, nil
+	case t.kind == openToken:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t, err := p.next(); err != nil || t.kind != closeToken {
+			return nil, fmt.Errorf("missing ')'")
+		}
+		return n, nil
+	case t.kind == wordToken && t.keyword() == "":
+		return p.parseCondition(strings.ToLower(t.value))
+	default:
+		return nil, fmt.Errorf("unexpected %s, expected a condition like source=Netdata", t)
+	}
+}
+
+func (p *parser) parseCondition(field string) (node, error) {
+	if !slices.Contains(Fields, field) {
+		return nil, fmt.Errorf("unknown field '%s', valid fields are: %s", field, strings.Join(Fields, ", "))
+	}
+	op, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if op.kind != opToken {
+		return nil, fmt.Errorf("unexpected %s after '%s', expected '=', '!=', '~', or '!~'", op, field)
+	}
+	value, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if value.kind != wordToken && value.kind != stringToken {
+		return nil, fmt.Errorf("unexpected %s, expected the value of '%s'", value, field)
+	}
+
+	condition := conditionNode{field: field, op: op.value, value: value.value}
+	if op.value == "~" || op.value == "!~" {
+		condition.regex, err = regexp.Compile(value.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex of '%s': %w", field, err)
+		}
+	}
+
+	return condition, nil
+}
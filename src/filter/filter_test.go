@@ -0,0 +1,44 @@
+package filter
+
+import "testing"
+
+func TestFilter(t *testing.T) {
+	fields := map[string]string{
+		"source":   "Netdata",
+		"summary":  "Disk space usage on /dev/sda1",
+		"status":   "WARNING",
+		"severity": "warning",
+	}
+	get := func(field string) string { return fields[field] }
+
+	tests := map[string]bool{
+		`source=netdata`:                                      true,
+		`source!=Netdata`:                                     false,
+		`summary~"(?i)disk space"`:                            true,
+		`summary!~disk`:                                       true,
+		`summary~"disk" AND source=Netdata`:                   false,
+		`summary~"Disk" and source=Netdata`:                   true,
+		`source=Sonarr OR severity=warning`:                   true,
+		`NOT (source=Netdata AND status=WARNING)`:             false,
+		`source=Sonarr OR source=Radarr AND status=WARN`:      false,
+		`(source=Sonarr OR source=Netdata) AND NOT value~"."`: true,
+		`summary="Disk space usage on /dev/sda1"`:             true,
+		`summary="say \"hi\""`:                                false,
+	}
+	for text, expected := range tests {
+		expr, err := Parse(text)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", text, err)
+			continue
+		}
+		if actual := expr.Match(get); actual != expected {
+			t.Errorf("%q: expected %v, got %v", text, expected, actual)
+		}
+	}
+
+	for _, text := range []string{``, `source`, `source=`, `name=Netdata`, `source=Netdata AND`, `(source=Netdata`, `source=Netdata)`, `summary~"[`, `summary="disk`, `source!Netdata`, `source=Netdata status=WARNING`} {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("Parse(%q): expected an error", text)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Package logging sets up the structured logs of the API
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+// Setup sets the default logger using the log configs. It should be called again after
+// the configs are reloaded. The secrets are redacted from the messages and attributes.
+func Setup(logConfigs config.LogConfigs) {
+	slog.SetDefault(slog.New(NewHandler(os.Stderr, logConfigs)))
+}
+
+// NewHandler returns a handler that writes the logs to w in the format of
+// the log configs, redacting the secrets with config.Redact
+func NewHandler(w io.Writer, logConfigs config.LogConfigs) slog.Handler {
+	options := &slog.HandlerOptions{Level: logConfigs.Level}
+	if logConfigs.Format == "json" {
+		return redactHandler{slog.NewJSONHandler(w, options)}
+	}
+
+	return redactHandler{slog.NewTextHandler(w, options)}
+}
+
+// redactHandler redacts the secrets from the message and attributes of the records
+type redactHandler struct {
+	next slog.Handler
+}
+
+func (h redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h redactHandler) Handle(ctx context.Context, record slog.Record) error {
+	redactedRecord := slog.NewRecord(record.Time, record.Level, config.Redact(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redactedRecord.AddAttrs(redactAttr(attr))
+		return true
+	})
+
+	return h.next.Handle(ctx, redactedRecord)
+}
+
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redactedAttrs := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redactedAttrs[i] = redactAttr(attr)
+	}
+
+	return redactHandler{h.next.WithAttrs(redactedAttrs)}
+}
+
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	return redactHandler{h.next.WithGroup(name)}
+}
+
+// redactAttr redacts the string, error, and group attributes
+func redactAttr(attr slog.Attr) slog.Attr {
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, config.Redact(value.String()))
+	case slog.KindGroup:
+		group := value.Group()
+		redactedGroup := make([]any, len(group))
+		for i, groupAttr := range group {
+			redactedGroup[i] = redactAttr(groupAttr)
+		}
+		return slog.Group(attr.Key, redactedGroup...)
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			return slog.String(attr.Key, config.Redact(err.Error()))
+		}
+	}
+
+	return slog.Attr{Key: attr.Key, Value: value}
+}
@@ -0,0 +1,32 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+func TestHandlerRedacts(t *testing.T) {
+	config.Set(&config.Configs{Auth: config.AuthConfigs{ActionsSecret: "test-actions-secret"}})
+	defer config.Set(nil)
+
+	var output bytes.Buffer
+	logger := slog.New(NewHandler(&output, config.LogConfigs{Level: slog.LevelInfo, Format: "json"}))
+	logger.With("secret", "test-actions-secret").Info("request to http://pihole/admin/api.php?auth=abc123",
+		"error", errors.New("invalid token test-actions-secret"), slog.Group("request", "url", "http://pihole?auth=abc123"))
+	logger.Debug("not logged")
+
+	logs := output.String()
+	for _, secret := range []string{"test-actions-secret", "abc123", "not logged"} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("expected %q to not be in the logs: %s", secret, logs)
+		}
+	}
+	if strings.Count(logs, "REDACTED") != 4 {
+		t.Errorf("expected 4 redacted values: %s", logs)
+	}
+}
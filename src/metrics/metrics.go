@@ -0,0 +1,88 @@
+// Package metrics has the Prometheus metrics of the API, exposed in the /metrics route
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "homarr_iframes"
+
+var (
+	// SourceRequests counts the requests to the sources by source, instance, and response status code.
+	// The code is "error" if the request failed without a response, like when the source is down.
+	SourceRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "source_requests_total",
+		Help:      "Requests to the sources, by source, instance, and response status code.",
+	}, []string{"source", "instance", "code"})
+	// SourceRequestErrors counts the requests to the sources that failed or returned a 4xx or 5xx status code
+	SourceRequestErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "source_request_errors_total",
+		Help:      "Requests to the sources that failed or returned a 4xx or 5xx status code, by source and instance.",
+	}, []string{"source", "instance"})
+	// SourceRequestDuration is the duration of the requests to the sources, including the retries
+	SourceRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "source_request_duration_seconds",
+		Help:      "Duration of the requests to the sources, including the retries, by source and instance.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"source", "instance"})
+
+	// CacheRequests counts the reads of the iFrames data cache by source and result, "hit" or "miss"
+	CacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Reads of the iFrames data cache, by source and result (hit or miss).",
+	}, []string{"source", "result"})
+
+	// RenderDuration is the duration of the iFrame routes, including getting the data
+	RenderDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "iframe_render_duration_seconds",
+		Help:      "Duration of the iFrame routes, including getting the data, by widget.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"widget"})
+	// HashRequests counts the requests to the hash routes, used by the iFrames to check for updates
+	HashRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "hash_requests_total",
+		Help:      "Requests to the hash routes, used by the iFrames to check for updates, by widget.",
+	}, []string{"widget"})
+
+	// Alarms is the number of alarms of each source instance by status, from the last time they were polled
+	Alarms = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "alarms",
+		Help:      "Alarms of each source instance by status, from the last time the alarms were polled.",
+	}, []string{"source", "instance", "status"})
+	// AlarmsSourceUp is 1 if the alarms of a source instance were got in the last poll, or 0 if it failed
+	AlarmsSourceUp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "alarms_source_up",
+		Help:      "Whether the alarms of each source instance were got in the last poll (1) or not (0).",
+	}, []string{"source", "instance"})
+)
+
+// SetAlarms sets the number of alarms of a source instance by status,
+// removing the statuses the instance doesn't have anymore
+func SetAlarms(source, instance string, statuses []string) {
+	Alarms.DeletePartialMatch(prometheus.Labels{"source": source, "instance": instance})
+	counts := map[string]int{}
+	for _, status := range statuses {
+		counts[status]++
+	}
+	for status, count := range counts {
+		Alarms.WithLabelValues(source, instance, status).Set(float64(count))
+	}
+}
+
+// SetAlarmsSourceUp sets if the alarms of a source instance were got in the last poll
+func SetAlarmsSourceUp(source, instance string, up bool) {
+	value := 0.0
+	if up {
+		value = 1
+	}
+	AlarmsSourceUp.WithLabelValues(source, instance).Set(value)
+}
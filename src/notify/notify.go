@@ -0,0 +1,223 @@
+// Package notify sends messages to the notification targets, like ntfy and Discord
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// Level is the severity of a message, used to set the priority or color of the notifications
+type Level string
+
+const (
+	LevelInfo    Level = "info"
+	LevelSuccess Level = "success"
+	LevelWarning Level = "warning"
+	LevelFailure Level = "failure"
+)
+
+// Message is a notification
+type Message struct {
+	Title string
+	Body  string
+	Level Level
+}
+
+// Send sends a message to a notifier
+func Send(ctx context.Context, notifier config.NotifierConfigs, message Message) error {
+	if notifier.Type == "smtp" {
+		return sendEmail(ctx, notifier, message)
+	}
+
+	client := sources.HTTPClient(sources.NotifiersHTTPClient, notifier.Name)
+	req, err := newRequest(ctx, notifier, message)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("non-2xx status code: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// newRequest returns the request that sends a message to an HTTP notifier
+func newRequest(ctx context.Context, notifier config.NotifierConfigs, message Message) (*http.Request, error) {
+	var req *http.Request
+	var err error
+	switch notifier.Type {
+	case "ntfy":
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, notifier.URL, strings.NewReader(message.Body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Title", encodeHeader(message.Title))
+		req.Header.Set("Priority", ntfyPriorities[message.Level])
+		req.Header.Set("Tags", ntfyTags[message.Level])
+		if notifier.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+notifier.Token)
+		}
+		return req, nil
+	case "gotify":
+		req, err = newJSONRequest(ctx, strings.TrimSuffix(notifier.URL, "/")+"/message", map[string]any{
+			"title":    message.Title,
+			"message":  message.Body,
+			"priority": gotifyPriorities[message.Level],
+		})
+		if err == nil {
+			req.Header.Set("X-Gotify-Key", notifier.Token)
+		}
+	case "apprise":
+		req, err = newJSONRequest(ctx, notifier.URL, map[string]string{
+			"title": message.Title,
+			"body":  message.Body,
+			"type":  appriseTypes[message.Level],
+		})
+	case "discord":
+		req, err = newJSONRequest(ctx, notifier.URL, map[string]string{
+			"content": "**" + message.Title + "**\n" + message.Body,
+		})
+	case "slack":
+		req, err = newJSONRequest(ctx, notifier.URL, map[string]string{
+			"text": "*" + message.Title + "*\n" + message.Body,
+		})
+	default:
+		return nil, fmt.Errorf("unknown notifier type: %s", notifier.Type)
+	}
+
+	return req, err
+}
+
+var (
+	ntfyPriorities   = map[Level]string{LevelInfo: "default", LevelSuccess: "low", LevelWarning: "default", LevelFailure: "high"}
+	ntfyTags         = map[Level]string{LevelInfo: "bell", LevelSuccess: "white_check_mark", LevelWarning: "warning", LevelFailure: "rotating_light"}
+	gotifyPriorities = map[Level]int{LevelInfo: 4, LevelSuccess: 2, LevelWarning: 5, LevelFailure: 8}
+	appriseTypes     = map[Level]string{LevelInfo: "info", LevelSuccess: "success", LevelWarning: "warning", LevelFailure: "failure"}
+)
+
+func newJSONRequest(ctx context.Context, url string, body any) (*http.Request, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
+
+// sendEmail sends a message by SMTP. The smtp:// URLs use STARTTLS if the server supports it,
+// and the smtps:// URLs use implicit TLS, usually on port 465. The connection is closed if ctx is canceled.
+func sendEmail(ctx context.Context, notifier config.NotifierConfigs, message Message) error {
+	u, err := url.Parse(notifier.URL)
+	if err != nil {
+		return fmt.Errorf("error parsing SMTP URL: %w", err)
+	}
+	port := u.Port()
+	if port == "" {
+		port = "587"
+		if u.Scheme == "smtps" {
+			port = "465"
+		}
+	}
+	address := net.JoinHostPort(u.Hostname(), port)
+	timeout := config.Current().HTTP.Timeout
+
+	var conn net.Conn
+	dialer := &net.Dialer{Timeout: timeout}
+	if u.Scheme == "smtps" {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: u.Hostname()}}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return fmt.Errorf("error connecting to SMTP server: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+	// The SMTP client doesn't take a context, so the connection is closed to stop it
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	client, err := smtp.NewClient(conn, u.Hostname())
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error connecting to SMTP server: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && u.Scheme == "smtp" {
+		if err := client.StartTLS(&tls.Config{ServerName: u.Hostname()}); err != nil {
+			return fmt.Errorf("error starting TLS: %w", err)
+		}
+	}
+	if password, ok := u.User.Password(); ok {
+		if err := client.Auth(smtp.PlainAuth("", u.User.Username(), password, u.Hostname())); err != nil {
+			return fmt.Errorf("error authenticating to SMTP server: %w", err)
+		}
+	}
+
+	if err := client.Mail(notifier.From); err != nil {
+		return fmt.Errorf("error sending email: %w", err)
+	}
+	for _, to := range notifier.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("error sending email to %s: %w", to, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("error sending email: %w", err)
+	}
+	if _, err := w.Write(emailContent(notifier, message)); err != nil {
+		return fmt.Errorf("error sending email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("error sending email: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// emailContent returns the headers and body of an email
+func emailContent(notifier config.NotifierConfigs, message Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", notifier.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(notifier.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", encodeHeader(message.Title))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(message.Body, "\r\n", "\n"), "\n", "\r\n"))
+	buf.WriteString("\r\n")
+
+	return buf.Bytes()
+}
+
+// encodeHeader encodes a header value with non-ASCII characters or line breaks,
+// so a title with line breaks can't add headers
+func encodeHeader(s string) string {
+	return mime.QEncoding.Encode("UTF-8", s)
+}
@@ -0,0 +1,168 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+func TestSend(t *testing.T) {
+	config.Set(&config.Configs{HTTP: config.HTTPConfigs{Timeout: 5 * time.Second}})
+	defer config.Set(nil)
+
+	type request struct {
+		path   string
+		header http.Header
+		body   string
+	}
+	requests := make(chan request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- request{r.URL.Path, r.Header, string(body)}
+	}))
+	defer server.Close()
+
+	message := Message{Title: "CRITICAL: High CPU", Body: "Netdata: 95%", Level: LevelFailure}
+	tests := []struct {
+		notifier config.NotifierConfigs
+		path     string
+		body     map[string]any
+		header   map[string]string
+	}{
+		{
+			notifier: config.NotifierConfigs{Type: "ntfy", URL: server.URL + "/homelab", Token: "ntfy-token"},
+			path:     "/homelab",
+			header:   map[string]string{"Title": "CRITICAL: High CPU", "Priority": "high", "Authorization": "Bearer ntfy-token"},
+		},
+		{
+			notifier: config.NotifierConfigs{Type: "gotify", URL: server.URL + "/", Token: "gotify-token"},
+			path:     "/message",
+			body:     map[string]any{"title": "CRITICAL: High CPU", "message": "Netdata: 95%", "priority": float64(8)},
+			header:   map[string]string{"X-Gotify-Key": "gotify-token"},
+		},
+		{
+			notifier: config.NotifierConfigs{Type: "apprise", URL: server.URL + "/notify/homelab"},
+			path:     "/notify/homelab",
+			body:     map[string]any{"title": "CRITICAL: High CPU", "body": "Netdata: 95%", "type": "failure"},
+		},
+		{
+			notifier: config.NotifierConfigs{Type: "discord", URL: server.URL + "/api/webhooks/1/token"},
+			path:     "/api/webhooks/1/token",
+			body:     map[string]any{"content": "**CRITICAL: High CPU**\nNetdata: 95%"},
+		},
+		{
+			notifier: config.NotifierConfigs{Type: "slack", URL: server.URL + "/services/token"},
+			path:     "/services/token",
+			body:     map[string]any{"text": "*CRITICAL: High CPU*\nNetdata: 95%"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.notifier.Type, func(t *testing.T) {
+			if err := Send(context.Background(), test.notifier, message); err != nil {
+				t.Fatal(err)
+			}
+			r := <-requests
+			if r.path != test.path {
+				t.Errorf("expected path %s, got %s", test.path, r.path)
+			}
+			for name, value := range test.header {
+				if r.header.Get(name) != value {
+					t.Errorf("expected header %s to be %q, got %q", name, value, r.header.Get(name))
+				}
+			}
+			if test.body == nil {
+				if r.body != message.Body {
+					t.Errorf("expected body %q, got %q", message.Body, r.body)
+				}
+				return
+			}
+			var body map[string]any
+			if err := json.Unmarshal([]byte(r.body), &body); err != nil {
+				t.Fatal(err)
+			}
+			for key, value := range test.body {
+				if body[key] != value {
+					t.Errorf("expected %s to be %v, got %v", key, value, body[key])
+				}
+			}
+		})
+	}
+}
+
+func TestSendReusesConnections(t *testing.T) {
+	config.Set(&config.Configs{HTTP: config.HTTPConfigs{Timeout: 5 * time.Second}})
+	defer config.Set(nil)
+
+	var connections atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	notifier := config.NotifierConfigs{Name: "reused", Type: "ntfy", URL: server.URL + "/homelab"}
+	for range 3 {
+		if err := Send(context.Background(), notifier, Message{Title: "Disk full", Level: LevelWarning}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := connections.Load(); n != 1 {
+		t.Errorf("expected the notifications to reuse a connection, got %d connections", n)
+	}
+}
+
+func TestSendEmailCanceled(t *testing.T) {
+	config.Set(&config.Configs{HTTP: config.HTTPConfigs{Timeout: time.Minute}})
+	defer config.Set(nil)
+
+	// The server accepts the connection but never sends its greeting
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	notifier := config.NotifierConfigs{Type: "smtp", URL: "smtp://" + listener.Addr().String(), From: "homarr-iframes@domain.com", To: []string{"me@domain.com"}}
+	start := time.Now()
+	if err := Send(ctx, notifier, Message{Title: "Disk full"}); err == nil {
+		t.Fatal("expected an error when the context is canceled")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the email to be canceled with the context, took %s", elapsed)
+	}
+}
+
+func TestEmailContent(t *testing.T) {
+	notifier := config.NotifierConfigs{From: "homarr-iframes@domain.com", To: []string{"me@domain.com", "you@domain.com"}}
+	content := string(emailContent(notifier, Message{Title: "High CPU\r\nBcc: someone@domain.com", Body: "Netdata\n95%"}))
+
+	if strings.Contains(content, "\r\nBcc:") {
+		t.Errorf("the title should not add headers, got %q", content)
+	}
+	if !strings.Contains(content, "To: me@domain.com, you@domain.com\r\n") || !strings.HasSuffix(content, "\r\n\r\nNetdata\r\n95%\r\n") {
+		t.Errorf("unexpected email content: %q", content)
+	}
+}
@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// AdminRoutes registers the admin routes. They require the ACTIONS_SECRET as
+// a bearer token, or the trusted header set by an authentication proxy.
+func AdminRoutes(group *gin.RouterGroup) {
+	group = group.Group("/admin", adminAuth())
+	group.POST("/reload", reloadHandler)
+}
+
+// ReloadResponse is the response of the reload route
+type ReloadResponse struct {
+	Message string `json:"message"`
+	// Changed are the names of the integrations whose configs changed
+	Changed []string `json:"changed"`
+}
+
+// @Summary Reload configs
+// @Description Loads the configs again from the environment variables, the .env file, and the config file, and recreates the clients of the sources whose configs changed. If the new configs are invalid, the current ones are kept. Requires the ACTIONS_SECRET as a bearer token or the AUTH_TRUSTED_HEADER.
+// @Success 200 {object} ReloadResponse "Configs reloaded"
+// @Failure 400 {object} sources.MessageResponse "Invalid configs"
+// @Failure 401 {object} sources.MessageResponse "Unauthorized"
+// @Produce json
+// @Param Authorization header string false "Bearer ACTIONS_SECRET."
+// @Router /admin/reload [post]
+func reloadHandler(c *gin.Context) {
+	changed, err := sources.Reload()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "error reloading configs, keeping the current ones: " + err.Error()})
+		return
+	}
+	if changed == nil {
+		changed = []string{}
+	}
+
+	c.JSON(http.StatusOK, ReloadResponse{Message: "configs reloaded", Changed: changed})
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources/alarms"
+)
+
+// AlarmsRoutes registers the routes that receive alarms from external tools, shown by the
+// webhook alarms source. They require the ACTIONS_SECRET as a bearer token, or the trusted header.
+func AlarmsRoutes(group *gin.RouterGroup) {
+	group = group.Group("/alarms", adminAuth())
+	group.POST("/ingest", alarms.IngestHandler)
+}
@@ -0,0 +1,113 @@
+package routes
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// actionAuth returns a middleware that only allows authenticated requests from allowed origins to an action route.
+// A request is authenticated if it has the ACTIONS_SECRET as a bearer token, the trusted header set by an
+// authentication proxy, or a valid action token for the item in the X-Action-Token header.
+func actionAuth(name string, action sources.Action) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !allowedOrigin(c.Request) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "origin not allowed"})
+			return
+		}
+
+		if authenticated(c) {
+			c.Next()
+			return
+		}
+		if token := c.GetHeader("X-Action-Token"); token != "" {
+			if sources.VerifyActionToken(c.Request, token, name, action.Path, c.Query(action.Item)) {
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "unauthorized, provide a valid action token or the actions secret"})
+	}
+}
+
+// adminAuth returns a middleware that only allows requests with the ACTIONS_SECRET as a bearer
+// token or the trusted header set by an authentication proxy, from allowed origins
+func adminAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !allowedOrigin(c.Request) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "origin not allowed"})
+			return
+		}
+		if !authenticated(c) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "unauthorized, provide the actions secret as a bearer token"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// readAuth returns a middleware that only allows authenticated requests to the read routes,
+// like the iFrames and data routes, if AUTH_REQUIRED is true
+func readAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if config.Current().Auth.Required && !authenticated(c) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "unauthorized, provide the actions secret as a bearer token or authenticate with the authentication proxy"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// authenticated returns true if the request has the ACTIONS_SECRET as a bearer token,
+// or the trusted header set by an authentication proxy in the AUTH_TRUSTED_PROXIES
+func authenticated(c *gin.Context) bool {
+	authConfigs := config.Current().Auth
+	if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(authConfigs.ActionsSecret)) == 1 {
+			return true
+		}
+	}
+	if authConfigs.TrustedHeader == "" || c.GetHeader(authConfigs.TrustedHeader) == "" {
+		return false
+	}
+	address, _, err := net.SplitHostPort(c.Request.RemoteAddr)
+	if err != nil {
+		address = c.Request.RemoteAddr
+	}
+
+	return authConfigs.TrustedProxy(address)
+}
+
+// allowedOrigin returns false if the request comes from another site, unless its origin is in ACTIONS_ALLOWED_ORIGINS.
+// Requests without the Origin header, like the ones from scripts, are allowed if the browser doesn't say they are cross-site.
+func allowedOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || origin == "null" {
+		return r.Header.Get("Sec-Fetch-Site") != "cross-site"
+	}
+	if slices.Contains(config.Current().Auth.AllowedOrigins, origin) {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if u.Host == r.Host {
+		return true
+	}
+	forwardedHost := r.Header.Get("X-Forwarded-Host")
+
+	return forwardedHost != "" && u.Host == forwardedHost
+}
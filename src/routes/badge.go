@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources/alarms"
+)
+
+// BadgeRoutes registers the SVG badge routes, which take the same parameters as the data route of the source
+func BadgeRoutes(group *gin.RouterGroup) {
+	group = group.Group("/badge", setSource("alarms"), widgetDefaults("alarms"))
+	group.GET("/alarms.svg", alarms.BadgeHandler)
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources/media"
+)
+
+// CalendarRoutes registers the iCalendar feed routes, which take the same parameters as the data route of the source.
+// The widget defaults aren't applied, as they are meant for the iFrame, like a shorter date range than the feed one.
+func CalendarRoutes(group *gin.RouterGroup) {
+	group = group.Group("/calendar", setSource("media_releases"))
+	group.GET("/media.ics", media.GetICS)
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// DataRoutes registers the data route of every registered source
+func DataRoutes(group *gin.RouterGroup) {
+	group = group.Group("/data")
+	for _, integration := range sources.All() {
+		if integration.Data != nil {
+			group.GET("/"+integration.Name, setSource(integration.Name), readAuth(), widgetDefaults(integration.Name), integration.Data)
+		}
+	}
+}
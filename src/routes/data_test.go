@@ -0,0 +1,43 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+func TestGetData(t *testing.T) {
+	urls := map[string]string{
+		"Get Linkwarden data":     "/v1/data/linkwarden",
+		"Get Cinemark data":       "/v1/data/cinemark?theaterIds=2133",
+		"Get Vikunja data":        "/v1/data/vikunja",
+		"Get Media Releases data": "/v1/data/media_releases",
+		"Get Media Requests data": "/v1/data/media_requests",
+		"Get Uptime Kuma data":    "/v1/data/uptimekuma?slug=general",
+		"Get Alarms data":         "/v1/data/alarms?alarms=sonarr,radarr,lidarr,prowlarr,kavita,pihole,speedtest-tracker,netdata,changedetectionio,kaizoku",
+	}
+	for name, url := range urls {
+		t.Run(name, func(t *testing.T) {
+			r, err := requestHelper(http.MethodGet, url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if r.Code != http.StatusOK {
+				t.Fatalf("expected status code 200, got %d: %s", r.Code, r.Body.String())
+			}
+
+			var response struct {
+				Version int `json:"version"`
+			}
+			if err := json.Unmarshal(r.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.Version != sources.DataVersion {
+				t.Fatalf("expected version %d, got %d", sources.DataVersion, response.Version)
+			}
+		})
+	}
+}
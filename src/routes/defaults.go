@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+// widgetDefaults returns a middleware that adds the default query parameters of an iFrame
+// set in the config file to the request, if the request doesn't have them
+func widgetDefaults(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defaults := config.Current().WidgetDefaults(name)
+		if len(defaults) == 0 {
+			c.Next()
+			return
+		}
+
+		query := c.Request.URL.Query()
+		for param, values := range defaults {
+			if _, ok := query[param]; !ok {
+				query[param] = values
+			}
+		}
+		c.Request.URL.RawQuery = query.Encode()
+		c.Next()
+	}
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// EventsRoutes registers the events route of every registered source with an iFrame
+//
+// @Summary iFrame live updates
+// @Description Server-Sent Events stream used by the iFrames to update without reloading. Sends an "update" event with the iFrame HTML as a JSON string when the iFrame changes, and an "error" event if the iFrame can't be created. Accepts the same query parameters as the iFrame route.
+// @Success 200 {string} string "Event stream"
+// @Produce text/event-stream
+// @Param name path string true "iFrame name, like vikunja or media_releases." Example(vikunja)
+// @Router /events/{name} [get]
+func EventsRoutes(group *gin.RouterGroup) {
+	group = group.Group("/events")
+	for _, integration := range sources.All() {
+		if integration.IFrame != nil {
+			group.GET("/"+integration.Name, setSource(integration.Name), readAuth(), widgetDefaults(integration.Name), sources.EventsHandler(integration))
+		}
+	}
+}
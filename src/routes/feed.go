@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources/alarms"
+)
+
+// FeedRoutes registers the alarm feeds routes, which take the same parameters as the alarms data route
+func FeedRoutes(group *gin.RouterGroup) {
+	group = group.Group("/feed", setSource("alarms"), widgetDefaults("alarms"))
+	group.GET("/alarms.rss", alarms.RSSFeedHandler)
+	group.GET("/alarms.atom", alarms.AtomFeedHandler)
+	group.GET("/alarms.json", alarms.JSONFeedHandler)
+}
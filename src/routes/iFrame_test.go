@@ -126,8 +126,14 @@ func TestGetIFrames(t *testing.T) {
 }
 
 func TestSetVikunjaTaskDone(t *testing.T) {
+	defer config.Set(config.Current())
+	configs := *config.Current()
+	configs.Auth.ActionsSecret = "test-actions-secret"
+	config.Set(&configs)
+
 	t.Run("Set Vikunja Task Done", func(t *testing.T) {
-		r, err := requestHelper(http.MethodPatch, "/v1/iframe/vikunja/set_task_done?taskId=1", nil)
+		header := http.Header{"Authorization": {"Bearer " + configs.Auth.ActionsSecret}}
+		r, err := requestWithHeader(http.MethodPatch, "/v1/iframe/vikunja/set_task_done?taskId=1", header)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -148,11 +154,18 @@ func TestSetVikunjaTaskDone(t *testing.T) {
 }
 
 func requestHelper(method, url string, target any) (*httptest.ResponseRecorder, error) {
+	return requestWithHeader(method, url, nil)
+}
+
+func requestWithHeader(method, url string, header http.Header) (*httptest.ResponseRecorder, error) {
 	r := httptest.NewRecorder()
 	req, err := http.NewRequest(method, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
 
 	router := api.SetupRouter()
 	router.ServeHTTP(r, req)
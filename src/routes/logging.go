@@ -0,0 +1,123 @@
+package routes
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+const (
+	// requestIDKey is the context key of the request ID, also sent in the X-Request-ID header
+	requestIDKey = "request_id"
+	// sourceKey is the context key of the source name of the route
+	sourceKey = "source"
+	// maxLoggedErrorLength is the maximum length of an error response body in the logs
+	maxLoggedErrorLength = 1024
+)
+
+// RequestLogger returns a middleware that sets a request ID and logs the requests. The successful ones
+// are logged in the debug level, as the iFrames request the hash routes very often, the 4xx responses in the warn
+// level, and the 5xx ones in the error level. The secrets are redacted from the error responses.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" || len(requestID) > 64 {
+			requestID = newRequestID()
+		}
+		c.Set(requestIDKey, requestID)
+		c.Header("X-Request-ID", requestID)
+
+		writer := &redactWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelDebug
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		} else if status >= http.StatusBadRequest {
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("request_id", requestID),
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if source := c.GetString(sourceKey); source != "" {
+			attrs = append(attrs, slog.String("source", source))
+		}
+		if message := writer.errorMessage(); message != "" {
+			attrs = append(attrs, slog.String("error", message))
+		}
+		slog.LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// setSource returns a middleware that sets the source name of a route, used in the logs
+func setSource(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(sourceKey, name)
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
+// redactWriter redacts the secrets from the error responses, and keeps the
+// start of their body to be logged. Successful responses are written as they are.
+type redactWriter struct {
+	gin.ResponseWriter
+	errorBody []byte
+}
+
+func (w *redactWriter) Write(data []byte) (int, error) {
+	if w.Status() < http.StatusBadRequest {
+		return w.ResponseWriter.Write(data)
+	}
+
+	redactedData := []byte(config.Redact(string(data)))
+	if len(w.errorBody) < maxLoggedErrorLength {
+		w.errorBody = append(w.errorBody, redactedData[:min(len(redactedData), maxLoggedErrorLength-len(w.errorBody))]...)
+	}
+	if _, err := w.ResponseWriter.Write(redactedData); err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+func (w *redactWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// errorMessage returns the message of a JSON error response, like {"message": "..."}, or the start of its body
+func (w *redactWriter) errorMessage() string {
+	if len(w.errorBody) == 0 {
+		return ""
+	}
+	var response struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(w.errorBody, &response); err == nil && response.Message != "" {
+		return response.Message
+	}
+
+	return string(w.errorBody)
+}
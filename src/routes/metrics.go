@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/diogovalentte/homarr-iframes/src/metrics"
+)
+
+// MetricsRoute registers the /metrics route with the Prometheus metrics
+func MetricsRoute(router *gin.Engine) {
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+}
+
+// renderMetrics returns a middleware that records the duration of an iFrame route
+func renderMetrics(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		metrics.RenderDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	}
+}
+
+// hashMetrics returns a middleware that counts the requests to a hash route
+func hashMetrics(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		metrics.HashRequests.WithLabelValues(name).Inc()
+		c.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package routes
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// StatusRoute registers the status route. It requires authentication, like the admin routes,
+// because it requests every configured source and shows which instances are configured.
+func StatusRoute(group *gin.RouterGroup) {
+	group.GET("/status", adminAuth(), statusHandler)
+}
+
+// @Summary Integrations status
+// @Description Checks every configured integration instance and returns if its config is complete, if it's reachable, if the credentials are valid, its version, and the request latency. Used to find out why an iFrame doesn't work. Requires the ACTIONS_SECRET as a bearer token or the AUTH_TRUSTED_HEADER.
+// @Success 200 {array} sources.Status
+// @Produce json
+// @Produce plain
+// @Param format query string false "Response format, 'json' or 'table'. Defaults to json." Example(table)
+// @Router /status [get]
+func statusHandler(c *gin.Context) {
+	format := c.Query("format")
+	if format != "" && format != "json" && format != "table" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "format must be 'json' or 'table'"})
+		return
+	}
+
+	statuses := sources.Statuses()
+	if format == "table" {
+		var table bytes.Buffer
+		if err := sources.WriteStatusTable(&table, statuses); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+		c.String(http.StatusOK, table.String())
+		return
+	}
+
+	if statuses == nil {
+		statuses = []sources.Status{}
+	}
+	c.JSON(http.StatusOK, statuses)
+}
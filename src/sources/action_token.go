@@ -0,0 +1,98 @@
+package sources
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+// ActionTokenTTL is how long an action token is valid. The iFrames with actions are rendered
+// again by the events route before their tokens expire, so the opened iFrames keep working.
+var ActionTokenTTL = 10 * time.Minute
+
+var actionTokenNow = time.Now
+
+// ActionSession creates the action tokens of an iFrame render. Each render has a random ID, and its
+// tokens are only valid for the client that requested the iFrame, until ActionTokenTTL after the render.
+type ActionSession struct {
+	id         string
+	client     string
+	expiration string
+}
+
+// NewActionSession returns the action session of an iFrame render requested by a client
+func NewActionSession(r *http.Request) ActionSession {
+	id := make([]byte, 12)
+	_, _ = rand.Read(id)
+
+	return ActionSession{
+		id:         base64.RawURLEncoding.EncodeToString(id),
+		client:     ClientIP(r),
+		expiration: strconv.FormatInt(actionTokenNow().Add(ActionTokenTTL).Unix(), 10),
+	}
+}
+
+// Token returns a token that allows the iFrame to do an action on an item, like setting a task as done.
+// The token is only valid for the action (name and path) and item, like ("vikunja", "set_task_done", "12").
+func (s ActionSession) Token(name, path, item string) string {
+	return s.expiration + "." + s.id + "." + signActionToken(name, path, item, s.expiration, s.id, s.client)
+}
+
+// VerifyActionToken returns true if the token was created by ActionSession.Token for the action and item,
+// isn't expired, and the request comes from the client that requested the iFrame.
+func VerifyActionToken(r *http.Request, token, name, path, item string) bool {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	expirationStr, id, signature := parts[0], parts[1], parts[2]
+	expiration, err := strconv.ParseInt(expirationStr, 10, 64)
+	if err != nil || actionTokenNow().Unix() > expiration {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(signActionToken(name, path, item, expirationStr, id, ClientIP(r))))
+}
+
+func signActionToken(name, path, item, expiration, session, client string) string {
+	mac := hmac.New(sha256.New, []byte(config.Current().Auth.ActionsSecret))
+	mac.Write([]byte(name + "\n" + path + "\n" + item + "\n" + expiration + "\n" + session + "\n" + client))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ClientIP returns the IP address of the client of a request. The X-Forwarded-For header is only
+// used if the request comes from one of the AUTH_TRUSTED_PROXIES, so the clients can't change their address.
+func ClientIP(r *http.Request) string {
+	address, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		address = r.RemoteAddr
+	}
+
+	authConfigs := config.Current().Auth
+	if !authConfigs.TrustedProxy(address) {
+		return address
+	}
+	// The proxies append the address of their client, so the last untrusted address is the client's
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if hop == "" {
+			continue
+		}
+		address = hop
+		if !authConfigs.TrustedProxy(hop) {
+			break
+		}
+	}
+
+	return address
+}
@@ -0,0 +1,80 @@
+package sources
+
+import (
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+func TestActionToken(t *testing.T) {
+	config.Set(&config.Configs{Auth: config.AuthConfigs{ActionsSecret: "test-actions-secret"}})
+	defer config.Set(nil)
+	now := time.Now()
+	actionTokenNow = func() time.Time { return now }
+	defer func() { actionTokenNow = time.Now }()
+
+	request := httptest.NewRequest("GET", "/v1/iframe/vikunja", nil)
+	request.RemoteAddr = "192.168.1.10:51000"
+	session := NewActionSession(request)
+	token := session.Token("vikunja", "set_task_done", "12")
+	if !VerifyActionToken(request, token, "vikunja", "set_task_done", "12") {
+		t.Fatal("expected valid token")
+	}
+	if VerifyActionToken(request, token, "vikunja", "set_task_done", "13") {
+		t.Fatal("token should not be valid for another item")
+	}
+	if VerifyActionToken(request, token, "linkwarden", "delete_link", "12") {
+		t.Fatal("token should not be valid for another action")
+	}
+	if VerifyActionToken(request, "4102444800."+token[len("4102444800."):], "vikunja", "set_task_done", "12") {
+		t.Fatal("token with a changed expiration should not be valid")
+	}
+	if VerifyActionToken(request, "invalid", "vikunja", "set_task_done", "12") {
+		t.Fatal("invalid token should not be valid")
+	}
+	if NewActionSession(request).Token("vikunja", "set_task_done", "12") == token {
+		t.Fatal("each render should have different tokens")
+	}
+
+	other := httptest.NewRequest("DELETE", "/v1/iframe/vikunja/set_task_done", nil)
+	other.RemoteAddr = "192.168.1.20:51000"
+	if VerifyActionToken(other, token, "vikunja", "set_task_done", "12") {
+		t.Fatal("token should not be valid for another client")
+	}
+	other.Header.Set("X-Forwarded-For", "192.168.1.10")
+	if VerifyActionToken(other, token, "vikunja", "set_task_done", "12") {
+		t.Fatal("the X-Forwarded-For header of untrusted clients should be ignored")
+	}
+
+	now = now.Add(ActionTokenTTL + time.Second)
+	if VerifyActionToken(request, token, "vikunja", "set_task_done", "12") {
+		t.Fatal("expired token should not be valid")
+	}
+
+	config.Set(&config.Configs{Auth: config.AuthConfigs{ActionsSecret: "another-actions-secret"}})
+	now = time.Now()
+	if VerifyActionToken(request, token, "vikunja", "set_task_done", "12") {
+		t.Fatal("token signed with another secret should not be valid")
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	_, proxies, _ := net.ParseCIDR("172.18.0.0/16")
+	config.Set(&config.Configs{Auth: config.AuthConfigs{TrustedProxies: []*net.IPNet{proxies}}})
+	defer config.Set(nil)
+
+	request := httptest.NewRequest("GET", "/v1/iframe/vikunja", nil)
+	request.RemoteAddr = "172.18.0.2:40000"
+	request.Header.Add("X-Forwarded-For", "10.0.0.1, 192.168.1.10")
+	request.Header.Add("X-Forwarded-For", "172.18.0.3")
+	if ip := ClientIP(request); ip != "192.168.1.10" {
+		t.Errorf("expected the last untrusted forwarded address, got %s", ip)
+	}
+	request.RemoteAddr = "192.168.1.20:40000"
+	if ip := ClientIP(request); ip != "192.168.1.20" {
+		t.Errorf("expected the remote address of an untrusted client, got %s", ip)
+	}
+}
@@ -0,0 +1,141 @@
+package sources
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Alarm is the alarm format every integration with alarms should return.
+// It's used by the alarms iFrame.
+type Alarm struct {
+	// Time: time related to the alarm
+	Time time.Time
+	// Summary: like "Low disk space on /dev/sda1"
+	Summary string
+	// URL: an URL to be used in the link of the alarm
+	URL string
+	// Status: like "CLEAR", "WARNING", "ERROR", or "CRITICAL"
+	// Prefer to uppercase the status
+	Status string
+	// Severity: the normalized severity of the alarm, used to filter and color it.
+	// If not set, it's got from the status, see StatusSeverity.
+	Severity Severity
+	// Value: a value related to the alarm, like "12GB free"
+	Value string
+	// Property: custom property to be used in the alarm card
+	Property string
+	// Source: like "Netdata", "Radarr", etc.
+	Source string
+	// Instance: name of the source instance, like "4k". Empty for the default instance.
+	Instance string
+	// BackgroundImgURL: URL to an image to be used as background of the alarm card
+	BackgroundImgURL string
+	// BackgroundColor: Color to be used as background of the alarm card if no BackgroundImgURL is set
+	BackgroundColor string
+	// BackgroundImgSize: Size of the background image in %, like 80 or 102.5
+	BackgroundImgSize float32
+	// Maintenance: name of the maintenance window the alarm is in, set by the alarms iFrame. Empty if it's not in one.
+	Maintenance string
+	// MaintenanceAction: "dim" or "mark", what the alarms iFrame does with the alarm in the maintenance window
+	MaintenanceAction string
+}
+
+func (a Alarm) String() string {
+	return fmt.Sprintf("Alarm{Time: %s, Summary: %s, URL: %s, Status: %s, Severity: %s, Value: %s, Property: %s, Source: %s, Instance: %s, BackgroundImgURL: %s, BackgroundColor: %s, BackgroundImgSize: %.2f, Maintenance: %s, MaintenanceAction: %s}",
+		a.Time.Format(time.RFC3339), a.Summary, a.URL, a.Status, a.NormalizedSeverity(), a.Value, a.Property, a.Source, a.Instance, a.BackgroundImgURL, a.BackgroundColor, a.BackgroundImgSize, a.Maintenance, a.MaintenanceAction)
+}
+
+// NormalizedSeverity returns the severity of the alarm, or the severity of its status if it's not set
+func (a Alarm) NormalizedSeverity() Severity {
+	if a.Severity != SeverityUnknown {
+		return a.Severity
+	}
+
+	return StatusSeverity(a.Status)
+}
+
+// Severity is the normalized severity of an alarm, as each source has its own statuses.
+// The severities are ordered, so an alarm can be compared with a minimum severity.
+type Severity int
+
+const (
+	// SeverityUnknown is the zero value, used when the severity is not set
+	SeverityUnknown Severity = iota
+	SeverityOK
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+// Severities are the names of the severities, from the lowest to the highest
+var Severities = []string{"ok", "info", "warning", "error", "critical"}
+
+// statusSeverities are the severities of the statuses returned by the sources
+var statusSeverities = map[string]Severity{
+	"CLEAR":   SeverityOK,
+	"OK":      SeverityOK,
+	"SUCCESS": SeverityOK,
+	"NOTICE":  SeverityInfo,
+	"INFO":    SeverityInfo,
+	// Speedtest Tracker unhealthy results, shown as neutral like before the severities
+	"DOWNLOAD": SeverityInfo,
+	"UPLOAD":   SeverityInfo,
+	"PING":     SeverityInfo,
+	"WARNING":  SeverityWarning,
+	"WARN":     SeverityWarning,
+	"CHANGED":  SeverityWarning,
+	"ERROR":    SeverityError,
+	"FAILED":   SeverityError,
+	"CRITICAL": SeverityCritical,
+	"FATAL":    SeverityCritical,
+}
+
+// StatusSeverity returns the severity of a status, like "WARNING" or "CLEAR".
+// Unknown statuses, like the Netdata "UNDEFINED", are info.
+func StatusSeverity(status string) Severity {
+	if severity, ok := statusSeverities[strings.ToUpper(status)]; ok {
+		return severity
+	}
+
+	return SeverityInfo
+}
+
+// ParseSeverity parses a severity name, like "warning"
+func ParseSeverity(name string) (Severity, error) {
+	for i, severityName := range Severities {
+		if strings.EqualFold(name, severityName) {
+			return Severity(i + 1), nil
+		}
+	}
+
+	return SeverityUnknown, fmt.Errorf("severity '%s' is not valid. Valid severities are: %s", name, strings.Join(Severities, ", "))
+}
+
+// String returns the severity name, like "warning"
+func (s Severity) String() string {
+	if s <= SeverityUnknown || int(s) > len(Severities) {
+		return "unknown"
+	}
+
+	return Severities[s-1]
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	if string(text) == "unknown" || len(text) == 0 {
+		*s = SeverityUnknown
+		return nil
+	}
+	severity, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = severity
+
+	return nil
+}
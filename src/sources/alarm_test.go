@@ -0,0 +1,48 @@
+package sources
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSeverity(t *testing.T) {
+	statuses := map[string]Severity{
+		"CLEAR":     SeverityOK,
+		"notice":    SeverityInfo,
+		"CHANGED":   SeverityWarning,
+		"UNDEFINED": SeverityInfo,
+		"DOWNLOAD":  SeverityInfo,
+		"WARNING":   SeverityWarning,
+		"FAILED":    SeverityError,
+		"CRITICAL":  SeverityCritical,
+	}
+	for status, expected := range statuses {
+		if actual := (Alarm{Status: status}).NormalizedSeverity(); actual != expected {
+			t.Errorf("status %s: expected %s, got %s", status, expected, actual)
+		}
+	}
+	if severity := (Alarm{Status: "WARNING", Severity: SeverityCritical}).NormalizedSeverity(); severity != SeverityCritical {
+		t.Errorf("expected the severity set by the source, got %s", severity)
+	}
+
+	if severity, err := ParseSeverity("Error"); err != nil || severity != SeverityError {
+		t.Errorf("expected error, got %s (%v)", severity, err)
+	}
+	if _, err := ParseSeverity("high"); err == nil {
+		t.Error("expected an error parsing an invalid severity")
+	}
+
+	data, err := json.Marshal(Alarm{Severity: SeverityWarning})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var alarm Alarm
+	if err := json.Unmarshal(data, &alarm); err != nil || alarm.Severity != SeverityWarning {
+		t.Errorf("expected the severity to be unmarshaled, got %s (%v)", alarm.Severity, err)
+	}
+	// Alarms stored before the severity existed
+	var oldAlarm Alarm
+	if err := json.Unmarshal([]byte(`{"Status": "ERROR"}`), &oldAlarm); err != nil || oldAlarm.NormalizedSeverity() != SeverityError {
+		t.Errorf("expected the severity of the status, got %s (%v)", oldAlarm.NormalizedSeverity(), err)
+	}
+}
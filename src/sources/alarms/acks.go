@@ -0,0 +1,269 @@
+package alarms
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+// historyAcksBucket has the acknowledgments, by ack fingerprint
+var historyAcksBucket = []byte("acks")
+
+// ackFingerprintRegex validates the fingerprint query parameter of the acknowledge and snooze routes
+var ackFingerprintRegex = regexp.MustCompile(`^[0-9a-f]{16}$`)
+
+// memoryAcks keeps the acknowledgments when the history is disabled, so they're lost on restart
+var memoryAcks = newMemoryAckStore()
+
+// AckFingerprint returns the ID of an alarm and its content, used by the acknowledgments. Unlike
+// Fingerprint, it uses the property, so an acknowledgment ends when the alarm changes.
+func AckFingerprint(alarm Alarm) string {
+	hash := sha256.Sum256([]byte(alarm.Source + "\x00" + alarm.Instance + "\x00" + alarm.Summary + "\x00" + alarm.Property + "\x00" + alarm.Status))
+
+	return hex.EncodeToString(hash[:8])
+}
+
+// Ack is an acknowledgment or snooze of an alarm. Acknowledged alarms are dimmed or hidden,
+// and snoozed ones are hidden. Both end when the alarm changes, as it has another ack fingerprint.
+type Ack struct {
+	Fingerprint string    `json:"fingerprint"`
+	CreatedAt   time.Time `json:"created_at"`
+	// Snoozed is true if the alarm is hidden, false if it's only acknowledged
+	Snoozed bool `json:"snoozed"`
+	// Until is when the snooze ends, nil if it lasts until the alarm changes
+	Until *time.Time `json:"until,omitempty"`
+}
+
+// Expired returns true if the snooze ended
+func (a Ack) Expired(now time.Time) bool {
+	return a.Until != nil && now.After(*a.Until)
+}
+
+// stale returns true if the acknowledgment expired or was created before the retention,
+// as the alarm it acknowledges probably changed or is gone
+func (a Ack) stale(now time.Time, retention time.Duration) bool {
+	return a.Expired(now) || (retention > 0 && a.CreatedAt.Before(now.Add(-retention)))
+}
+
+// ackStore stores the acknowledgments
+type ackStore interface {
+	// Acks returns the acknowledgments that didn't expire, by ack fingerprint
+	Acks() (map[string]Ack, error)
+	PutAck(ack Ack) error
+	DeleteAck(fingerprint string) error
+}
+
+// getAckStore returns the history if it's enabled, so the acknowledgments
+// are kept after a restart, or a store in memory otherwise
+func getAckStore() (ackStore, error) {
+	history, err := getHistory()
+	if err != nil {
+		return nil, err
+	}
+	if history == nil {
+		return memoryAcks, nil
+	}
+
+	return history, nil
+}
+
+// applyAcks removes the snoozed alarms and marks the acknowledged ones.
+// If hideAcknowledged is true, the acknowledged alarms are removed too.
+func applyAcks(alarms []trackedAlarm, hideAcknowledged bool) ([]trackedAlarm, error) {
+	store, err := getAckStore()
+	if err != nil {
+		return nil, err
+	}
+	acks, err := store.Acks()
+	if err != nil {
+		return nil, err
+	}
+	if len(acks) == 0 {
+		return alarms, nil
+	}
+
+	result := make([]trackedAlarm, 0, len(alarms))
+	for _, alarm := range alarms {
+		ack, ok := acks[alarm.AckFingerprint]
+		if ok && (ack.Snoozed || hideAcknowledged) {
+			continue
+		}
+		alarm.Acknowledged = ok
+		result = append(result, alarm)
+	}
+
+	return result, nil
+}
+
+// acknowledge acknowledges or snoozes an alarm. If snoozed, the alarm is hidden for duration,
+// or until it changes if duration is zero.
+func acknowledge(fingerprint string, snoozed bool, duration time.Duration) error {
+	store, err := getAckStore()
+	if err != nil {
+		return err
+	}
+
+	ack := Ack{Fingerprint: fingerprint, CreatedAt: time.Now(), Snoozed: snoozed}
+	if duration > 0 {
+		until := ack.CreatedAt.Add(duration)
+		ack.Until = &until
+	}
+
+	return store.PutAck(ack)
+}
+
+// parseSnoozeDuration parses the duration of a snooze, like "1h", "1d", or "changed",
+// which returns zero, as the alarm is snoozed until it changes
+func parseSnoozeDuration(s string) (time.Duration, error) {
+	if s == "changed" {
+		return 0, nil
+	}
+
+	var duration time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		duration = time.Duration(n) * 24 * time.Hour
+	} else {
+		duration, err = time.ParseDuration(s)
+	}
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("duration must be 'changed' or a positive duration, like '1h' or '1d'")
+	}
+
+	return duration, nil
+}
+
+// Acks returns the acknowledgments that didn't expire, by ack fingerprint
+func (h *History) Acks() (map[string]Ack, error) {
+	now := h.now()
+	acks := map[string]Ack{}
+	err := h.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(historyAcksBucket).ForEach(func(_, value []byte) error {
+			var ack Ack
+			if err := json.Unmarshal(value, &ack); err != nil {
+				return fmt.Errorf("error unmarshaling ack: %w", err)
+			}
+			if !ack.Expired(now) {
+				acks[ack.Fingerprint] = ack
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting acks: %w", err)
+	}
+
+	return acks, nil
+}
+
+// PutAck acknowledges or snoozes an alarm, replacing its current acknowledgment
+func (h *History) PutAck(ack Ack) error {
+	value, err := json.Marshal(ack)
+	if err != nil {
+		return fmt.Errorf("error marshaling ack: %w", err)
+	}
+	err = h.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(historyAcksBucket).Put([]byte(ack.Fingerprint), value)
+	})
+	if err != nil {
+		return fmt.Errorf("error saving ack: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteAck removes the acknowledgment of an alarm
+func (h *History) DeleteAck(fingerprint string) error {
+	err := h.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(historyAcksBucket).Delete([]byte(fingerprint))
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting ack: %w", err)
+	}
+
+	return nil
+}
+
+// pruneAcks removes the stale acknowledgments. It's called by Prune, in the same transaction.
+func pruneAcks(tx *bolt.Tx, now time.Time, retention time.Duration) error {
+	bucket := tx.Bucket(historyAcksBucket)
+	var keys [][]byte
+	err := bucket.ForEach(func(key, value []byte) error {
+		var ack Ack
+		if err := json.Unmarshal(value, &ack); err != nil {
+			return fmt.Errorf("error unmarshaling ack: %w", err)
+		}
+		if ack.stale(now, retention) {
+			keys = append(keys, append([]byte(nil), key...))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := bucket.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type memoryAckStore struct {
+	mu   sync.Mutex
+	acks map[string]Ack
+	now  func() time.Time
+}
+
+func newMemoryAckStore() *memoryAckStore {
+	return &memoryAckStore{acks: map[string]Ack{}, now: time.Now}
+}
+
+// Acks returns the acknowledgments that didn't expire, and removes the stale ones, like the history does
+// with the ALARMS_HISTORY_DAYS retention, so the ones lasting until the alarm changes aren't kept forever
+func (s *memoryAckStore) Acks() (map[string]Ack, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	retention := config.Current().IFrames.AlarmsHistoryRetention
+	acks := map[string]Ack{}
+	for fingerprint, ack := range s.acks {
+		if ack.stale(now, retention) {
+			delete(s.acks, fingerprint)
+			continue
+		}
+		acks[fingerprint] = ack
+	}
+
+	return acks, nil
+}
+
+func (s *memoryAckStore) PutAck(ack Ack) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.acks[ack.Fingerprint] = ack
+
+	return nil
+}
+
+func (s *memoryAckStore) DeleteAck(fingerprint string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.acks, fingerprint)
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package alarms
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+func TestAcks(t *testing.T) {
+	history, err := OpenHistory(filepath.Join(t.TempDir(), "alarms.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer history.Close()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	history.now = func() time.Time { return now }
+
+	until := now.Add(time.Hour)
+	for _, ack := range []Ack{
+		{Fingerprint: "acknowledged", CreatedAt: now},
+		{Fingerprint: "snoozed", CreatedAt: now, Snoozed: true, Until: &until},
+	} {
+		if err := history.PutAck(ack); err != nil {
+			t.Fatal(err)
+		}
+	}
+	acks, err := history.Acks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(acks) != 2 || !acks["snoozed"].Snoozed {
+		t.Fatalf("expected 2 acks, got %v", acks)
+	}
+
+	// The snooze expires, and the acknowledgment is older than the retention
+	now = now.Add(2 * time.Hour)
+	if acks, _ = history.Acks(); len(acks) != 1 {
+		t.Fatalf("expected the snooze to expire, got %v", acks)
+	}
+	if err := history.Prune(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if acks, _ = history.Acks(); len(acks) != 0 {
+		t.Fatalf("expected the old acks to be pruned, got %v", acks)
+	}
+
+	if err := history.PutAck(Ack{Fingerprint: "acknowledged", CreatedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	if err := history.DeleteAck("acknowledged"); err != nil {
+		t.Fatal(err)
+	}
+	if acks, _ = history.Acks(); len(acks) != 0 {
+		t.Fatalf("expected the ack to be deleted, got %v", acks)
+	}
+}
+
+func TestMemoryAcks(t *testing.T) {
+	defer config.Set(config.Current())
+	configs := &config.Configs{}
+	configs.IFrames.AlarmsHistoryRetention = 24 * time.Hour
+	config.Set(configs)
+	store := newMemoryAckStore()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	until := now.Add(time.Hour)
+	for _, ack := range []Ack{
+		{Fingerprint: "acknowledged", CreatedAt: now},
+		{Fingerprint: "snoozed", CreatedAt: now, Snoozed: true, Until: &until},
+	} {
+		if err := store.PutAck(ack); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if acks, _ := store.Acks(); len(acks) != 2 {
+		t.Fatalf("expected 2 acks, got %v", acks)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if acks, _ := store.Acks(); len(acks) != 1 || len(store.acks) != 1 {
+		t.Fatalf("expected the snooze to expire and be removed, got %v", store.acks)
+	}
+
+	// The acknowledgment until the alarm changes is removed after the retention, like in the history
+	now = now.Add(24 * time.Hour)
+	if acks, _ := store.Acks(); len(acks) != 0 || len(store.acks) != 0 {
+		t.Fatalf("expected the old acknowledgment to be removed, got %v", store.acks)
+	}
+}
+
+func TestApplyAcks(t *testing.T) {
+	defer config.Set(config.Current())
+	config.Set(&config.Configs{})
+	defer func() { memoryAcks = newMemoryAckStore() }()
+
+	diskAlarm := Alarm{Source: "Netdata", Summary: "Low disk space", Status: "WARNING", Property: "10GB free"}
+	cpuAlarm := Alarm{Source: "Netdata", Summary: "High CPU", Status: "CRITICAL"}
+	radarrAlarm := Alarm{Source: "Radarr", Summary: "Indexer unavailable", Status: "ERROR"}
+	alarms, err := trackAlarms([]Alarm{diskAlarm, cpuAlarm, radarrAlarm})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := acknowledge(AckFingerprint(diskAlarm), false, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := acknowledge(AckFingerprint(cpuAlarm), true, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := applyAcks(alarms, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 || !result[0].Acknowledged || result[1].Acknowledged {
+		t.Fatalf("expected the CPU alarm to be hidden and the disk alarm to be acknowledged, got %v", result)
+	}
+	if result, _ = applyAcks(alarms, true); len(result) != 1 || result[0].Summary != "Indexer unavailable" {
+		t.Fatalf("expected the acknowledged alarms to be hidden, got %v", result)
+	}
+	// The iFrame is reloaded when an alarm is acknowledged
+	if sources.GetHash(alarms[:1], "2025-01-01") == sources.GetHash(result[:1], "2025-01-01") {
+		t.Error("the acknowledgment should change the alarms hash")
+	}
+
+	// The acknowledgment ends when the alarm changes
+	diskAlarm.Property = "5GB free"
+	if alarms, err = trackAlarms([]Alarm{diskAlarm}); err != nil {
+		t.Fatal(err)
+	}
+	if result, _ = applyAcks(alarms, true); len(result) != 1 || result[0].Acknowledged {
+		t.Fatalf("expected the changed alarm not to be acknowledged, got %v", result)
+	}
+}
+
+func TestParseSnoozeDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"changed": 0,
+		"1h":      time.Hour,
+		"30m":     30 * time.Minute,
+		"1d":      24 * time.Hour,
+		"7d":      7 * 24 * time.Hour,
+	}
+	for s, expected := range tests {
+		actual, err := parseSnoozeDuration(s)
+		if err != nil || actual != expected {
+			t.Errorf("parseSnoozeDuration(%q): expected %s, got %s (%v)", s, expected, actual, err)
+		}
+	}
+	for _, s := range []string{"", "0h", "-1d", "forever"} {
+		if _, err := parseSnoozeDuration(s); err == nil {
+			t.Errorf("parseSnoozeDuration(%q): expected an error", s)
+		}
+	}
+}
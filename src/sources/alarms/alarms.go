@@ -18,59 +18,51 @@ import (
 )
 
 var (
-	a                  *Alarms
+	instances          = sources.NewInstances[*Alarms]("alarms")
 	backgroundImageURL = ""
+	// defaultResolvedSince is how long ago the alarms shown in the resolved mode can be resolved
+	defaultResolvedSince = 24 * time.Hour
 )
 
+func init() {
+	sources.Register(sources.Integration{
+		Name:   "alarms",
+		Title:  "Alarms",
+		IFrame: iFrameHandler,
+		Hash:   hashHandler,
+		Data:   dataHandler,
+		Actions: []sources.Action{
+			{Method: http.MethodPost, Path: "acknowledge", Item: "fingerprint", Handler: acknowledgeHandler},
+			{Method: http.MethodDelete, Path: "acknowledge", Item: "fingerprint", Handler: unacknowledgeHandler},
+			{Method: http.MethodPost, Path: "snooze", Item: "fingerprint", Handler: snoozeHandler},
+		},
+	})
+}
+
 type Alarms struct {
 	Regex *regexp.Regexp
 }
 
 func New() (*Alarms, error) {
-	if a != nil {
-		return a, nil
-	}
-
-	newN := &Alarms{}
-	err := newN.Init()
-	if err != nil {
-		return nil, err
-	}
-	a = newN
+	return instances.Get("", func(string) (*Alarms, error) {
+		newN := &Alarms{}
+		err := newN.Init()
+		if err != nil {
+			return nil, err
+		}
 
-	return a, nil
+		return newN, nil
+	})
 }
 
 func (a *Alarms) Init() error {
-	a.Regex = config.GlobalConfigs.IFrames.AlarmsRegex
+	a.Regex = config.Current().IFrames.AlarmsRegex
 
 	return nil
 }
 
 // GetiFrame returns an HTML/CSS code to be used as an iFrame
 func (a *Alarms) GetiFrame(c *gin.Context) {
-	var err error
-	alarmNamesStr := c.Query("alarms")
-	if alarmNamesStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "at the least one alarm must be provided in the alarmNames query parameter"})
-		return
-	}
-	alarmNames := []string{}
-	alarmNamesStrings := strings.Split(alarmNamesStr, ",")
-	for _, alarmName := range alarmNamesStrings {
-		var valid bool
-		for _, validAlarmName := range validAlarmNames {
-			if alarmName == validAlarmName {
-				alarmNames = append(alarmNames, alarmName)
-				valid = true
-			}
-		}
-		if !valid {
-			c.JSON(http.StatusBadRequest, gin.H{"message": "alarm '" + alarmName + "' is not valid. Valid alarms are: " + strings.Join(validAlarmNames, ", ")})
-			return
-		}
-	}
-
 	theme := c.Query("theme")
 	if theme == "" {
 		theme = "light"
@@ -79,53 +71,54 @@ func (a *Alarms) GetiFrame(c *gin.Context) {
 		return
 	}
 
-	sortDesc := c.Query("sort_desc")
-	var desc bool
-	if sortDesc != "" {
-		desc, err = strconv.ParseBool(sortDesc)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": "sort_desc must be a boolean"})
-			return
-		}
-	}
-
 	apiURL := c.Query("api_url")
 	if apiURL != "" {
-		_, err = url.ParseRequestURI(apiURL)
+		_, err := url.ParseRequestURI(apiURL)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"message": "api_url must be a valid URL like 'http://192.168.1.46:8080' or 'https://sub.domain.com'"})
 			return
 		}
 	}
 
-	regexInclude := true
-	regexIncludeStr := c.Query("regex_include")
-	if regexIncludeStr != "" {
-		regexInclude, err = strconv.ParseBool(regexIncludeStr)
+	groupBy, err := parseGroupBy(c.Query("group_by"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	layout := c.Query("layout")
+	if layout != "" && layout != "cards" && layout != "summary" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "layout must be 'cards' or 'summary'"})
+		return
+	}
+	link := c.Query("link")
+	if link != "" {
+		_, err := url.ParseRequestURI(link)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": "regex_include must be a boolean"})
+			c.JSON(http.StatusBadRequest, gin.H{"message": "link must be a valid URL"})
 			return
 		}
 	}
 
-	changedetectionioShowViewedStr := c.Query("changedetectionio_show_viewed")
-	changedetectionioShowViewed := true
-	if changedetectionioShowViewedStr != "" {
-		changedetectionioShowViewed, err = strconv.ParseBool(changedetectionioShowViewedStr)
+	alarms, ok := a.getQueryData(c)
+	if !ok {
+		return
+	}
+
+	if layout == "summary" {
+		if link == "" {
+			link = summaryLink(c.Request.URL.Query(), apiURL)
+		}
+		html, err := getSummaryiFrame(summarizeAlarms(alarms), theme, apiURL, link)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"message": "changedetectionio_show_viewed must be a boolean"})
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 			return
 		}
-	}
-
-	alarms, err := a.GetAlarms(alarmNames, desc, config.GlobalConfigs.IFrames.AlarmsRegex, regexInclude, changedetectionioShowViewed)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		c.Data(http.StatusOK, "text/html", html)
 		return
 	}
 
-	var html []byte
-	html, err = a.getAlarmsiFrame(alarms, desc, regexInclude, changedetectionioShowViewed, alarmNamesStr, theme, apiURL)
+	html, err := a.getAlarmsiFrame(groupAlarms(alarms, groupBy), sources.NewActionSession(c.Request), theme, apiURL)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -134,7 +127,7 @@ func (a *Alarms) GetiFrame(c *gin.Context) {
 	c.Data(http.StatusOK, "text/html", []byte(html))
 }
 
-func (a *Alarms) getAlarmsiFrame(alarms []Alarm, desc, regexInclude, changedetectionioShowViewed bool, alarmsQueryArg, theme, apiURL string) ([]byte, error) {
+func (a *Alarms) getAlarmsiFrame(groups []alarmGroup, actions sources.ActionSession, theme, apiURL string) ([]byte, error) {
 	html := `
 <!doctype html>
 <html lang="en">
@@ -291,56 +284,196 @@ func (a *Alarms) getAlarmsiFrame(alarms []Alarm, desc, regexInclude, changedetec
             border-radius: 1rem;
             margin: 0;
         }
+
+        .acknowledged {
+            opacity: 0.5;
+        }
+
+        .ack-buttons-container {
+            display: flex;
+            gap: 4px;
+            margin-top: 6px;
+        }
+
+        .ack-button {
+            color: white;
+            background-color: transparent;
+            padding: 0 0.4rem;
+            border-radius: 0.5rem;
+            border: 1px solid rgba(153, 182, 187, 0.6);
+            font-size: 0.6875rem;
+            font-weight: bold;
+            line-height: 1.125rem;
+        }
+
+        button.ack-button:hover {
+            filter: brightness(0.9);
+            background-color: rgba(153, 182, 187, 0.2);
+        }
+
+        .alarm-group-card {
+            list-style: none;
+            cursor: pointer;
+        }
+
+        .alarm-group-card::-webkit-details-marker {
+            display: none;
+        }
+
+        .group-chevron {
+            font-size: 0.75rem;
+            transition: transform 0.2s;
+        }
+
+        details[open] .group-chevron {
+            transform: rotate(180deg);
+        }
+
+        .alarm-group-alarms {
+            margin-left: 16px;
+        }
     </style>
 
+    {{ .LiveUpdates }}
+
     <script>
-        let lastHash = null;
-
-        async function fetchData() {
-            try {
-                var url = '{{ .APIURL }}/v1/hash/alarms?alarms={{ .AlarmsQueryArg }}&sort_desc={{ .SortDesc }}&regex_include={{ .RegexInclude }}&changedetectionio_show_viewed={{ .ChangeDetectionIOShowViewed }}';
-                const response = await fetch(url);
-                const data = await response.json();
-
-                if (lastHash === null) {
-                    lastHash = data.hash;
-                } else {
-                    if (data.hash !== lastHash) {
-                        lastHash = data.hash;
-                        location.reload();
-                    }
-                }
-            } catch (error) {
-                console.error('Error getting last update from the API:', error);
+      function acknowledgeAlarm(buttonId, method, path, fingerprint, duration, actionToken) {
+        try {
+            var xhr = new XMLHttpRequest();
+            var url = '{{ .APIURL }}/v1/iframe/alarms/' + path + '?fingerprint=' + encodeURIComponent(fingerprint);
+            if (duration) {
+                url += '&duration=' + encodeURIComponent(duration);
             }
+            xhr.open(method, url, true);
+            xhr.setRequestHeader('X-Action-Token', actionToken);
+
+            xhr.onload = function () {
+              if (xhr.status >= 200 && xhr.status < 300) {
+                console.log('Request to ', path, ' alarm ', fingerprint, ' finished with success:', xhr.responseText);
+                location.reload();
+              } else {
+                console.log('Request to ', path, ' alarm ', fingerprint, ' failed:', xhr.responseText);
+                handleAcknowledgeError(buttonId);
+              }
+            };
+
+            xhr.onerror = function () {
+              console.log('Request to ', path, ' alarm ', fingerprint, ' failed:', xhr.responseText);
+              handleAcknowledgeError(buttonId);
+            };
+
+            xhr.send(null);
+        } catch (error) {
+            console.log('Request to ', path, ' alarm ', fingerprint, ' failed:', error);
+            handleAcknowledgeError(buttonId);
+        }
+      }
+
+      function handleAcknowledgeError(buttonId) {
+        var button = document.getElementById(buttonId);
+        button.style.backgroundColor = 'red';
+        button.style.borderColor = 'red';
+      }
+
+      // Keeps the open alarm groups open when the iFrame reloads or updates
+      (function () {
+        var storageKey = 'alarms-open-groups';
+        function getOpenGroups() {
+          try {
+            return JSON.parse(sessionStorage.getItem(storageKey)) || [];
+          } catch (error) {
+            return [];
+          }
         }
 
-        function fetchAndUpdate() {
-            fetchData();
-            setTimeout(fetchAndUpdate, 10000); // 10 seconds
+        function restoreOpenGroups() {
+          getOpenGroups().forEach(function (id) {
+            var group = document.getElementById(id);
+            if (group && !group.open) {
+              group.open = true;
+            }
+          });
         }
 
-        {{ if .APIURL }}
-            fetchAndUpdate();
-        {{ end }}
+        document.addEventListener('toggle', function (event) {
+          var group = event.target;
+          if (!group.classList || !group.classList.contains('alarm-group')) {
+            return;
+          }
+          var openGroups = getOpenGroups().filter(function (id) { return id !== group.id; });
+          if (group.open) {
+            openGroups.push(group.id);
+          }
+          try {
+            sessionStorage.setItem(storageKey, JSON.stringify(openGroups));
+          } catch (error) {
+            console.log('Error saving the open alarm groups:', error);
+          }
+        }, true);
+
+        document.addEventListener('DOMContentLoaded', function () {
+          restoreOpenGroups();
+          new MutationObserver(restoreOpenGroups).observe(document.body, { childList: true, subtree: true });
+        });
+      })();
     </script>
 
 </head>
 <body>
-{{ range .Alarms }}
-    <div class="alarms-container">
+{{ range .Groups }}
+    {{ if eq (len .Alarms) 1 }}
+        {{ template "alarm" (alarmCard (index .Alarms 0)) }}
+    {{ else }}{{ $first := index .Alarms 0 }}{{ $highest := .Highest }}
+        <details class="alarm-group" id="group-{{ .ID }}">
+            <summary class="alarms-container alarm-group-card{{ if .Acknowledged }} acknowledged{{ end }}">
+                <div class="background-image" style="{{ if $first.BackgroundImgURL }}background-image: url('{{ $first.BackgroundImgURL }}');{{ else }}background-color: {{ $first.BackgroundColor }};{{ end }} background-size: {{ $first.BackgroundImgSize }}%;"></div>
+
+                <div class="text-wrap">
+                    <i class="fa-solid fa-layer-group"></i> <span class="alarm-summary" title="{{ $first.Summary }}">{{ $first.Summary }}</span>
+                    <div class="more-info-container">
+                        <span class="info-label" title="{{ join .Sources ", " }}"><i class="fa-solid fa-cube"></i> {{ join .Sources ", " }}</span>
+                    </div>
+                </div>
+
+                <div class="alarm-info-container">
+                    <p class="alarm-value-label">{{ len .Alarms }} alarms <i class="fa-solid fa-chevron-down group-chevron"></i></p>
+                    <div>
+                        <p class="alarm-status-label" style="color: white; background-color: {{ getStatusColor $highest.Status }};">{{ $highest.Status }}</p>
+                    </div>
+                </div>
+            </summary>
+            <div class="alarm-group-alarms">
+                {{ range .Alarms }}{{ template "alarm" (alarmCard .) }}{{ end }}
+            </div>
+        </details>
+    {{ end }}
+{{ end }}
+</body>
+</html>
+
+{{ define "alarm" }}
+    <div class="alarms-container{{ if or .Acknowledged (eq .MaintenanceAction "dim") }} acknowledged{{ end }}">
         <div class="background-image" style="{{ if .BackgroundImgURL }}background-image: url('{{ .BackgroundImgURL }}');{{ else }}background-color: {{ .BackgroundColor }};{{ end }} background-size: {{ .BackgroundImgSize }}%;"></div>
 
         <div class="text-wrap">
             <i class="fa-solid fa-bell"></i> <a href="{{ .URL }}" target="_blank" class="alarm-summary" title="{{ .Summary }}">{{ .Summary }}</a>
             <div class="more-info-container">
-                <span class="info-label"><i class="fa-solid fa-cube"></i> {{ .Source }}</span>
+                <span class="info-label"><i class="fa-solid fa-cube"></i> {{ .Source }}{{ if .Instance }} ({{ .Instance }}){{ end }}</span>
                 {{ if not .Time.IsZero }}
                     <span class="info-label"><i class="fa-solid fa-calendar-days"></i> {{ .Time.Format "2006-01-02 15h04" }}</span> 
                 {{ end }}
+                {{ if .ResolvedAt }}
+                    <span class="info-label"><i class="fa-solid fa-check"></i> {{ .ResolvedAt.Format "2006-01-02 15h04" }}</span>
+                {{ end }}
+                {{ if not .FirstSeen.IsZero }}
+                    <span class="info-label" title="First seen at {{ .FirstSeen.Format "2006-01-02 15h04" }}"><i class="fa-solid fa-hourglass-half"></i> {{ formatDuration .Duration }}</span>
+                {{ end }}
                 {{ if .Property }}
                     <span class="info-label" title="{{ .Property }}"><i class="fa-solid fa-gear"></i> {{ .Property }}</span>
                 {{ end }}
+                {{ if .Maintenance }}
+                    <span class="info-label" title="In the {{ .Maintenance }} maintenance window"><i class="fa-solid fa-screwdriver-wrench"></i> In maintenance</span>
+                {{ end }}
             </div>
         </div>
     
@@ -349,11 +482,22 @@ func (a *Alarms) getAlarmsiFrame(alarms []Alarm, desc, regexInclude, changedetec
             <div>
                 <p class="alarm-status-label" style="color: white; background-color: {{ getStatusColor .Status }};">{{ .Status }}</p>
             </div>
+            {{ if and .APIURL .AckFingerprint }}{{ $ackToken := getActionToken "acknowledge" .AckFingerprint }}{{ $snoozeToken := getActionToken "snooze" .AckFingerprint }}
+                <div class="ack-buttons-container">
+                    {{ if .Acknowledged }}
+                        <button id="unack-{{ .AckFingerprint }}" onclick="acknowledgeAlarm(this.id, 'DELETE', 'acknowledge', '{{ .AckFingerprint }}', '', '{{ $ackToken }}')" class="ack-button" title="Remove the acknowledgment" onmouseenter="this.style.cursor='pointer';"><i class="fa-solid fa-rotate-left"></i></button>
+                    {{ else }}
+                        <button id="ack-{{ .AckFingerprint }}" onclick="acknowledgeAlarm(this.id, 'POST', 'acknowledge', '{{ .AckFingerprint }}', '', '{{ $ackToken }}')" class="ack-button" title="Acknowledge until it changes" onmouseenter="this.style.cursor='pointer';"><i class="fa-solid fa-check"></i></button>
+                    {{ end }}
+                    <button id="snooze-1h-{{ .AckFingerprint }}" onclick="acknowledgeAlarm(this.id, 'POST', 'snooze', '{{ .AckFingerprint }}', '1h', '{{ $snoozeToken }}')" class="ack-button" title="Snooze for 1 hour" onmouseenter="this.style.cursor='pointer';">1h</button>
+                    <button id="snooze-1d-{{ .AckFingerprint }}" onclick="acknowledgeAlarm(this.id, 'POST', 'snooze', '{{ .AckFingerprint }}', '1d', '{{ $snoozeToken }}')" class="ack-button" title="Snooze for 1 day" onmouseenter="this.style.cursor='pointer';">1d</button>
+                    <button id="snooze-changed-{{ .AckFingerprint }}" onclick="acknowledgeAlarm(this.id, 'POST', 'snooze', '{{ .AckFingerprint }}', 'changed', '{{ $snoozeToken }}')" class="ack-button" title="Snooze until it changes" onmouseenter="this.style.cursor='pointer';"><i class="fa-solid fa-bell-slash"></i></button>
+                </div>
+            {{ end }}
         </div>
     </div>
 {{ end }}
-</body>
-</html>
+
 	`
 	// Homarr theme
 	scrollbarThumbBackgroundColor := "#d1dbe3"
@@ -364,30 +508,24 @@ func (a *Alarms) getAlarmsiFrame(alarms []Alarm, desc, regexInclude, changedetec
 	}
 
 	templateData := iframeTemplateData{
-		Alarms:                        alarms,
-		AlarmsQueryArg:                alarmsQueryArg,
+		Groups:                        groups,
 		Theme:                         theme,
 		APIURL:                        apiURL,
-		RegexInclude:                  regexInclude,
-		SortDesc:                      desc,
-		ChangeDetectionIOShowViewed:   changedetectionioShowViewed,
+		LiveUpdates:                   sources.LiveUpdatesScript(apiURL, "alarms"),
 		ScrollbarThumbBackgroundColor: scrollbarThumbBackgroundColor,
 		ScrollbarTrackBackgroundColor: scrollbarTrackBackgroundColor,
 	}
 
 	templateFuncs := template.FuncMap{
-		"getStatusColor": func(status string) string {
-			switch status {
-			case "CLEAR":
-				return "green"
-			case "WARNING", "CHANGED":
-				return "orange"
-			case "ERROR", "CRITICAL", "FAILED":
-				return "red"
-			default:
-				return "gray"
-			}
+		"formatDuration": formatDuration,
+		"join":           strings.Join,
+		"alarmCard": func(alarm trackedAlarm) alarmCard {
+			return alarmCard{trackedAlarm: alarm, APIURL: apiURL}
 		},
+		"getActionToken": func(path, fingerprint string) string {
+			return actions.Token("alarms", path, fingerprint)
+		},
+		"getStatusColor": getStatusColor,
 	}
 
 	tmpl := template.Must(template.New("alarms").Funcs(templateFuncs).Parse(html))
@@ -404,46 +542,46 @@ func (a *Alarms) getAlarmsiFrame(alarms []Alarm, desc, regexInclude, changedetec
 type iframeTemplateData struct {
 	Theme                         string
 	APIURL                        string
-	RegexInclude                  bool
+	LiveUpdates                   template.HTML
 	ScrollbarThumbBackgroundColor string
 	ScrollbarTrackBackgroundColor string
-	Alarms                        []Alarm
-	AlarmsQueryArg                string
-	SortDesc                      bool
-	ChangeDetectionIOShowViewed   bool
+	Groups                        []alarmGroup
+}
+
+// alarmCard is the data of the alarm card template
+type alarmCard struct {
+	trackedAlarm
+	APIURL string
 }
 
 // GetHash returns the hash of the alarms
 func (a *Alarms) GetHash(c *gin.Context) {
-	alarmNamesStr := c.Query("alarms")
-	if alarmNamesStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "at the least one alarm must be provided in the alarmNames query parameter"})
+	alarms, ok := a.getQueryData(c)
+	if !ok {
 		return
 	}
-	alarmNames := []string{}
-	alarmNamesStrings := strings.Split(alarmNamesStr, ",")
-	for _, alarmName := range alarmNamesStrings {
-		var valid bool
-		for _, validAlarmName := range validAlarmNames {
-			if alarmName == validAlarmName {
-				alarmNames = append(alarmNames, alarmName)
-				valid = true
-			}
-		}
-		if !valid {
-			c.JSON(http.StatusBadRequest, gin.H{"message": "alarm '" + alarmName + "' is not valid. Valid alarms are: " + strings.Join(validAlarmNames, ", ")})
-			return
-		}
+
+	hash := sources.GetHash(alarms, time.Now().Format("2006-01-02"))
+
+	c.JSON(http.StatusOK, gin.H{"hash": fmt.Sprintf("%x", hash)})
+}
+
+// getQueryData parses the query parameters used by the iFrame, hash, and data routes and returns the alarms.
+// If it fails, it writes the error response and returns false.
+func (a *Alarms) getQueryData(c *gin.Context) ([]trackedAlarm, bool) {
+	alarmNames, err := ParseAlarmNames(c.Query("alarms"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return nil, false
 	}
 
-	var err error
 	sortDesc := c.Query("sort_desc")
 	var desc bool
 	if sortDesc != "" {
 		desc, err = strconv.ParseBool(sortDesc)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"message": "sort_desc must be a boolean"})
-			return
+			return nil, false
 		}
 	}
 
@@ -453,27 +591,89 @@ func (a *Alarms) GetHash(c *gin.Context) {
 		regexInclude, err = strconv.ParseBool(regexIncludeStr)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"message": "regex_include must be a boolean"})
-			return
+			return nil, false
 		}
 	}
 
 	changedetectionioShowViewedStr := c.Query("changedetectionio_show_viewed")
-	changedetectionioShowViewed := true
 	if changedetectionioShowViewedStr != "" {
-		changedetectionioShowViewed, err = strconv.ParseBool(changedetectionioShowViewedStr)
+		_, err = strconv.ParseBool(changedetectionioShowViewedStr)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"message": "changedetectionio_show_viewed must be a boolean"})
-			return
+			return nil, false
 		}
 	}
 
-	alarms, err := a.GetAlarms(alarmNames, desc, config.GlobalConfigs.IFrames.AlarmsRegex, regexInclude, changedetectionioShowViewed)
+	var hideAcknowledged bool
+	switch c.Query("acknowledged") {
+	case "", "dim":
+	case "hide":
+		hideAcknowledged = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"message": "acknowledged must be 'dim' or 'hide'"})
+		return nil, false
+	}
+
+	severity, err := parseSeverityFilter(c.Query("min_severity"), c.Query("severity"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return nil, false
+	}
+	filters, err := parseFilters(c.Query("filter"), c.Query("filter_preset"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return nil, false
+	}
+
+	var alarms []trackedAlarm
+	switch mode := c.Query("mode"); mode {
+	case "", "active":
+		var activeAlarms []Alarm
+		activeAlarms, err = a.getCachedAlarms(c.Request.URL.Query(), alarmNames, desc, regexInclude)
+		if err == nil {
+			alarms, err = trackAlarms(activeAlarms)
+		}
+		if err == nil {
+			alarms, err = applyAcks(alarms, hideAcknowledged)
+		}
+	case "resolved":
+		if config.Current().IFrames.AlarmsHistoryFile == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "the resolved mode requires the alarms history, set ALARMS_HISTORY_FILE to enable it"})
+			return nil, false
+		}
+		since := defaultResolvedSince
+		if sinceStr := c.Query("since"); sinceStr != "" {
+			since, err = time.ParseDuration(sinceStr)
+			if err != nil || since <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"message": "since must be a positive duration, like '24h'"})
+				return nil, false
+			}
+		}
+		alarms, err = getResolvedAlarms(alarmNames, time.Now().Add(-since), config.Current().IFrames.AlarmsRegex, regexInclude)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"message": "mode must be 'active' or 'resolved'"})
+		return nil, false
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-		return
+		return nil, false
 	}
 
-	hash := sources.GetHash(alarms, time.Now().Format("2006-01-02"))
+	filteredAlarms := make([]trackedAlarm, 0, len(alarms))
+	for _, alarm := range alarms {
+		if severity.matches(alarm.Alarm) && matchesFilters(alarm.Alarm, filters) {
+			filteredAlarms = append(filteredAlarms, alarm)
+		}
+	}
 
-	c.JSON(http.StatusOK, gin.H{"hash": fmt.Sprintf("%x", hash)})
+	return filteredAlarms, true
+}
+
+// getCachedAlarms returns the alarms used by the iFrame and hash routes.
+// The alarms are cached by the query parameters that change them.
+func (a *Alarms) getCachedAlarms(query url.Values, alarmNames []string, desc, regexInclude bool) ([]Alarm, error) {
+	key := sources.CacheKey("alarms", query, "alarms", "sort_desc", "regex_include", "changedetectionio_show_viewed")
+	return sources.Cached(key, func() ([]Alarm, error) {
+		return a.GetAlarms(alarmNames, desc, config.Current().IFrames.AlarmsRegex, regexInclude, query, config.Current().IFrames.AlarmsSourceTimeout)
+	})
 }
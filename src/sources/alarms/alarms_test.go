@@ -1,6 +1,17 @@
 package alarms
 
-import "testing"
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/radarr"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/sonarr"
+)
 
 func TestNew(t *testing.T) {
 	alarms, err := New()
@@ -12,3 +23,113 @@ func TestNew(t *testing.T) {
 		t.Fatal("Expected alarms to be not nil")
 	}
 }
+
+func TestSeverityFilter(t *testing.T) {
+	alarms := []Alarm{{Status: "CLEAR"}, {Status: "NOTICE"}, {Status: "WARNING"}, {Status: "ERROR"}, {Status: "CRITICAL"}}
+	tests := []struct {
+		minSeverity, severities string
+		expected                []string
+	}{
+		{"", "", []string{"CLEAR", "NOTICE", "WARNING", "ERROR", "CRITICAL"}},
+		{"error", "", []string{"ERROR", "CRITICAL"}},
+		{"", "info,critical", []string{"NOTICE", "CRITICAL"}},
+		{"warning", "info,critical", []string{"CRITICAL"}},
+	}
+	for _, test := range tests {
+		filter, err := parseSeverityFilter(test.minSeverity, test.severities)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var statuses []string
+		for _, alarm := range alarms {
+			if filter.matches(alarm) {
+				statuses = append(statuses, alarm.Status)
+			}
+		}
+		if !slices.Equal(statuses, test.expected) {
+			t.Errorf("min_severity=%q severity=%q: expected %v, got %v", test.minSeverity, test.severities, test.expected, statuses)
+		}
+	}
+
+	if _, err := parseSeverityFilter("high", ""); err == nil {
+		t.Error("expected an error parsing an invalid min_severity")
+	}
+	if _, err := parseSeverityFilter("", "warning,high"); err == nil {
+		t.Error("expected an error parsing an invalid severity")
+	}
+}
+
+func TestFilters(t *testing.T) {
+	defer config.Set(config.Current())
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("ALARMS_FILTER", `NOT source=Pi-hole`)
+	t.Setenv("ALARMS_FILTER_PRESET_OPS", `severity=error OR severity=critical`)
+	t.Setenv("SONARR_ALARMS_FILTER", `NOT summary~"(?i)indexers are unavailable"`)
+	if err := config.SetConfigs(""); err != nil {
+		t.Fatal(err)
+	}
+
+	alarms := []Alarm{
+		{Source: "Netdata", Summary: "Disk space usage", Status: "WARNING"},
+		{Source: "Netdata", Summary: "Disk write errors", Status: "CRITICAL"},
+		{Source: "Pi-hole", Summary: "Blocking disabled", Status: "ERROR"},
+		{Source: "Sonarr", Summary: "Indexers are unavailable due to failures", Status: "WARNING"},
+	}
+	tests := []struct {
+		expression, presets string
+		expected            []string
+	}{
+		{"", "", []string{"Disk space usage", "Disk write errors", "Indexers are unavailable due to failures"}},
+		{"", "ops", []string{"Disk write errors"}},
+		{`summary~"Disk" AND source=netdata`, "", []string{"Disk space usage", "Disk write errors"}},
+		{`summary~"Disk"`, "OPS", []string{"Disk write errors"}},
+	}
+	for _, test := range tests {
+		filters, err := parseFilters(test.expression, test.presets)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var summaries []string
+		for _, alarm := range alarms {
+			if matchesFilters(alarm, filters) {
+				summaries = append(summaries, alarm.Summary)
+			}
+		}
+		if !slices.Equal(summaries, test.expected) {
+			t.Errorf("filter=%q filter_preset=%q: expected %v, got %v", test.expression, test.presets, test.expected, summaries)
+		}
+	}
+	for _, params := range [][2]string{{"source=", ""}, {"", "home"}} {
+		if _, err := parseFilters(params[0], params[1]); err == nil {
+			t.Errorf("filter=%q filter_preset=%q: expected an error", params[0], params[1])
+		}
+	}
+
+	if filtered := filterInstanceAlarms("sonarr", "", alarms[3:]); len(filtered) != 0 {
+		t.Errorf("expected the Sonarr alarm to be filtered by SONARR_ALARMS_FILTER, got %v", filtered)
+	}
+	if filtered := filterInstanceAlarms("radarr", "", alarms[3:]); len(filtered) != 1 {
+		t.Errorf("expected the alarm to not be filtered without RADARR_ALARMS_FILTER, got %v", filtered)
+	}
+}
+
+func TestFilterKeepsErrorAlarm(t *testing.T) {
+	defer config.Set(config.Current())
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("SONARR_ALARMS_FILTER", `severity=critical AND NOT status=ERROR`)
+	if err := config.SetConfigs(""); err != nil {
+		t.Fatal(err)
+	}
+
+	var err error
+	integration := &sources.Integration{Name: "sonarr", Title: "Sonarr", Alarms: func(string, url.Values) ([]Alarm, error) {
+		return []Alarm{{Source: "Sonarr", Summary: "Indexers are unavailable", Status: "WARNING"}}, err
+	}}
+	if alarms := getIntegrationAlarms(integration, "", url.Values{}, time.Second, false); len(alarms) != 0 {
+		t.Errorf("expected the Sonarr alarm to be filtered by SONARR_ALARMS_FILTER, got %v", alarms)
+	}
+	err = errors.New("connection refused")
+	if alarms := getIntegrationAlarms(integration, "", url.Values{}, time.Second, false); len(alarms) != 1 || alarms[0].Summary != "Sonarr unreachable" {
+		t.Errorf("expected the unreachable alarm not to be filtered, got %v", alarms)
+	}
+}
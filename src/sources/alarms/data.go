@@ -0,0 +1,97 @@
+package alarms
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// Data is the response of the /v1/data/alarms route
+type Data struct {
+	Version int         `json:"version"`
+	Alarms  []AlarmData `json:"alarms"`
+}
+
+// AlarmData is an alarm in the /v1/data/alarms route
+type AlarmData struct {
+	// Time is omitted if the alarm doesn't have a time, like the alarm of an unreachable source
+	Time *time.Time `json:"time,omitempty"`
+	// Source is like "Netdata" or "Radarr"
+	Source string `json:"source"`
+	// Instance is the source instance name, like "4k". Omitted for the default instance.
+	Instance string `json:"instance,omitempty"`
+	// Status is like "CLEAR", "WARNING", "ERROR", or "CRITICAL"
+	Status string `json:"status"`
+	// Severity is the normalized status: "ok", "info", "warning", "error", or "critical"
+	Severity sources.Severity `json:"severity" swaggertype:"string" enums:"ok,info,warning,error,critical"`
+	Summary  string           `json:"summary"`
+	Value    string           `json:"value,omitempty"`
+	Property string           `json:"property,omitempty"`
+	URL      string           `json:"url,omitempty"`
+	// FirstSeen is when the alarm was first seen. Omitted if the alarms history is disabled.
+	FirstSeen *time.Time `json:"first_seen,omitempty"`
+	// ResolvedAt is when the alarm was resolved, only in the resolved mode
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	// DurationSeconds is how long the alarm is or was active. Omitted if the alarms history is disabled.
+	DurationSeconds int64 `json:"duration_seconds,omitempty"`
+	// Fingerprint is used to acknowledge and snooze the alarm. Omitted in the resolved mode.
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Acknowledged is true if the alarm is acknowledged
+	Acknowledged bool `json:"acknowledged"`
+	// Group is the ID of the group of the alarm, with the group_by query parameter. Omitted if the alarm isn't grouped.
+	Group string `json:"group,omitempty"`
+	// Maintenance is the name of the maintenance window the alarm is in. Omitted if it's not in one.
+	Maintenance string `json:"maintenance,omitempty"`
+}
+
+// GetData returns the alarms as JSON
+func (a *Alarms) GetData(c *gin.Context) {
+	groupBy, err := parseGroupBy(c.Query("group_by"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	alarms, ok := a.getQueryData(c)
+	if !ok {
+		return
+	}
+
+	groups := map[string]string{}
+	for _, group := range groupAlarms(alarms, groupBy) {
+		if len(group.Alarms) < 2 {
+			continue
+		}
+		for _, alarm := range group.Alarms {
+			groups[Fingerprint(alarm.Alarm)] = group.ID
+		}
+	}
+
+	response := Data{Version: sources.DataVersion, Alarms: []AlarmData{}}
+	for _, alarm := range alarms {
+		response.Alarms = append(response.Alarms, AlarmData{
+			Time:     sources.OptionalTime(alarm.Time),
+			Source:   alarm.Source,
+			Instance: alarm.Instance,
+			Status:   alarm.Status,
+			Severity: alarm.NormalizedSeverity(),
+			Summary:  alarm.Summary,
+			Value:    alarm.Value,
+			Property: alarm.Property,
+			URL:      alarm.URL,
+
+			FirstSeen:       sources.OptionalTime(alarm.FirstSeen),
+			ResolvedAt:      alarm.ResolvedAt,
+			DurationSeconds: int64(alarm.Duration().Seconds()),
+			Fingerprint:     alarm.AckFingerprint,
+			Acknowledged:    alarm.Acknowledged,
+			Group:           groups[Fingerprint(alarm.Alarm)],
+			Maintenance:     alarm.Maintenance,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,344 @@
+package alarms
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// feedTitle is the title of the alarm feeds
+const feedTitle = "Homarr iFrames Alarms"
+
+// feedItem is an alarm in the feeds, with the fields used by the three formats
+type feedItem struct {
+	// GUID is stable while the alarm is active, so the feed readers don't show it again
+	GUID    string
+	Title   string
+	Link    string
+	Content string
+	// Time is when the alarm happened or was resolved, or zero if it's unknown
+	Time       time.Time
+	Categories []string
+}
+
+// getFeedItems returns the feed items of the alarms
+func getFeedItems(alarms []trackedAlarm) []feedItem {
+	items := make([]feedItem, len(alarms))
+	for i, alarm := range alarms {
+		item := feedItem{
+			GUID:       feedGUID(alarm),
+			Title:      fmt.Sprintf("[%s] %s", alarm.Status, alarm.Summary),
+			Link:       alarm.URL,
+			Time:       alarm.Time,
+			Categories: []string{alarm.Source, alarm.NormalizedSeverity().String()},
+		}
+		if item.Time.IsZero() {
+			item.Time = alarm.FirstSeen
+		}
+		if alarm.ResolvedAt != nil {
+			item.Title = "[RESOLVED] " + alarm.Summary
+			item.Time = *alarm.ResolvedAt
+		}
+
+		content := []string{"Source: " + alarm.Source}
+		if alarm.Instance != "" {
+			content[0] += " (" + alarm.Instance + ")"
+		}
+		content = append(content, "Status: "+alarm.Status)
+		if alarm.Property != "" {
+			content = append(content, "Property: "+alarm.Property)
+		}
+		if alarm.Value != "" {
+			content = append(content, "Value: "+alarm.Value)
+		}
+		if !alarm.FirstSeen.IsZero() {
+			content = append(content, "First seen: "+alarm.FirstSeen.Format(time.RFC1123Z))
+		}
+		item.Content = strings.Join(content, "\n")
+		items[i] = item
+	}
+
+	return items
+}
+
+// feedGUID returns the GUID of an alarm, derived from its fingerprint. If the history is enabled,
+// it has the time the alarm was first seen, so an alarm that fires again is a new item.
+// The resolved alarms have another GUID, as they are another event.
+func feedGUID(alarm trackedAlarm) string {
+	guid := "urn:homarr-iframes:alarm:" + Fingerprint(alarm.Alarm)
+	if !alarm.FirstSeen.IsZero() {
+		guid += ":" + fmt.Sprint(alarm.FirstSeen.Unix())
+	}
+	if alarm.ResolvedAt != nil {
+		guid += ":resolved"
+	}
+
+	return guid
+}
+
+// feedID returns the ID of a feed, derived from the query parameters, so each feed URL has its own ID
+func feedID(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	hash := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(hash, "%s=%s\x00", key, strings.Join(query[key], ","))
+	}
+
+	return "urn:homarr-iframes:alarms:" + hex.EncodeToString(hash.Sum(nil)[:8])
+}
+
+// feedUpdated returns the time of the newest item, or now if no item has a time
+func feedUpdated(items []feedItem, now time.Time) time.Time {
+	var updated time.Time
+	for _, item := range items {
+		if item.Time.After(updated) {
+			updated = item.Time
+		}
+	}
+	if updated.IsZero() {
+		return now
+	}
+
+	return updated
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	Description string   `xml:"description"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// renderRSS returns an RSS 2.0 feed
+func renderRSS(items []feedItem, link string, now time.Time) ([]byte, error) {
+	feed := rssFeed{Version: "2.0", Channel: rssChannel{
+		Title:         feedTitle,
+		Link:          link,
+		Description:   "Alarms from the sources configured in Homarr iFrames",
+		LastBuildDate: feedUpdated(items, now).Format(time.RFC1123Z),
+		Items:         []rssItem{},
+	}}
+	for _, item := range items {
+		rss := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Content,
+			GUID:        rssGUID{Value: item.GUID},
+			Categories:  item.Categories,
+		}
+		if !item.Time.IsZero() {
+			rss.PubDate = item.Time.Format(time.RFC1123Z)
+		}
+		feed.Channel.Items = append(feed.Channel.Items, rss)
+	}
+
+	return marshalXML(feed)
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Links      []atomLink     `xml:"link"`
+	Content    atomContent    `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// renderAtom returns an Atom feed. The entries without time use the feed time, as Atom requires it.
+func renderAtom(items []feedItem, id, link string, now time.Time) ([]byte, error) {
+	updated := feedUpdated(items, now)
+	feed := atomFeed{
+		ID:      id,
+		Title:   feedTitle,
+		Updated: updated.Format(time.RFC3339),
+		Links:   []atomLink{{Href: link, Rel: "self"}},
+		Author:  atomAuthor{Name: "Homarr iFrames"},
+	}
+	for _, item := range items {
+		entryUpdated := item.Time
+		if entryUpdated.IsZero() {
+			entryUpdated = updated
+		}
+		entry := atomEntry{
+			ID:      item.GUID,
+			Title:   item.Title,
+			Updated: entryUpdated.Format(time.RFC3339),
+			Content: atomContent{Type: "text", Value: item.Content},
+		}
+		if item.Link != "" {
+			entry.Links = []atomLink{{Href: item.Link, Rel: "alternate"}}
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return marshalXML(feed)
+}
+
+func marshalXML(feed any) ([]byte, error) {
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling feed: %w", err)
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
+
+// JSONFeed is a JSON Feed 1.1, see https://www.jsonfeed.org/version/1.1/
+type JSONFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description"`
+	Items       []JSONFeedItem `json:"items"`
+}
+
+// JSONFeedItem is an alarm in the JSON Feed
+type JSONFeedItem struct {
+	// ID is stable while the alarm is active
+	ID            string     `json:"id"`
+	URL           string     `json:"url,omitempty"`
+	Title         string     `json:"title"`
+	ContentText   string     `json:"content_text"`
+	DatePublished *time.Time `json:"date_published,omitempty"`
+	// Tags are the alarm source and severity
+	Tags []string `json:"tags"`
+}
+
+// renderJSONFeed returns a JSON Feed 1.1
+func renderJSONFeed(items []feedItem, link string) ([]byte, error) {
+	feed := JSONFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feedTitle,
+		FeedURL:     link,
+		Description: "Alarms from the sources configured in Homarr iFrames",
+		Items:       []JSONFeedItem{},
+	}
+	for _, item := range items {
+		jsonItem := JSONFeedItem{
+			ID:          item.GUID,
+			URL:         item.Link,
+			Title:       item.Title,
+			ContentText: item.Content,
+			Tags:        item.Categories,
+		}
+		if !item.Time.IsZero() {
+			jsonItem.DatePublished = &item.Time
+		}
+		feed.Items = append(feed.Items, jsonItem)
+	}
+
+	body, err := json.Marshal(feed)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling feed: %w", err)
+	}
+
+	return body, nil
+}
+
+// feedURL returns the URL of the feed request, used as the feed link. It uses the
+// X-Forwarded-Proto header set by the reverse proxies to know if the request is HTTPS.
+func feedURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	return scheme + "://" + r.Host + r.URL.RequestURI()
+}
+
+// getFeed writes a feed of the alarms in a format: "rss", "atom", or "json"
+func (a *Alarms) getFeed(c *gin.Context, format string) {
+	alarms, ok := a.getQueryData(c)
+	if !ok {
+		return
+	}
+
+	items := getFeedItems(alarms)
+	link := feedURL(c.Request)
+	now := time.Now()
+	var body []byte
+	var contentType string
+	var err error
+	switch format {
+	case "rss":
+		body, err = renderRSS(items, link, now)
+		contentType = "application/rss+xml; charset=utf-8"
+	case "atom":
+		body, err = renderAtom(items, feedID(c.Request.URL.Query()), link, now)
+		contentType = "application/atom+xml; charset=utf-8"
+	default:
+		body, err = renderJSONFeed(items, link)
+		contentType = "application/feed+json; charset=utf-8"
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, body)
+}
@@ -0,0 +1,84 @@
+package alarms
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeeds(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	firstSeen := now.Add(-time.Hour)
+	resolvedAt := now.Add(-time.Minute)
+	alarms := []trackedAlarm{
+		{Alarm: Alarm{Source: "Netdata", Summary: "Disk space usage", Status: "WARNING", URL: "https://netdata.local/alerts"}, FirstSeen: firstSeen},
+		{Alarm: Alarm{Source: "Sonarr", Instance: "4k", Summary: "Missing root folder", Status: "ERROR", Property: "/data/tv"}},
+		{Alarm: Alarm{Source: "Netdata", Summary: "Disk space usage", Status: "WARNING"}, FirstSeen: firstSeen, ResolvedAt: &resolvedAt},
+	}
+	items := getFeedItems(alarms)
+
+	// The GUID doesn't change with the value, and is different when the alarm fires again or is resolved
+	changed := alarms[0]
+	changed.Value = "95%"
+	if feedGUID(changed) != items[0].GUID {
+		t.Errorf("the GUID changed with the alarm value")
+	}
+	fired := alarms[0]
+	fired.FirstSeen = now
+	if feedGUID(fired) == items[0].GUID || items[2].GUID == items[0].GUID {
+		t.Errorf("expected different GUIDs, got %s", items[0].GUID)
+	}
+	if items[0].Link != "https://netdata.local/alerts" || items[0].Title != "[WARNING] Disk space usage" || !items[0].Time.Equal(firstSeen) {
+		t.Errorf("unexpected item: %+v", items[0])
+	}
+	if items[1].Content != "Source: Sonarr (4k)\nStatus: ERROR\nProperty: /data/tv" || !items[1].Time.IsZero() {
+		t.Errorf("unexpected item: %+v", items[1])
+	}
+	if items[2].Title != "[RESOLVED] Disk space usage" || !items[2].Time.Equal(resolvedAt) {
+		t.Errorf("unexpected item: %+v", items[2])
+	}
+
+	body, err := renderRSS(items, "http://localhost/v1/feed/alarms.rss", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rss rssFeed
+	if err := xml.Unmarshal(body, &rss); err != nil {
+		t.Fatal(err)
+	}
+	if len(rss.Channel.Items) != 3 || rss.Channel.Items[0].GUID.Value != items[0].GUID || rss.Channel.Items[1].PubDate != "" {
+		t.Errorf("unexpected RSS feed: %s", body)
+	}
+	if rss.Channel.LastBuildDate != resolvedAt.Format(time.RFC1123Z) {
+		t.Errorf("expected the last build date %s, got %s", resolvedAt.Format(time.RFC1123Z), rss.Channel.LastBuildDate)
+	}
+
+	body, err = renderAtom(items, feedID(map[string][]string{"alarms": {"netdata"}}), "http://localhost/v1/feed/alarms.atom", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var atom atomFeed
+	if err := xml.Unmarshal(body, &atom); err != nil {
+		t.Fatal(err)
+	}
+	if len(atom.Entries) != 3 || atom.Entries[1].Updated != resolvedAt.Format(time.RFC3339) || atom.Entries[1].Links != nil {
+		t.Errorf("unexpected Atom feed: %s", body)
+	}
+	if !strings.HasPrefix(atom.ID, "urn:homarr-iframes:alarms:") || atom.ID == feedID(map[string][]string{"alarms": {"sonarr"}}) {
+		t.Errorf("unexpected Atom feed ID: %s", atom.ID)
+	}
+
+	body, err = renderJSONFeed(items, "http://localhost/v1/feed/alarms.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var feed JSONFeed
+	if err := json.Unmarshal(body, &feed); err != nil {
+		t.Fatal(err)
+	}
+	if len(feed.Items) != 3 || feed.Items[0].ID != items[0].GUID || feed.Items[1].DatePublished != nil || feed.Items[0].Tags[1] != "warning" {
+		t.Errorf("unexpected JSON feed: %s", body)
+	}
+}
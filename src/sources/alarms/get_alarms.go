@@ -2,119 +2,60 @@ package alarms
 
 import (
 	"fmt"
+	"log/slog"
+	"net/url"
 	"regexp"
+	"slices"
 	"sort"
-	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
-	"github.com/diogovalentte/homarr-iframes/src/sources/backrest"
-	"github.com/diogovalentte/homarr-iframes/src/sources/changedetectionio"
-	"github.com/diogovalentte/homarr-iframes/src/sources/kaizoku"
-	"github.com/diogovalentte/homarr-iframes/src/sources/kavita"
-	"github.com/diogovalentte/homarr-iframes/src/sources/lidarr"
-	"github.com/diogovalentte/homarr-iframes/src/sources/netdata"
-	"github.com/diogovalentte/homarr-iframes/src/sources/openarchiver"
-	"github.com/diogovalentte/homarr-iframes/src/sources/pihole"
-	"github.com/diogovalentte/homarr-iframes/src/sources/prowlarr"
-	"github.com/diogovalentte/homarr-iframes/src/sources/radarr"
-	"github.com/diogovalentte/homarr-iframes/src/sources/sonarr"
-	speedtesttracker "github.com/diogovalentte/homarr-iframes/src/sources/speedtest-tracker"
+	"github.com/diogovalentte/homarr-iframes/src/filter"
+	"github.com/diogovalentte/homarr-iframes/src/metrics"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
-var validAlarmNames = []string{"netdata", "prowlarr", "sonarr", "radarr", "lidarr", "speedtest-tracker", "pihole", "kavita", "kaizoku", "changedetectionio", "backrest", "openarchiver"}
-
-func (a *Alarms) GetAlarms(alarmNames []string, desc bool, regex *regexp.Regexp, regexInclude, changedetectionioShowViewed bool) ([]Alarm, error) {
-	var alarms []Alarm
+// ParseAlarmNames parses and validates the alarms query parameter, like "netdata,radarr,radarr:4k".
+// An alarm name can have the instance name after a colon, without it, the default instance is used.
+func ParseAlarmNames(alarmNamesStr string) ([]string, error) {
+	if alarmNamesStr == "" {
+		return nil, fmt.Errorf("at the least one alarm must be provided in the alarmNames query parameter")
+	}
 
-	for _, alarmName := range alarmNames {
-		switch alarmName {
-		case "netdata":
-			netdataAlarms, err := getNetdataAlarms()
-			if err != nil {
-				return nil, fmt.Errorf("failed to get Netdata alarms: %w", err)
-			}
-			alarms = append(alarms, netdataAlarms...)
-		case "prowlarr":
-			prowlarrAlarms, err := getProwlarrAlarms()
-			if err != nil {
-				return nil, fmt.Errorf("failed to get Prowlarr alarms: %w", err)
-			}
-			alarms = append(alarms, prowlarrAlarms...)
-		case "radarr":
-			radarrAlarms, err := getRadarrAlarms()
-			if err != nil {
-				return nil, fmt.Errorf("failed to get Radarr alarms: %w", err)
-			}
-			alarms = append(alarms, radarrAlarms...)
-		case "lidarr":
-			lidarrAlarms, err := getLidarrAlarms()
-			if err != nil {
-				return nil, fmt.Errorf("failed to get Lidarr alarms: %w", err)
-			}
-			alarms = append(alarms, lidarrAlarms...)
-		case "sonarr":
-			sonarrAlarms, err := getSonarrAlarms()
-			if err != nil {
-				return nil, fmt.Errorf("failed to get Sonarr alarms: %w", err)
-			}
-			alarms = append(alarms, sonarrAlarms...)
-		case "speedtest-tracker":
-			speedTestTrackerAlarms, err := getSpeedTestTrackerAlarms()
-			if err != nil {
-				return nil, fmt.Errorf("failed to get SpeedTest Tracker alarms: %w", err)
-			}
-			alarms = append(alarms, speedTestTrackerAlarms...)
-		case "pihole":
-			piholeAlarms, err := getPiholeAlarms()
-			if err != nil {
-				return nil, fmt.Errorf("failed to get Pi-hole alarms: %w", err)
-			}
-			alarms = append(alarms, piholeAlarms...)
-		case "kavita":
-			kavitaAlarms, err := getKavitaAlarms()
-			if err != nil {
-				return nil, fmt.Errorf("failed to get Kavita alarms: %w", err)
-			}
-			alarms = append(alarms, kavitaAlarms...)
-		case "kaizoku":
-			kaizokuAlarms, err := getKaizokuAlarms()
-			if err != nil {
-				return nil, fmt.Errorf("failed to get Kaizoku alarms: %w", err)
-			}
-			alarms = append(alarms, kaizokuAlarms...)
-		case "changedetectionio":
-			changedetectionioAlarms, err := getChangedetectionioAlarms(changedetectionioShowViewed)
-			if err != nil {
-				return nil, fmt.Errorf("failed to get ChangeDetection.io alarms: %w", err)
-			}
-			alarms = append(alarms, changedetectionioAlarms...)
-		case "backrest":
-			backrestAlarms, err := getBackrestAlarms()
-			if err != nil {
-				return nil, fmt.Errorf("failed to get Backrest alarms: %w", err)
-			}
-			alarms = append(alarms, backrestAlarms...)
-		case "openarchiver":
-			openArchiverAlarms, err := getOpenArchiverAlarms()
-			if err != nil {
-				return nil, fmt.Errorf("failed to get OpenArchiver alarms: %w", err)
-			}
-			alarms = append(alarms, openArchiverAlarms...)
-		default:
-			return nil, fmt.Errorf("invalid alarm name: %s", alarmName)
+	validAlarmNames := sources.AlarmProviders()
+	alarmNames := []string{}
+	for _, alarmName := range strings.Split(alarmNamesStr, ",") {
+		name, instance, _ := strings.Cut(alarmName, ":")
+		if !slices.Contains(validAlarmNames, name) {
+			return nil, fmt.Errorf("alarm '%s' is not valid. Valid alarms are: %s", name, strings.Join(validAlarmNames, ", "))
 		}
+		if !slices.Contains(config.Current().Instances(name), instance) {
+			return nil, fmt.Errorf("instance '%s' of alarm '%s' is not configured", instance, name)
+		}
+		alarmNames = append(alarmNames, alarmName)
+	}
+
+	return alarmNames, nil
+}
+
+// GetAlarms returns the alarms of the registered integrations in alarmNames, like "radarr" or "radarr:4k".
+// The integrations are requested concurrently, and an integration that fails or
+// takes longer than timeout returns an ERROR alarm instead of its alarms.
+// The alarms in a maintenance window are hidden, or have the window set to be dimmed or marked.
+// params are passed to the integrations, like the changedetectionio_show_viewed query parameter.
+// The alarms aren't recorded in the history nor notified, only the poller does it, as params change the alarms.
+func (a *Alarms) GetAlarms(alarmNames []string, desc bool, regex *regexp.Regexp, regexInclude bool, params url.Values, timeout time.Duration) ([]Alarm, error) {
+	alarms, err := getAlarms(alarmNames, params, timeout, false)
+	if err != nil {
+		return nil, err
 	}
 
 	if regex != nil {
 		var filteredAlarms []Alarm
 		for _, alarm := range alarms {
-			alarmString := fmt.Sprintf("%s%s%s%s%s%s", alarm.Source, alarm.Summary, alarm.URL, alarm.Status, alarm.Property, alarm.Value)
-			match := regex.MatchString(alarmString)
-			if regexInclude && match {
-				filteredAlarms = append(filteredAlarms, alarm)
-			} else if !regexInclude && !match {
+			if matchesRegex(alarm, regex) == regexInclude {
 				filteredAlarms = append(filteredAlarms, alarm)
 			}
 		}
@@ -126,551 +67,235 @@ func (a *Alarms) GetAlarms(alarmNames []string, desc bool, regex *regexp.Regexp,
 	return alarms, nil
 }
 
-func getNetdataAlarms() ([]Alarm, error) {
-	n, err := netdata.New()
-	if err != nil {
-		return nil, err
-	}
-
-	netdataAlarms, err := n.GetAlarms(-1)
-	if err != nil {
-		return nil, err
-	}
-
-	var alarms []Alarm
-	for _, alarm := range netdataAlarms {
-		summary := alarm.Summary
-		if summary == "" {
-			summary = alarm.Name
-		}
-		if summary == "" {
-			summary = "Unknown"
+// getAlarms returns the alarms of the integrations in alarmNames, requested concurrently, with the maintenance windows applied.
+// If record is true, the alarms of each integration instance are recorded in the history, notified, and set in the metrics.
+func getAlarms(alarmNames []string, params url.Values, timeout time.Duration, record bool) ([]Alarm, error) {
+	integrations := make([]*sources.Integration, len(alarmNames))
+	for i, alarmName := range alarmNames {
+		name, _, _ := strings.Cut(alarmName, ":")
+		integration, ok := sources.Get(name)
+		if !ok || integration.Alarms == nil {
+			return nil, fmt.Errorf("invalid alarm name: %s", alarmName)
 		}
-		alarms = append(alarms, Alarm{
-			Source:            "Netdata",
-			BackgroundImgURL:  netdata.BackgroundImageURL,
-			BackgroundImgSize: 80,
-			Summary:           summary,
-			URL:               n.Address,
-			Status:            alarm.Status,
-			Value:             alarm.ValueString,
-			Property:          alarm.Component + " / " + alarm.Type,
-			Time:              alarm.LastStatusChange,
-		})
-	}
-
-	return alarms, nil
-}
-
-func getRadarrAlarms() ([]Alarm, error) {
-	r, err := radarr.New()
-	if err != nil {
-		return nil, err
+		integrations[i] = integration
 	}
 
-	radarrAlarms, err := r.GetHealth()
-	if err != nil {
-		return nil, err
+	results := make([][]Alarm, len(alarmNames))
+	var wg sync.WaitGroup
+	for i, alarmName := range alarmNames {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, instance, _ := strings.Cut(alarmName, ":")
+			alarms := getIntegrationAlarms(integrations[i], instance, params, timeout, record)
+			results[i] = applyMaintenance(integrations[i].Name, instance, alarms, time.Now())
+		}()
 	}
+	wg.Wait()
 
 	var alarms []Alarm
-	for _, alarm := range radarrAlarms {
-		url := fmt.Sprintf("%s/system/status", r.Address)
-		if alarm.WikiURL != "" {
-			url = alarm.WikiURL
-		}
-		alarms = append(alarms, Alarm{
-			Source:            "Radarr",
-			BackgroundImgURL:  radarr.BackgroundImageURL,
-			BackgroundImgSize: 120,
-			Summary:           alarm.Message,
-			URL:               url,
-			Status:            strings.ToUpper(alarm.Type),
-			Property:          alarm.Source,
-		})
+	for _, result := range results {
+		alarms = append(alarms, result...)
 	}
 
 	return alarms, nil
 }
 
-func getLidarrAlarms() ([]Alarm, error) {
-	l, err := lidarr.New()
-	if err != nil {
-		return nil, err
-	}
-
-	lidarrAlarms, err := l.GetHealth()
-	if err != nil {
-		return nil, err
-	}
-
-	var alarms []Alarm
-	for _, alarm := range lidarrAlarms {
-		url := fmt.Sprintf("%s/system/status", l.Address)
-		if alarm.WikiURL != "" {
-			url = alarm.WikiURL
-		}
-		alarms = append(alarms, Alarm{
-			Source:            "Lidarr",
-			BackgroundImgURL:  lidarr.BackgroundImageURL,
-			BackgroundImgSize: 120,
-			Summary:           alarm.Message,
-			URL:               url,
-			Status:            strings.ToUpper(alarm.Type),
-			Property:          alarm.Source,
-		})
-	}
-
-	return alarms, nil
+// matchesRegex returns true if the regex matches the alarm fields concatenated
+func matchesRegex(alarm Alarm, regex *regexp.Regexp) bool {
+	return regex.MatchString(fmt.Sprintf("%s%s%s%s%s%s", alarm.Source, alarm.Summary, alarm.URL, alarm.Status, alarm.Property, alarm.Value))
 }
 
-func getSonarrAlarms() ([]Alarm, error) {
-	s, err := sonarr.New()
-	if err != nil {
-		return nil, err
-	}
-
-	sonarrAlarms, err := s.GetHealth()
-	if err != nil {
-		return nil, err
-	}
-
-	var alarms []Alarm
-	for _, alarm := range sonarrAlarms {
-		url := fmt.Sprintf("%s/system/status", s.Address)
-		if alarm.WikiURL != "" {
-			url = alarm.WikiURL
-		}
-		alarms = append(alarms, Alarm{
-			Source:            "Sonarr",
-			BackgroundImgURL:  sonarr.BackgroundImageURL,
-			BackgroundImgSize: 120,
-			Summary:           alarm.Message,
-			URL:               url,
-			Status:            strings.ToUpper(alarm.Type),
-			Property:          alarm.Source,
-		})
-	}
-
-	return alarms, nil
+// severityFilter filters the alarms by severity, from the min_severity and severity query parameters
+type severityFilter struct {
+	// min is the minimum severity, or SeverityUnknown to not filter by it
+	min sources.Severity
+	// severities are the only severities shown, or empty to show all
+	severities []sources.Severity
 }
 
-func getProwlarrAlarms() ([]Alarm, error) {
-	p, err := prowlarr.New()
-	if err != nil {
-		return nil, err
-	}
-
-	prowlarrAlarms, err := p.GetHealth()
-	if err != nil {
-		return nil, err
+// parseSeverityFilter parses the min_severity and the comma separated severity query parameters, like "error" and "warning,critical"
+func parseSeverityFilter(minSeverity, severities string) (severityFilter, error) {
+	var filter severityFilter
+	var err error
+	if minSeverity != "" {
+		if filter.min, err = sources.ParseSeverity(minSeverity); err != nil {
+			return filter, fmt.Errorf("min_severity: %w", err)
+		}
 	}
-
-	var alarms []Alarm
-	for _, alarm := range prowlarrAlarms {
-		url := fmt.Sprintf("%s/system/status", p.Address)
-		if alarm.WikiURL != "" {
-			url = alarm.WikiURL
+	if severities != "" {
+		for _, name := range strings.Split(severities, ",") {
+			severity, err := sources.ParseSeverity(strings.TrimSpace(name))
+			if err != nil {
+				return filter, fmt.Errorf("severity: %w", err)
+			}
+			filter.severities = append(filter.severities, severity)
 		}
-		alarms = append(alarms, Alarm{
-			Source:            "Prowlarr",
-			BackgroundImgURL:  prowlarr.BackgroundImageURL,
-			BackgroundImgSize: 100,
-			Summary:           alarm.Message,
-			URL:               url,
-			Status:            strings.ToUpper(alarm.Type),
-			Property:          alarm.Source,
-		})
 	}
 
-	return alarms, nil
+	return filter, nil
 }
 
-func getSpeedTestTrackerAlarms() ([]Alarm, error) {
-	s, err := speedtesttracker.New()
-	if err != nil {
-		return nil, err
+// matches returns true if the alarm severity passes the filter
+func (f severityFilter) matches(alarm Alarm) bool {
+	severity := alarm.NormalizedSeverity()
+	if severity < f.min {
+		return false
 	}
 
-	test, err := s.GetLatestTest()
-	if err != nil {
-		return nil, err
-	}
+	return len(f.severities) == 0 || slices.Contains(f.severities, severity)
+}
 
-	// test failed
-	if test.Status == "failed" {
-		layout := "2006-01-02 15:04:05"
-		updatedAt, err := time.Parse(layout, test.UpdatedAt)
-		if err != nil {
-			return nil, err
+// parseFilters returns the filter expressions of the alarms iFrame: the ALARMS_FILTER variable, the comma
+// separated presets in the filter_preset query parameter, and the expression in the filter query parameter
+func parseFilters(expression, presets string) ([]*filter.Expr, error) {
+	iframesConfigs := config.Current().IFrames
+	var filters []*filter.Expr
+	if iframesConfigs.AlarmsFilter != nil {
+		filters = append(filters, iframesConfigs.AlarmsFilter)
+	}
+	if presets != "" {
+		for _, name := range strings.Split(presets, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			preset, ok := iframesConfigs.AlarmsFilterPresets[name]
+			if !ok {
+				return nil, fmt.Errorf("filter preset '%s' is not configured, set ALARMS_FILTER_PRESET_%s to configure it", name, strings.ToUpper(name))
+			}
+			filters = append(filters, preset)
 		}
-		updatedAt = updatedAt.In(time.Local)
-
-		url := s.Address + "/admin/results"
-
-		alarms := []Alarm{{
-			Time:            updatedAt,
-			Summary:         "Last Speedtest Failed",
-			URL:             url,
-			Status:          strings.ToUpper(test.Data.Level),
-			Value:           test.Service,
-			Property:        test.Data.Message,
-			Source:          "SpeedTest",
-			BackgroundColor: "black",
-		}}
-
-		return alarms, nil
-	} else if test.Status == "running" {
-		return []Alarm{}, nil
 	}
-
-	// threshold breached
-	alarms := []Alarm{}
-	if !test.Healthy && test.Status == "completed" {
-		layout := "2006-01-02 15:04:05"
-		updatedAt, err := time.Parse(layout, test.UpdatedAt)
+	if expression != "" {
+		expr, err := filter.Parse(expression)
 		if err != nil {
 			return nil, err
 		}
-		updatedAt = updatedAt.In(time.Local)
-
-		url := s.Address + "/admin/results"
-
-		if !test.Benchmarks.Download.Passed {
-			alarms = append(alarms, Alarm{
-				Time:            updatedAt,
-				Summary:         "Speedtest Threshold Breached",
-				URL:             url,
-				Status:          "DOWNLOAD",
-				Value:           test.DownloadBitsHuman,
-				Property:        test.Data.ISP,
-				Source:          "SpeedTest",
-				BackgroundColor: "black",
-			})
-		}
-		if !test.Benchmarks.Upload.Passed {
-			alarms = append(alarms, Alarm{
-				Time:            updatedAt,
-				Summary:         "Speedtest Threshold Breached",
-				URL:             url,
-				Status:          "UPLOAD",
-				Value:           test.UploadBitsHuman,
-				Property:        test.Data.ISP,
-				Source:          "SpeedTest",
-				BackgroundColor: "black",
-			})
-		}
-		if !test.Benchmarks.Ping.Passed {
-			alarms = append(alarms, Alarm{
-				Time:            updatedAt,
-				Summary:         "Speedtest Threshold Breached",
-				URL:             url,
-				Status:          "PING",
-				Value:           fmt.Sprintf("%.2f ms", test.Ping),
-				Property:        test.Data.ISP,
-				Source:          "SpeedTest",
-				BackgroundColor: "black",
-			})
-		}
+		filters = append(filters, expr)
 	}
 
-	return alarms, nil
+	return filters, nil
 }
 
-func getPiholeAlarms() ([]Alarm, error) {
-	p, err := pihole.New()
-	if err != nil {
-		return nil, err
-	}
-
-	messages, err := p.GetMessages()
-	if err != nil {
-		return nil, err
-	}
-
-	var alarms []Alarm
-	for _, message := range messages.Messages {
-		timestamp := time.Unix(message.Timestamp, 0)
-		var url string
-		if p.Token != "" {
-			url = p.Address + "/admin/messages.php"
-		} else {
-			url = p.Address + "/admin/messages"
+// matchesFilters returns true if the alarm matches every filter
+func matchesFilters(alarm Alarm, filters []*filter.Expr) bool {
+	for _, expr := range filters {
+		if !expr.Match(alarmField(alarm)) {
+			return false
 		}
-		alarms = append(alarms, Alarm{
-			Source:            "Pi-hole",
-			BackgroundImgURL:  pihole.BackgroundImgURL,
-			BackgroundImgSize: 80,
-			Summary:           message.Plain,
-			Property:          message.Type,
-			Time:              timestamp,
-			URL:               url,
-			Status:            "WARNING",
-		})
 	}
 
-	return alarms, nil
+	return true
 }
 
-func getKavitaAlarms() ([]Alarm, error) {
-	k, err := kavita.New()
-	if err != nil {
-		return nil, err
-	}
-
-	errors, err := k.GetMediaErrors()
-	if err != nil {
-		return nil, err
-	}
-
-	var alarms []Alarm
-	for _, error := range errors {
-		layout := "2006-01-02T15:04:05.9999999"
-		timestamp, err := time.Parse(layout, error.CreatedUTC)
-		if err != nil {
-			return nil, err
+// alarmField returns a function that returns the alarm fields used by the filter expressions
+func alarmField(alarm Alarm) func(string) string {
+	return func(field string) string {
+		switch field {
+		case "source":
+			return alarm.Source
+		case "instance":
+			return alarm.Instance
+		case "summary":
+			return alarm.Summary
+		case "status":
+			return alarm.Status
+		case "severity":
+			return alarm.NormalizedSeverity().String()
+		case "property":
+			return alarm.Property
+		case "value":
+			return alarm.Value
+		case "url":
+			return alarm.URL
+		default:
+			return ""
 		}
-		timestamp = timestamp.Local()
-		url := k.Address + "/settings#admin-media-issues"
-		alarms = append(alarms, Alarm{
-			Source:            "Kavita",
-			BackgroundImgURL:  kavita.BackgroundImgURL,
-			BackgroundImgSize: 100,
-			Summary:           error.Comment,
-			Time:              timestamp,
-			URL:               url,
-			Status:            "ERROR",
-		})
 	}
-
-	return alarms, nil
 }
 
-func getKaizokuAlarms() ([]Alarm, error) {
-	k, err := kaizoku.New()
-	if err != nil {
-		return nil, err
+// filterInstanceAlarms removes the alarms that don't match the ALARMS_FILTER variable of the integration instance,
+// like SONARR_ALARMS_FILTER. They are removed before being recorded, so they are not notified nor kept in the history.
+func filterInstanceAlarms(name, instance string, alarms []Alarm) []Alarm {
+	text := config.Current().Instance(name, instance).Get(sources.AlarmsFilterKey)
+	if text == "" {
+		return alarms
 	}
-
-	queues, err := k.GetQueues()
+	expr, err := filter.Parse(text)
 	if err != nil {
-		return nil, err
+		// The filter is validated when the configs are loaded
+		slog.Error("error parsing alarms filter", "source", name, "instance", instance, "error", err)
+		return alarms
 	}
 
-	var alarms []Alarm
-	for _, queue := range queues {
-		if queue.Counts.Failed < 1 {
-			continue
+	var filteredAlarms []Alarm
+	for _, alarm := range alarms {
+		alarm.Instance = instance
+		if expr.Match(alarmField(alarm)) {
+			filteredAlarms = append(filteredAlarms, alarm)
 		}
-
-		summary := fmt.Sprintf("Queue %s has failed jobs", queue.Name)
-		url := k.Address + "/bull/queues/queue/" + queue.Name + "?status=failed"
-
-		alarms = append(alarms, Alarm{
-			Source:          "Kaizoku",
-			BackgroundColor: "black",
-			Summary:         summary,
-			URL:             url,
-			Status:          "FAILED",
-			Value:           fmt.Sprintf("%d jobs", queue.Counts.Failed),
-			Property:        queue.Name,
-		})
 	}
 
-	return alarms, nil
+	return filteredAlarms
 }
 
-func getChangedetectionioAlarms(showViewed bool) ([]Alarm, error) {
-	c, err := changedetectionio.New()
-	if err != nil {
-		return nil, err
-	}
-
-	watches, err := c.GetWatches()
-	if err != nil {
-		return nil, err
+// getIntegrationAlarms returns the alarms of an integration instance. If record is true, they are recorded
+// and set in the metrics.
+// If the integration returns an error or doesn't answer within timeout, it returns an ERROR alarm.
+func getIntegrationAlarms(integration *sources.Integration, instance string, params url.Values, timeout time.Duration, record bool) []Alarm {
+	type result struct {
+		alarms []Alarm
+		err    error
 	}
+	resultChan := make(chan result, 1)
+	go func() {
+		alarms, err := integration.Alarms(instance, params)
+		resultChan <- result{alarms, err}
+	}()
 
 	var alarms []Alarm
-	for ID, watch := range watches {
-		var hasError bool
-		if value, ok := watch.LastError.(bool); !ok {
-			errStr := watch.LastError.(string)
-			if errStr != "" {
-				hasError = true
-			}
-		} else if value {
-			hasError = true
-		}
-
-		if hasError {
-			errStr := watch.LastError.(string)
-			lastChecked := time.Unix(int64(watch.LastChecked), 0)
-			summary := watch.Title
-			if watch.Title == "" {
-				summary = watch.URL
-			}
-			alarms = append(alarms, Alarm{
-				Source:            "ChangeDetection.io",
-				BackgroundImgURL:  changedetectionio.BackgroundImgURL,
-				BackgroundImgSize: 100,
-				Summary:           summary,
-				URL:               c.Address,
-				Status:            "ERROR",
-				Property:          errStr,
-				Time:              lastChecked,
-			})
-
-			continue
-		}
-
-		if watch.Viewed && !showViewed {
-			continue
-		}
-
-		minChanged := time.Now().Add(-time.Hour * time.Duration(config.GlobalConfigs.ChangeDetectionIO.ChangedLastHours))
-		lastChanged := time.Unix(int64(watch.LastChanged), 0)
-		if lastChanged.After(minChanged) {
-			summary := watch.Title
-			if summary == "" {
-				summary = watch.URL
-			}
-			viewed := "Viewed"
-			if !watch.Viewed {
-				viewed = "Not Viewed"
-			}
-
-			alarms = append(alarms, Alarm{
-				Source:            "ChangeDetection.io",
-				BackgroundImgURL:  changedetectionio.BackgroundImgURL,
-				BackgroundImgSize: 100,
-				Summary:           summary,
-				URL:               c.Address + "/diff/" + ID,
-				Status:            "CHANGED",
-				Value:             viewed,
-				Time:              lastChanged,
-			})
-		}
+	var err error
+	select {
+	case r := <-resultChan:
+		alarms, err = r.alarms, r.err
+	case <-time.After(timeout):
+		err = fmt.Errorf("timed out after %s", timeout)
 	}
-
-	return alarms, nil
-}
-
-func getBackrestAlarms() ([]Alarm, error) {
-	b, err := backrest.New()
 	if err != nil {
-		return nil, err
-	}
-
-	summary, err := b.GetSummaryDashboard()
-	if err != nil {
-		return nil, err
-	}
-
-	var alarms []Alarm
-	for _, planSummary := range summary.PlanSummaries {
-		if planSummary.ID == "" {
-			planSummary.ID = "Unknown"
-		}
-
-		if planSummary.BackupsFailed30days == "0" {
-			continue
-		}
-
-		for i := range planSummary.RecentBackups.FlowID {
-			status := "Unknown"
-			switch planSummary.RecentBackups.Status[i] {
-			case "STATUS_SUCCESS":
-				continue
-			case "STATUS_WARNING":
-				status = "WARNING"
-			case "STATUS_ERROR":
-				status = "ERROR"
-			case "STATUS_INPROGRESS":
-				continue
-			}
-
-			t, err := strconv.ParseInt(planSummary.RecentBackups.TimeStampMs[i], 10, 64)
-			if err != nil {
-				return nil, err
-			}
-			timestamp := time.UnixMilli(t)
-			timestamp = timestamp.In(time.Local)
-			last24Hours := time.Now().Add(-24 * time.Hour)
-			if timestamp.Before(last24Hours) {
-				continue
-			}
-
-			durationMilliseconds, err := strconv.ParseFloat(planSummary.RecentBackups.DurationMs[i], 64)
-			if err != nil {
-				return nil, err
-			}
-			durationFormated := "00:00:00 min"
-			if durationMilliseconds != 0 {
-				duration := time.Duration(durationMilliseconds) * time.Millisecond
-				hours := int(duration.Hours())
-				minutes := int(duration.Minutes())
-				seconds := int(duration.Seconds()) % 60
-				durationFormated = fmt.Sprintf("%02d:%02d:%02d min", hours, minutes, seconds)
-			}
-
-			alarms = append(alarms, Alarm{
-				Source:          "Backrest",
-				BackgroundColor: "black",
-				Summary:         fmt.Sprintf("Plan %s was not successful", planSummary.ID),
-				URL:             b.Address + "/#/plan/" + planSummary.ID,
-				Status:          status,
-				Property:        durationFormated,
-				Time:            timestamp,
-			})
+		slog.Warn("error getting alarms", "source", integration.Name, "instance", instance, "error", err)
+		// The error alarm isn't filtered, as it's the only sign the integration is down
+		alarms = []Alarm{integrationErrorAlarm(integration, instance, err)}
+	} else {
+		alarms = filterInstanceAlarms(integration.Name, instance, alarms)
+	}
+	statuses := make([]string, len(alarms))
+	for i := range alarms {
+		alarms[i].Instance = instance
+		alarms[i].Severity = alarms[i].NormalizedSeverity()
+		statuses[i] = alarms[i].Status
+	}
+	if record {
+		// The metrics are only set by the poller, as the alarms of the requests depend on their parameters.
+		// The alarms of a failed integration are unknown, so the last ones are kept.
+		metrics.SetAlarmsSourceUp(integration.Name, instance, err == nil)
+		if err == nil {
+			metrics.SetAlarms(integration.Name, instance, statuses)
 		}
+		recordAlarms(integration.Name, instance, alarms, err != nil)
 	}
 
-	return alarms, nil
+	return alarms
 }
 
-func getOpenArchiverAlarms() ([]Alarm, error) {
-	o, err := openarchiver.New()
-	if err != nil {
-		return nil, err
-	}
-
-	ingestionSources, err := o.GetIngestionSources(-1)
-	if err != nil {
-		return nil, err
+// integrationErrorAlarm returns the alarm shown when an integration can't be reached.
+// Its time is not set, so the alarms hash doesn't change every time the integration is requested.
+func integrationErrorAlarm(integration *sources.Integration, instance string, err error) Alarm {
+	return Alarm{
+		Source:          integration.Title,
+		Summary:         integration.Title + " unreachable",
+		URL:             config.Current().Instance(integration.Name, instance).Get("ADDRESS"),
+		Status:          "ERROR",
+		Property:        config.Redact(err.Error()),
+		BackgroundColor: "black",
 	}
-
-	alarms := []Alarm{}
-	for _, source := range ingestionSources {
-		if source.Status == "error" {
-			message := source.LastSyncStatusMessage
-			slices := strings.Split(message, "\n")
-			if len(slices) > 1 {
-				message = slices[1]
-			}
-			slices = strings.Split(message, ": ")
-			if len(slices) > 1 {
-				message = slices[1]
-			}
-
-			lastSyncFinishedAt, err := time.Parse(time.RFC3339, source.LastSyncFinishedAt)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse LastSyncFinishedAt for source %s: %w", source.Name, err)
-			}
-
-			alarms = append(alarms, Alarm{
-				Source:          "OpenArchiver",
-				Summary:         message,
-				URL:             o.Address + "/dashboard/ingestions",
-				Status:          "ERROR",
-				Property:        source.Name,
-				Time:            lastSyncFinishedAt,
-				BackgroundColor: "black",
-			})
-		}
-	}
-
-	return alarms, nil
 }
 
 func sortAlarms(alarms []Alarm, desc bool) {
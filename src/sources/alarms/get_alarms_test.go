@@ -1,11 +1,14 @@
-package alarms
+package alarms_test
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"testing"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources/alarms"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/all"
 )
 
 func setup() error {
@@ -29,9 +32,9 @@ func TestMain(m *testing.M) {
 }
 
 func TestGetAlarms(t *testing.T) {
-	alarms := Alarms{}
+	a := alarms.Alarms{}
 	t.Run("get alarms", func(t *testing.T) {
-		_, err := alarms.GetAlarms([]string{"netdata", "prowlarr", "radarr", "lidarr", "sonarr", "speedtest-tracker", "kavita", "pihole", "changedetectionio", "kaizoku", "backrest", "openarchiver"}, false, config.GlobalConfigs.IFrames.AlarmsRegex, true, true)
+		_, err := a.GetAlarms([]string{"netdata", "prowlarr", "radarr", "lidarr", "sonarr", "speedtest-tracker", "kavita", "pihole", "changedetectionio", "kaizoku", "backrest", "openarchiver"}, false, config.Current().IFrames.AlarmsRegex, true, url.Values{}, config.Current().IFrames.AlarmsSourceTimeout)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -0,0 +1,162 @@
+package alarms
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+// summarySimilarity is the minimum similarity of two summaries to be grouped, from 0 to 1.
+// With it, "Missing root folder: /data/movies" and "Missing root folder: /data/tv" are grouped.
+const summarySimilarity = 0.6
+
+// groupKeys are the group_by query parameter keys
+var groupKeys = []string{"summary", "property", "source", "severity"}
+
+// alarmGroup is a group of alarms shown in one collapsible card, or a single alarm
+type alarmGroup struct {
+	// ID identifies the group, so the iFrame keeps it open when it updates
+	ID     string
+	Alarms []trackedAlarm
+	// words are the summary words of the first alarm, compared with the summary of the other alarms
+	words []string
+	// key has the values of the other group_by keys of the first alarm
+	key string
+}
+
+// Sources returns the unique sources of the group alarms, like "Sonarr" and "Radarr (4k)"
+func (g alarmGroup) Sources() []string {
+	var groupSources []string
+	for _, alarm := range g.Alarms {
+		source := alarm.Source
+		if alarm.Instance != "" {
+			source += " (" + alarm.Instance + ")"
+		}
+		if !slices.Contains(groupSources, source) {
+			groupSources = append(groupSources, source)
+		}
+	}
+
+	return groupSources
+}
+
+// Acknowledged returns true if every alarm of the group is acknowledged
+func (g alarmGroup) Acknowledged() bool {
+	for _, alarm := range g.Alarms {
+		if !alarm.Acknowledged {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Highest returns the alarm of the group with the highest severity, used for the group status
+func (g alarmGroup) Highest() trackedAlarm {
+	highest := g.Alarms[0]
+	for _, alarm := range g.Alarms[1:] {
+		if alarm.NormalizedSeverity() > highest.NormalizedSeverity() {
+			highest = alarm
+		}
+	}
+
+	return highest
+}
+
+// parseGroupBy parses the comma separated group_by query parameter, like "summary,source"
+func parseGroupBy(groupBy string) ([]string, error) {
+	if groupBy == "" {
+		return nil, nil
+	}
+	var keys []string
+	for _, key := range strings.Split(groupBy, ",") {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !slices.Contains(groupKeys, key) {
+			return nil, fmt.Errorf("group_by key '%s' is not valid. Valid keys are: %s", key, strings.Join(groupKeys, ", "))
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// groupAlarms groups the alarms with the same keys, keeping the order of the first alarm of each group.
+// The summaries are grouped if they are similar, and the sources if they are in the same ALARMS_SOURCE_GROUP_<NAME>.
+// Without keys, each alarm is in its own group.
+func groupAlarms(alarms []trackedAlarm, keys []string) []alarmGroup {
+	sourceGroups := map[string]string{}
+	for group, groupSources := range config.Current().IFrames.AlarmsSourceGroups {
+		for _, source := range groupSources {
+			sourceGroups[source] = group
+		}
+	}
+
+	var groups []alarmGroup
+	for _, alarm := range alarms {
+		var keyValues, words []string
+		for _, key := range keys {
+			switch key {
+			case "summary":
+				words = summaryWords(alarm.Summary)
+			case "property":
+				keyValues = append(keyValues, strings.ToLower(alarm.Property))
+			case "source":
+				source := strings.ToLower(alarm.Source)
+				if group, ok := sourceGroups[source]; ok {
+					source = "group:" + group
+				}
+				keyValues = append(keyValues, source)
+			case "severity":
+				keyValues = append(keyValues, alarm.NormalizedSeverity().String())
+			}
+		}
+		key := strings.Join(keyValues, "\x00")
+
+		i := -1
+		if len(keys) > 0 {
+			i = slices.IndexFunc(groups, func(g alarmGroup) bool {
+				return g.key == key && (!slices.Contains(keys, "summary") || similarity(g.words, words) >= summarySimilarity)
+			})
+		}
+		if i == -1 {
+			hash := sha256.Sum256([]byte(key + "\x00" + strings.Join(words, " ") + "\x00" + AckFingerprint(alarm.Alarm)))
+			groups = append(groups, alarmGroup{ID: hex.EncodeToString(hash[:8]), words: words, key: key})
+			i = len(groups) - 1
+		}
+		groups[i].Alarms = append(groups[i].Alarms, alarm)
+	}
+
+	return groups
+}
+
+// summaryWords returns the unique words of a summary in lowercase, without numbers and punctuation
+func summaryWords(summary string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(summary), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if !slices.Contains(words, word) {
+			words = append(words, word)
+		}
+	}
+
+	return words
+}
+
+// similarity returns the Jaccard similarity of two sets of words, from 0 to 1
+func similarity(a, b []string) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	var common int
+	for _, word := range a {
+		if slices.Contains(b, word) {
+			common++
+		}
+	}
+
+	return float64(common) / float64(len(a)+len(b)-common)
+}
@@ -0,0 +1,65 @@
+package alarms
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+func TestGroupAlarms(t *testing.T) {
+	defer config.Set(config.Current())
+	configs := &config.Configs{}
+	configs.IFrames.AlarmsSourceGroups = map[string][]string{"arr": {"sonarr", "radarr"}}
+	config.Set(configs)
+
+	alarms := []trackedAlarm{
+		{Alarm: Alarm{Source: "Sonarr", Summary: "Missing root folder: /data/tv", Status: "ERROR"}},
+		{Alarm: Alarm{Source: "Netdata", Summary: "Disk space usage", Status: "WARNING"}},
+		{Alarm: Alarm{Source: "Radarr", Summary: "Missing root folder: /data/movies", Status: "ERROR"}},
+		{Alarm: Alarm{Source: "Radarr", Summary: "Indexers are unavailable due to failures", Status: "WARNING"}},
+		{Alarm: Alarm{Source: "Netdata", Summary: "Missing root folder", Status: "ERROR"}},
+	}
+	tests := []struct {
+		groupBy  string
+		expected [][]int
+	}{
+		{"", [][]int{{0}, {1}, {2}, {3}, {4}}},
+		{"summary", [][]int{{0, 2, 4}, {1}, {3}}},
+		{"summary,source", [][]int{{0, 2}, {1}, {3}, {4}}},
+		{"source", [][]int{{0, 2, 3}, {1, 4}}},
+		{"severity", [][]int{{0, 2, 4}, {1, 3}}},
+	}
+	for _, test := range tests {
+		keys, err := parseGroupBy(test.groupBy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var actual [][]int
+		for _, group := range groupAlarms(alarms, keys) {
+			var indexes []int
+			for _, alarm := range group.Alarms {
+				indexes = append(indexes, slices.IndexFunc(alarms, func(a trackedAlarm) bool { return a.Summary == alarm.Summary }))
+			}
+			actual = append(actual, indexes)
+		}
+		if !slices.EqualFunc(actual, test.expected, slices.Equal) {
+			t.Errorf("group_by=%q: expected %v, got %v", test.groupBy, test.expected, actual)
+		}
+	}
+
+	groups := groupAlarms(alarms, []string{"summary"})
+	if sources := groups[0].Sources(); !slices.Equal(sources, []string{"Sonarr", "Radarr", "Netdata"}) {
+		t.Errorf("expected the group sources, got %v", sources)
+	}
+	if highest := groups[2].Highest(); highest.Status != "WARNING" {
+		t.Errorf("expected the WARNING alarm to be the highest, got %v", highest)
+	}
+	if again := groupAlarms(alarms, []string{"summary"}); again[0].ID != groups[0].ID {
+		t.Error("expected the group IDs to be stable")
+	}
+
+	if _, err := parseGroupBy("summary,name"); err == nil {
+		t.Error("expected an error parsing an invalid group_by key")
+	}
+}
@@ -0,0 +1,339 @@
+package alarms
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// @Summary Alarms iFrame
+// @Description Returns an iFrame with alarms from multiple sources.
+// @Success 200 {string} string "HTML content"
+// @Produce html
+// @Param theme query string false "Homarr theme, defaults to light. If it's different from your Homarr theme, the background turns white" Example(light)
+// @Param api_url query string true "API URL used by your browser. Used by the iFrames to receive updates, if there is an update, the iFrame updates the changed items without reloading. If not specified, the iFrames will never try to reload." Example(https://sub.domain.com)
+// @Param alarms query string true "Alarms to show. Available values: netdata, radarr, lidarr, sonarr, prowlarr, speedtest-tracker, pihole, kavita, kaizoku, changedetectionio, backrest, openarchiver, webhook. Use name:instance to get alarms from a named instance, like radarr:4k." Example(netdata,radarr,radarr:4k)
+// @Param sort_desc query bool false "Sort alarms in descending order. Defaults to false." Example(false)
+// @Param regex_include query bool false "Show only alarms that match or not the regex. Default to true." Example(false)
+// @Param changedetectionio_show_viewed query bool false "Show viewed alarms from changedetection.io. Defaults to true." Example(false)
+// @Param mode query string false "'active' shows the current alarms, 'resolved' shows the alarms resolved in the last 'since' duration, the last resolved first. The resolved mode requires the alarms history. Defaults to active." Example(resolved)
+// @Param since query string false "In the resolved mode, how long ago the alarms can be resolved. Defaults to 24h." Example(12h)
+// @Param acknowledged query string false "'dim' shows the acknowledged alarms dimmed, 'hide' hides them. The snoozed alarms are always hidden. Defaults to dim." Example(hide)
+// @Param min_severity query string false "Show only alarms with this severity or higher. The severities are ok, info, warning, error, and critical, normalized from the status of each source." Example(error)
+// @Param severity query string false "Show only alarms with these severities, comma separated." Example(warning,critical)
+// @Param filter query string false "Filter expression, like 'summary~\"disk\" AND source=Netdata'. The conditions are field=value, field!=value, field~regex, and field!~regex, combined with AND, OR, NOT, and parentheses. The fields are source, instance, summary, status, severity, property, value, and url." Example(severity=critical OR source=Netdata)
+// @Param filter_preset query string false "Comma separated names of the filter presets set in ALARMS_FILTER_PRESET_<NAME>." Example(ops)
+// @Param group_by query string false "Group the alarms with the same keys in a collapsible card. Comma separated list of: summary (similar summaries), property, source (the same source or ALARMS_SOURCE_GROUP_<NAME>), and severity." Example(summary,source)
+// @Param layout query string false "'cards' shows a card for each alarm, 'summary' shows a compact summary with the number of alarms of each source by severity, or 'All clear'. Defaults to cards." Example(summary)
+// @Param link query string false "In the summary layout, URL opened when the summary is clicked. Defaults to the alarms iFrame with the same query parameters." Example(https://homarr.domain.com/board/alarms)
+// @Router /iframe/alarms [get]
+func iFrameHandler(c *gin.Context) {
+	a, err := New()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	a.GetiFrame(c)
+}
+
+// @Summary Get the hash of the alarms
+// @Description Get the hash of the alarms. Used by the iFrames to check updates and reload the iframe.
+// @Success 200 {object} sources.HashResponse
+// @Produce json
+// @Param alarms query string true "Alarms to show. Available values: netdata, radarr, lidarr, sonarr, prowlarr, speedtest-tracker, pihole, kavita, kaizoku, changedetectionio, backrest, openarchiver, webhook. Use name:instance to get alarms from a named instance, like radarr:4k." Example(netdata,radarr,radarr:4k)
+// @Param sort_desc query bool false "Sort alarms in descending order. Defaults to false." Example(false)
+// @Param regex_include query bool false "Show only alarms that match or not the regex. Default to true." Example(false)
+// @Param changedetectionio_show_viewed query bool false "Show viewed alarms from changedetection.io. Defaults to true." Example(false)
+// @Param mode query string false "'active' shows the current alarms, 'resolved' shows the alarms resolved in the last 'since' duration, the last resolved first. The resolved mode requires the alarms history. Defaults to active." Example(resolved)
+// @Param since query string false "In the resolved mode, how long ago the alarms can be resolved. Defaults to 24h." Example(12h)
+// @Param acknowledged query string false "'dim' shows the acknowledged alarms dimmed, 'hide' hides them. The snoozed alarms are always hidden. Defaults to dim." Example(hide)
+// @Param min_severity query string false "Show only alarms with this severity or higher. The severities are ok, info, warning, error, and critical, normalized from the status of each source." Example(error)
+// @Param severity query string false "Show only alarms with these severities, comma separated." Example(warning,critical)
+// @Param filter query string false "Filter expression, like 'summary~\"disk\" AND source=Netdata'. The conditions are field=value, field!=value, field~regex, and field!~regex, combined with AND, OR, NOT, and parentheses. The fields are source, instance, summary, status, severity, property, value, and url." Example(severity=critical OR source=Netdata)
+// @Param filter_preset query string false "Comma separated names of the filter presets set in ALARMS_FILTER_PRESET_<NAME>." Example(ops)
+// @Router /hash/alarms [get]
+func hashHandler(c *gin.Context) {
+	a, err := New()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	a.GetHash(c)
+}
+
+// @Summary Get the alarms data
+// @Description Get the alarms as JSON, in the same order as the iFrame. A source that fails or times out returns an ERROR alarm with the error in the property field.
+// @Success 200 {object} Data
+// @Produce json
+// @Param alarms query string true "Alarms to show. Available values: netdata, radarr, lidarr, sonarr, prowlarr, speedtest-tracker, pihole, kavita, kaizoku, changedetectionio, backrest, openarchiver, webhook. Use name:instance to get alarms from a named instance, like radarr:4k." Example(netdata,radarr,radarr:4k)
+// @Param sort_desc query bool false "Sort alarms in descending order. Defaults to false." Example(false)
+// @Param regex_include query bool false "Show only alarms that match or not the regex. Default to true." Example(false)
+// @Param changedetectionio_show_viewed query bool false "Show viewed alarms from changedetection.io. Defaults to true." Example(false)
+// @Param mode query string false "'active' shows the current alarms, 'resolved' shows the alarms resolved in the last 'since' duration, the last resolved first. The resolved mode requires the alarms history. Defaults to active." Example(resolved)
+// @Param since query string false "In the resolved mode, how long ago the alarms can be resolved. Defaults to 24h." Example(12h)
+// @Param acknowledged query string false "'dim' shows the acknowledged alarms dimmed, 'hide' hides them. The snoozed alarms are always hidden. Defaults to dim." Example(hide)
+// @Param min_severity query string false "Show only alarms with this severity or higher. The severities are ok, info, warning, error, and critical, normalized from the status of each source." Example(error)
+// @Param severity query string false "Show only alarms with these severities, comma separated." Example(warning,critical)
+// @Param filter query string false "Filter expression, like 'summary~\"disk\" AND source=Netdata'. The conditions are field=value, field!=value, field~regex, and field!~regex, combined with AND, OR, NOT, and parentheses. The fields are source, instance, summary, status, severity, property, value, and url." Example(severity=critical OR source=Netdata)
+// @Param filter_preset query string false "Comma separated names of the filter presets set in ALARMS_FILTER_PRESET_<NAME>." Example(ops)
+// @Param group_by query string false "Group the alarms with the same keys in a collapsible card. Comma separated list of: summary (similar summaries), property, source (the same source or ALARMS_SOURCE_GROUP_<NAME>), and severity." Example(summary,source)
+// @Router /data/alarms [get]
+func dataHandler(c *gin.Context) {
+	a, err := New()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	a.GetData(c)
+}
+
+// @Summary Alarms RSS feed
+// @Description Returns the alarms as an RSS 2.0 feed. Each item has a GUID derived from the alarm fingerprint and links to the alarm URL.
+// @Success 200 {string} string "RSS feed"
+// @Produce xml
+// @Param alarms query string true "Alarms to show. Available values: netdata, radarr, lidarr, sonarr, prowlarr, speedtest-tracker, pihole, kavita, kaizoku, changedetectionio, backrest, openarchiver, webhook. Use name:instance to get alarms from a named instance, like radarr:4k." Example(netdata,radarr,radarr:4k)
+// @Param sort_desc query bool false "Sort alarms in descending order. Defaults to false." Example(false)
+// @Param regex_include query bool false "Show only alarms that match or not the regex. Default to true." Example(false)
+// @Param changedetectionio_show_viewed query bool false "Show viewed alarms from changedetection.io. Defaults to true." Example(false)
+// @Param mode query string false "'active' shows the current alarms, 'resolved' shows the alarms resolved in the last 'since' duration, the last resolved first. The resolved mode requires the alarms history. Defaults to active." Example(resolved)
+// @Param since query string false "In the resolved mode, how long ago the alarms can be resolved. Defaults to 24h." Example(12h)
+// @Param acknowledged query string false "'dim' shows the acknowledged alarms dimmed, 'hide' hides them. The snoozed alarms are always hidden. Defaults to dim." Example(hide)
+// @Param min_severity query string false "Show only alarms with this severity or higher. The severities are ok, info, warning, error, and critical, normalized from the status of each source." Example(error)
+// @Param severity query string false "Show only alarms with these severities, comma separated." Example(warning,critical)
+// @Param filter query string false "Filter expression, like 'summary~\"disk\" AND source=Netdata'. The conditions are field=value, field!=value, field~regex, and field!~regex, combined with AND, OR, NOT, and parentheses. The fields are source, instance, summary, status, severity, property, value, and url." Example(severity=critical OR source=Netdata)
+// @Param filter_preset query string false "Comma separated names of the filter presets set in ALARMS_FILTER_PRESET_<NAME>." Example(ops)
+// @Router /feed/alarms.rss [get]
+func RSSFeedHandler(c *gin.Context) {
+	a, err := New()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	a.getFeed(c, "rss")
+}
+
+// @Summary Alarms Atom feed
+// @Description Returns the alarms as an Atom feed. Each entry has an ID derived from the alarm fingerprint and links to the alarm URL.
+// @Success 200 {string} string "Atom feed"
+// @Produce xml
+// @Param alarms query string true "Alarms to show. Available values: netdata, radarr, lidarr, sonarr, prowlarr, speedtest-tracker, pihole, kavita, kaizoku, changedetectionio, backrest, openarchiver, webhook. Use name:instance to get alarms from a named instance, like radarr:4k." Example(netdata,radarr,radarr:4k)
+// @Param sort_desc query bool false "Sort alarms in descending order. Defaults to false." Example(false)
+// @Param regex_include query bool false "Show only alarms that match or not the regex. Default to true." Example(false)
+// @Param changedetectionio_show_viewed query bool false "Show viewed alarms from changedetection.io. Defaults to true." Example(false)
+// @Param mode query string false "'active' shows the current alarms, 'resolved' shows the alarms resolved in the last 'since' duration, the last resolved first. The resolved mode requires the alarms history. Defaults to active." Example(resolved)
+// @Param since query string false "In the resolved mode, how long ago the alarms can be resolved. Defaults to 24h." Example(12h)
+// @Param acknowledged query string false "'dim' shows the acknowledged alarms dimmed, 'hide' hides them. The snoozed alarms are always hidden. Defaults to dim." Example(hide)
+// @Param min_severity query string false "Show only alarms with this severity or higher. The severities are ok, info, warning, error, and critical, normalized from the status of each source." Example(error)
+// @Param severity query string false "Show only alarms with these severities, comma separated." Example(warning,critical)
+// @Param filter query string false "Filter expression, like 'summary~\"disk\" AND source=Netdata'. The conditions are field=value, field!=value, field~regex, and field!~regex, combined with AND, OR, NOT, and parentheses. The fields are source, instance, summary, status, severity, property, value, and url." Example(severity=critical OR source=Netdata)
+// @Param filter_preset query string false "Comma separated names of the filter presets set in ALARMS_FILTER_PRESET_<NAME>." Example(ops)
+// @Router /feed/alarms.atom [get]
+func AtomFeedHandler(c *gin.Context) {
+	a, err := New()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	a.getFeed(c, "atom")
+}
+
+// @Summary Alarms JSON feed
+// @Description Returns the alarms as a JSON Feed 1.1. Each item has an ID derived from the alarm fingerprint and links to the alarm URL.
+// @Success 200 {string} string "JSON feed"
+// @Produce json
+// @Param alarms query string true "Alarms to show. Available values: netdata, radarr, lidarr, sonarr, prowlarr, speedtest-tracker, pihole, kavita, kaizoku, changedetectionio, backrest, openarchiver, webhook. Use name:instance to get alarms from a named instance, like radarr:4k." Example(netdata,radarr,radarr:4k)
+// @Param sort_desc query bool false "Sort alarms in descending order. Defaults to false." Example(false)
+// @Param regex_include query bool false "Show only alarms that match or not the regex. Default to true." Example(false)
+// @Param changedetectionio_show_viewed query bool false "Show viewed alarms from changedetection.io. Defaults to true." Example(false)
+// @Param mode query string false "'active' shows the current alarms, 'resolved' shows the alarms resolved in the last 'since' duration, the last resolved first. The resolved mode requires the alarms history. Defaults to active." Example(resolved)
+// @Param since query string false "In the resolved mode, how long ago the alarms can be resolved. Defaults to 24h." Example(12h)
+// @Param acknowledged query string false "'dim' shows the acknowledged alarms dimmed, 'hide' hides them. The snoozed alarms are always hidden. Defaults to dim." Example(hide)
+// @Param min_severity query string false "Show only alarms with this severity or higher. The severities are ok, info, warning, error, and critical, normalized from the status of each source." Example(error)
+// @Param severity query string false "Show only alarms with these severities, comma separated." Example(warning,critical)
+// @Param filter query string false "Filter expression, like 'summary~\"disk\" AND source=Netdata'. The conditions are field=value, field!=value, field~regex, and field!~regex, combined with AND, OR, NOT, and parentheses. The fields are source, instance, summary, status, severity, property, value, and url." Example(severity=critical OR source=Netdata)
+// @Param filter_preset query string false "Comma separated names of the filter presets set in ALARMS_FILTER_PRESET_<NAME>." Example(ops)
+// @Router /feed/alarms.json [get]
+func JSONFeedHandler(c *gin.Context) {
+	a, err := New()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	a.getFeed(c, "json")
+}
+
+// @Summary Alarms SVG badge
+// @Description Returns an SVG badge with the number of alarms by severity, colored with the highest severity, or 'all clear'.
+// @Success 200 {string} string "SVG badge"
+// @Produce image/svg+xml
+// @Param alarms query string true "Alarms to show. Available values: netdata, radarr, lidarr, sonarr, prowlarr, speedtest-tracker, pihole, kavita, kaizoku, changedetectionio, backrest, openarchiver, webhook. Use name:instance to get alarms from a named instance, like radarr:4k." Example(netdata,radarr,radarr:4k)
+// @Param sort_desc query bool false "Sort alarms in descending order. Defaults to false." Example(false)
+// @Param regex_include query bool false "Show only alarms that match or not the regex. Default to true." Example(false)
+// @Param changedetectionio_show_viewed query bool false "Show viewed alarms from changedetection.io. Defaults to true." Example(false)
+// @Param mode query string false "'active' shows the current alarms, 'resolved' shows the alarms resolved in the last 'since' duration, the last resolved first. The resolved mode requires the alarms history. Defaults to active." Example(resolved)
+// @Param since query string false "In the resolved mode, how long ago the alarms can be resolved. Defaults to 24h." Example(12h)
+// @Param acknowledged query string false "'dim' shows the acknowledged alarms dimmed, 'hide' hides them. The snoozed alarms are always hidden. Defaults to dim." Example(hide)
+// @Param min_severity query string false "Show only alarms with this severity or higher. The severities are ok, info, warning, error, and critical, normalized from the status of each source." Example(error)
+// @Param severity query string false "Show only alarms with these severities, comma separated." Example(warning,critical)
+// @Param filter query string false "Filter expression, like 'summary~\"disk\" AND source=Netdata'. The conditions are field=value, field!=value, field~regex, and field!~regex, combined with AND, OR, NOT, and parentheses. The fields are source, instance, summary, status, severity, property, value, and url." Example(severity=critical OR source=Netdata)
+// @Param filter_preset query string false "Comma separated names of the filter presets set in ALARMS_FILTER_PRESET_<NAME>." Example(ops)
+// @Param label query string false "Text on the left of the badge. Defaults to alarms." Example(homelab)
+// @Router /badge/alarms.svg [get]
+func BadgeHandler(c *gin.Context) {
+	a, err := New()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	a.GetBadge(c)
+}
+
+// @Summary Acknowledge an alarm
+// @Description Acknowledge an alarm, which is dimmed or hidden in the iFrame until it changes. Requires the action token of the alarm in the X-Action-Token header (embedded in the iFrame), the ACTIONS_SECRET as a bearer token, or the AUTH_TRUSTED_HEADER.
+// @Success 200 {object} sources.MessageResponse "Alarm acknowledged"
+// @Failure 401 {object} sources.MessageResponse "Unauthorized"
+// @Failure 403 {object} sources.MessageResponse "Origin not allowed"
+// @Produce json
+// @Param fingerprint query string true "The alarm fingerprint, from the data route." Example(3f2a9c1d8e7b6a50)
+// @Param X-Action-Token header string false "Action token of the alarm."
+// @Param Authorization header string false "Bearer ACTIONS_SECRET."
+// @Router /iframe/alarms/acknowledge [post]
+func acknowledgeHandler(c *gin.Context) {
+	fingerprint, ok := getFingerprint(c)
+	if !ok {
+		return
+	}
+
+	if err := acknowledge(fingerprint, false, 0); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	sources.InvalidateCache("alarms")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Alarm acknowledged"})
+}
+
+// @Summary Remove the acknowledgment of an alarm
+// @Description Remove the acknowledgment or snooze of an alarm. Requires the action token of the alarm in the X-Action-Token header (embedded in the iFrame), the ACTIONS_SECRET as a bearer token, or the AUTH_TRUSTED_HEADER.
+// @Success 200 {object} sources.MessageResponse "Alarm unacknowledged"
+// @Failure 401 {object} sources.MessageResponse "Unauthorized"
+// @Failure 403 {object} sources.MessageResponse "Origin not allowed"
+// @Produce json
+// @Param fingerprint query string true "The alarm fingerprint, from the data route." Example(3f2a9c1d8e7b6a50)
+// @Param X-Action-Token header string false "Action token of the alarm."
+// @Param Authorization header string false "Bearer ACTIONS_SECRET."
+// @Router /iframe/alarms/acknowledge [delete]
+func unacknowledgeHandler(c *gin.Context) {
+	fingerprint, ok := getFingerprint(c)
+	if !ok {
+		return
+	}
+
+	store, err := getAckStore()
+	if err == nil {
+		err = store.DeleteAck(fingerprint)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	sources.InvalidateCache("alarms")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Alarm unacknowledged"})
+}
+
+// @Summary Snooze an alarm
+// @Description Hide an alarm from the iFrame for a duration or until it changes. Requires the action token of the alarm in the X-Action-Token header (embedded in the iFrame), the ACTIONS_SECRET as a bearer token, or the AUTH_TRUSTED_HEADER.
+// @Success 200 {object} sources.MessageResponse "Alarm snoozed"
+// @Failure 401 {object} sources.MessageResponse "Unauthorized"
+// @Failure 403 {object} sources.MessageResponse "Origin not allowed"
+// @Produce json
+// @Param fingerprint query string true "The alarm fingerprint, from the data route." Example(3f2a9c1d8e7b6a50)
+// @Param duration query string true "How long to snooze the alarm, like '1h' or '1d', or 'changed' to snooze it until it changes." Example(1h)
+// @Param X-Action-Token header string false "Action token of the alarm."
+// @Param Authorization header string false "Bearer ACTIONS_SECRET."
+// @Router /iframe/alarms/snooze [post]
+func snoozeHandler(c *gin.Context) {
+	fingerprint, ok := getFingerprint(c)
+	if !ok {
+		return
+	}
+	duration, err := parseSnoozeDuration(c.Query("duration"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	if err := acknowledge(fingerprint, true, duration); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	sources.InvalidateCache("alarms")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Alarm snoozed"})
+}
+
+// getFingerprint returns the fingerprint query parameter of the acknowledge and snooze routes.
+// If it's invalid, it writes the error response and returns false.
+func getFingerprint(c *gin.Context) (string, bool) {
+	fingerprint := c.Query("fingerprint")
+	if !ackFingerprintRegex.MatchString(fingerprint) {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "fingerprint must be the 16 hexadecimal characters fingerprint of an alarm"})
+		return "", false
+	}
+
+	return fingerprint, true
+}
+
+// maxIngestBodySize is the maximum size of the ingest route body
+const maxIngestBodySize = 1 << 20
+
+// IngestResponse is the response of the ingest route
+type IngestResponse struct {
+	Message string `json:"message"`
+	// Received is the number of alarms added or replaced
+	Received int `json:"received"`
+	// Resolved is the number of alarms resolved
+	Resolved int `json:"resolved"`
+}
+
+// @Summary Ingest alarms
+// @Description Receives alarms from external tools, shown by the webhook alarms source. The body can be an Alertmanager webhook payload (also sent by the Grafana alerting webhook), an alarm, or a list of alarms. An alarm is shown until it's resolved, or until its TTL expires if it's not sent again. Requires the ACTIONS_SECRET as a bearer token or the AUTH_TRUSTED_HEADER.
+// @Success 200 {object} IngestResponse "Alarms received"
+// @Failure 400 {object} sources.MessageResponse "Invalid payload"
+// @Failure 401 {object} sources.MessageResponse "Unauthorized"
+// @Accept json
+// @Produce json
+// @Param body body IngestAlarm true "An Alertmanager webhook payload, an alarm, or a list of alarms."
+// @Param source query string false "Source name of the alarms without one. Defaults to Alertmanager for Alertmanager payloads and Webhook for the others." Example(Grafana)
+// @Param Authorization header string false "Bearer ACTIONS_SECRET."
+// @Router /alarms/ingest [post]
+func IngestHandler(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIngestBodySize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "error reading body: " + err.Error()})
+		return
+	}
+	alarms, resolved, err := parseIngestPayload(body, c.Query("source"), time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	if err := ingest(alarms, resolved); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	sources.InvalidateCache("alarms")
+
+	c.JSON(http.StatusOK, IngestResponse{
+		Message:  fmt.Sprintf("%d alarms received, %d resolved", len(alarms), len(resolved)),
+		Received: len(alarms),
+		Resolved: len(resolved),
+	})
+}
@@ -0,0 +1,407 @@
+package alarms
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+var (
+	// historyEntriesBucket has the history entries, by fingerprint and first seen time
+	historyEntriesBucket = []byte("entries")
+	// historyActiveBucket has the key of the active entry of each fingerprint
+	historyActiveBucket = []byte("active")
+
+	currentHistory   *History
+	currentHistoryMu sync.Mutex
+)
+
+// Fingerprint returns a stable ID of an alarm, used to know if it's the same alarm in different requests.
+// The time, value, and property are not used, as some sources put changing values in them, like durations.
+func Fingerprint(alarm Alarm) string {
+	hash := sha256.Sum256([]byte(alarm.Source + "\x00" + alarm.Instance + "\x00" + alarm.Summary + "\x00" + alarm.Status))
+
+	return hex.EncodeToString(hash[:8])
+}
+
+// HistoryEntry is an occurrence of an alarm, from the first time it was seen until it was resolved
+type HistoryEntry struct {
+	Fingerprint string `json:"fingerprint"`
+	// Integration is the name of the integration that returned the alarm, like "radarr"
+	Integration string `json:"integration"`
+	// Alarm is the last version of the alarm seen
+	Alarm     Alarm     `json:"alarm"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	// ResolvedAt is the first time the alarm wasn't returned by the integration anymore, nil if it's active
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// Duration returns how long the alarm was active, until now if it's still active
+func (e HistoryEntry) Duration(now time.Time) time.Duration {
+	if e.ResolvedAt != nil {
+		return e.ResolvedAt.Sub(e.FirstSeen)
+	}
+
+	return now.Sub(e.FirstSeen)
+}
+
+// History stores the alarms returned by the integrations in a bbolt file, so it's known
+// when they fired and were resolved, even after they disappear from the sources
+type History struct {
+	db  *bolt.DB
+	now func() time.Time
+}
+
+// OpenHistory opens or creates the history file
+func OpenHistory(path string) (*History, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("error opening alarms history file: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{historyEntriesBucket, historyActiveBucket, historyAcksBucket, historyIngestedBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creating alarms history buckets: %w", err)
+	}
+
+	return &History{db: db, now: time.Now}, nil
+}
+
+// Close closes the history file
+func (h *History) Close() error {
+	return h.db.Close()
+}
+
+// getHistory returns the history of the ALARMS_HISTORY_FILE config, or nil if it's not set.
+// If the file changes after the configs are reloaded, the old one is closed.
+func getHistory() (*History, error) {
+	path := config.Current().IFrames.AlarmsHistoryFile
+
+	currentHistoryMu.Lock()
+	defer currentHistoryMu.Unlock()
+	if currentHistory != nil && currentHistory.db.Path() == path {
+		return currentHistory, nil
+	}
+	if currentHistory != nil {
+		currentHistory.Close()
+		currentHistory = nil
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	history, err := OpenHistory(path)
+	if err != nil {
+		return nil, err
+	}
+	currentHistory = history
+
+	return history, nil
+}
+
+// Record records the alarms returned by an integration instance. The active entries of the instance
+// that aren't in alarms are resolved, unless failed is true, as the alarms of an integration that
+// can't be reached are unknown. CLEAR alarms are not active, so they resolve their entries.
+// It returns the entries that fired and were resolved.
+func (h *History) Record(integration, instance string, alarms []Alarm, failed bool) (fired, resolved []HistoryEntry, err error) {
+	now := h.now()
+	err = h.db.Update(func(tx *bolt.Tx) error {
+		entries, active := tx.Bucket(historyEntriesBucket), tx.Bucket(historyActiveBucket)
+
+		seen := map[string]bool{}
+		for _, alarm := range alarms {
+			if alarm.Status == "CLEAR" {
+				continue
+			}
+			fingerprint := Fingerprint(alarm)
+			seen[fingerprint] = true
+
+			entry := HistoryEntry{Fingerprint: fingerprint, Integration: integration, FirstSeen: now}
+			key := bytes.Clone(active.Get([]byte(fingerprint)))
+			isNew := key == nil
+			if isNew {
+				key = historyEntryKey(fingerprint, now)
+			} else if err := json.Unmarshal(entries.Get(key), &entry); err != nil {
+				return fmt.Errorf("error unmarshaling history entry: %w", err)
+			}
+			entry.Alarm, entry.LastSeen = alarm, now
+			if err := putHistoryEntry(entries, key, entry); err != nil {
+				return err
+			}
+			if err := active.Put([]byte(fingerprint), key); err != nil {
+				return err
+			}
+			if isNew {
+				fired = append(fired, entry)
+			}
+		}
+		if failed {
+			return nil
+		}
+
+		// The keys are deleted after iterating, as deleting with a cursor can skip the next key
+		var resolvedFingerprints [][]byte
+		err := active.ForEach(func(fingerprint, key []byte) error {
+			if seen[string(fingerprint)] {
+				return nil
+			}
+			var entry HistoryEntry
+			if err := json.Unmarshal(entries.Get(key), &entry); err != nil {
+				return fmt.Errorf("error unmarshaling history entry: %w", err)
+			}
+			if entry.Integration != integration || entry.Alarm.Instance != instance {
+				return nil
+			}
+			entry.ResolvedAt = &now
+			if err := putHistoryEntry(entries, key, entry); err != nil {
+				return err
+			}
+			resolvedFingerprints = append(resolvedFingerprints, bytes.Clone(fingerprint))
+			resolved = append(resolved, entry)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, fingerprint := range resolvedFingerprints {
+			if err := active.Delete(fingerprint); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("error recording alarms history: %w", err)
+	}
+
+	return fired, resolved, nil
+}
+
+// Entries returns the entries that were active after since, sorted by the
+// resolved time (the active ones first) and the first seen time, newest first
+func (h *History) Entries(since time.Time) ([]HistoryEntry, error) {
+	var result []HistoryEntry
+	err := h.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(historyEntriesBucket).ForEach(func(_, value []byte) error {
+			var entry HistoryEntry
+			if err := json.Unmarshal(value, &entry); err != nil {
+				return fmt.Errorf("error unmarshaling history entry: %w", err)
+			}
+			if entry.ResolvedAt == nil || entry.ResolvedAt.After(since) {
+				result = append(result, entry)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting alarms history: %w", err)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if (result[i].ResolvedAt == nil) != (result[j].ResolvedAt == nil) {
+			return result[i].ResolvedAt == nil
+		}
+		if result[i].ResolvedAt != nil && !result[i].ResolvedAt.Equal(*result[j].ResolvedAt) {
+			return result[i].ResolvedAt.After(*result[j].ResolvedAt)
+		}
+		return result[i].FirstSeen.After(result[j].FirstSeen)
+	})
+
+	return result, nil
+}
+
+// Active returns the active entries by fingerprint
+func (h *History) Active() (map[string]HistoryEntry, error) {
+	result := map[string]HistoryEntry{}
+	err := h.db.View(func(tx *bolt.Tx) error {
+		entries := tx.Bucket(historyEntriesBucket)
+		return tx.Bucket(historyActiveBucket).ForEach(func(fingerprint, key []byte) error {
+			var entry HistoryEntry
+			if err := json.Unmarshal(entries.Get(key), &entry); err != nil {
+				return fmt.Errorf("error unmarshaling history entry: %w", err)
+			}
+			result[string(fingerprint)] = entry
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting active alarms: %w", err)
+	}
+
+	return result, nil
+}
+
+// Prune removes the entries resolved before the retention, the old acknowledgments, and the expired ingested alarms
+func (h *History) Prune(retention time.Duration) error {
+	now := h.now()
+	before := now.Add(-retention)
+	err := h.db.Update(func(tx *bolt.Tx) error {
+		entries := tx.Bucket(historyEntriesBucket)
+		var keys [][]byte
+		err := entries.ForEach(func(key, value []byte) error {
+			var entry HistoryEntry
+			if err := json.Unmarshal(value, &entry); err != nil {
+				return fmt.Errorf("error unmarshaling history entry: %w", err)
+			}
+			if entry.ResolvedAt != nil && entry.ResolvedAt.Before(before) {
+				keys = append(keys, bytes.Clone(key))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := entries.Delete(key); err != nil {
+				return err
+			}
+		}
+		if err := pruneAcks(tx, now, retention); err != nil {
+			return err
+		}
+		return pruneIngested(tx, now)
+	})
+	if err != nil {
+		return fmt.Errorf("error pruning alarms history: %w", err)
+	}
+
+	return nil
+}
+
+// historyEntryKey returns the key of an entry, the fingerprint and the first seen time,
+// so each occurrence of an alarm has its own entry
+func historyEntryKey(fingerprint string, firstSeen time.Time) []byte {
+	return binary.BigEndian.AppendUint64([]byte(fingerprint+"/"), uint64(firstSeen.UnixNano()))
+}
+
+func putHistoryEntry(bucket *bolt.Bucket, key []byte, entry HistoryEntry) error {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error marshaling history entry: %w", err)
+	}
+
+	return bucket.Put(key, value)
+}
+
+// trackedAlarm is an alarm with its history, used by the iFrame and data routes.
+// The history fields are empty if the history is disabled.
+type trackedAlarm struct {
+	Alarm
+	FirstSeen  time.Time
+	ResolvedAt *time.Time
+	// AckFingerprint is the AckFingerprint of the alarm, used by the acknowledge and snooze buttons
+	AckFingerprint string
+	Acknowledged   bool
+}
+
+// String is used by the hash route, so it has the history and acknowledgment fields besides the alarm ones
+func (a trackedAlarm) String() string {
+	resolvedAt := ""
+	if a.ResolvedAt != nil {
+		resolvedAt = a.ResolvedAt.Format(time.RFC3339)
+	}
+
+	return fmt.Sprintf("trackedAlarm{%s, FirstSeen: %s, ResolvedAt: %s, Acknowledged: %t}", a.Alarm, a.FirstSeen.Format(time.RFC3339), resolvedAt, a.Acknowledged)
+}
+
+// Duration returns how long the alarm is or was active, or zero if it's unknown
+func (a trackedAlarm) Duration() time.Duration {
+	if a.FirstSeen.IsZero() {
+		return 0
+	}
+
+	return HistoryEntry{FirstSeen: a.FirstSeen, ResolvedAt: a.ResolvedAt}.Duration(time.Now())
+}
+
+// trackAlarms returns the alarms with the time they were first seen, if the history is enabled
+func trackAlarms(alarms []Alarm) ([]trackedAlarm, error) {
+	tracked := make([]trackedAlarm, len(alarms))
+	for i, alarm := range alarms {
+		tracked[i].Alarm = alarm
+		tracked[i].AckFingerprint = AckFingerprint(alarm)
+	}
+
+	history, err := getHistory()
+	if err != nil || history == nil {
+		return tracked, err
+	}
+	active, err := history.Active()
+	if err != nil {
+		return nil, err
+	}
+	for i, alarm := range alarms {
+		if entry, ok := active[Fingerprint(alarm)]; ok {
+			tracked[i].FirstSeen = entry.FirstSeen
+		}
+	}
+
+	return tracked, nil
+}
+
+// getResolvedAlarms returns the alarms of the integrations in alarmNames resolved after since,
+// the last resolved first. The regex filters them like in GetAlarms.
+func getResolvedAlarms(alarmNames []string, since time.Time, regex *regexp.Regexp, regexInclude bool) ([]trackedAlarm, error) {
+	history, err := getHistory()
+	if err != nil {
+		return nil, err
+	}
+	if history == nil {
+		return nil, fmt.Errorf("the alarms history is disabled, set ALARMS_HISTORY_FILE to enable it")
+	}
+
+	entries, err := history.Entries(since)
+	if err != nil {
+		return nil, err
+	}
+	var resolved []trackedAlarm
+	for _, entry := range entries {
+		alarmName := entry.Integration
+		if entry.Alarm.Instance != "" {
+			alarmName += ":" + entry.Alarm.Instance
+		}
+		if entry.ResolvedAt == nil || !slices.Contains(alarmNames, alarmName) {
+			continue
+		}
+		if regex != nil && matchesRegex(entry.Alarm, regex) != regexInclude {
+			continue
+		}
+		resolved = append(resolved, trackedAlarm{Alarm: entry.Alarm, FirstSeen: entry.FirstSeen, ResolvedAt: entry.ResolvedAt})
+	}
+
+	return resolved, nil
+}
+
+// formatDuration formats a duration like "45s", "12m", "3h 5m", or "2d 4h"
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
@@ -0,0 +1,105 @@
+package alarms
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	history, err := OpenHistory(filepath.Join(t.TempDir(), "alarms.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer history.Close()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	history.now = func() time.Time { return now }
+
+	diskAlarm := Alarm{Source: "Netdata", Summary: "Low disk space", Status: "WARNING", Value: "10GB"}
+	cpuAlarm := Alarm{Source: "Netdata", Summary: "High CPU", Status: "CRITICAL"}
+	radarrAlarm := Alarm{Source: "Radarr", Instance: "4k", Summary: "Indexer unavailable", Status: "ERROR"}
+
+	fired, _, err := history.Record("netdata", "", []Alarm{diskAlarm, cpuAlarm}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fired) != 2 {
+		t.Fatalf("expected 2 fired alarms, got %d", len(fired))
+	}
+	if _, _, err := history.Record("radarr", "4k", []Alarm{radarrAlarm}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// The value changes, but it's the same alarm
+	now = now.Add(time.Hour)
+	diskAlarm.Value = "5GB"
+	fired, resolved, err := history.Record("netdata", "", []Alarm{diskAlarm}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fired) != 0 || len(resolved) != 1 || resolved[0].Alarm.Summary != "High CPU" {
+		t.Fatalf("expected only the CPU alarm to be resolved, got fired %v and resolved %v", fired, resolved)
+	}
+	if duration := resolved[0].Duration(now); duration != time.Hour {
+		t.Errorf("expected the CPU alarm to last 1h, got %s", duration)
+	}
+
+	// A failed integration doesn't resolve its alarms
+	now = now.Add(time.Hour)
+	if _, resolved, err = history.Record("netdata", "", []Alarm{{Source: "Netdata", Summary: "Netdata unreachable", Status: "ERROR"}}, true); err != nil {
+		t.Fatal(err)
+	}
+	if len(resolved) != 0 {
+		t.Fatalf("expected no resolved alarms, got %v", resolved)
+	}
+
+	active, err := history.Active()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := active[Fingerprint(diskAlarm)]
+	if !ok || entry.Alarm.Value != "5GB" || !entry.FirstSeen.Equal(now.Add(-2*time.Hour)) {
+		t.Errorf("expected the disk alarm to be active since the first record with the last value, got %+v", entry)
+	}
+	if _, ok := active[Fingerprint(radarrAlarm)]; !ok {
+		t.Error("the alarms of other integrations should not be resolved")
+	}
+
+	// The CPU alarm fires again as a new occurrence, and the unreachable alarm is resolved
+	fired, resolved, err = history.Record("netdata", "", []Alarm{diskAlarm, cpuAlarm}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fired) != 1 || fired[0].Alarm.Summary != "High CPU" || len(resolved) != 1 || resolved[0].Alarm.Summary != "Netdata unreachable" {
+		t.Fatalf("expected the CPU alarm to fire and the unreachable alarm to be resolved, got fired %v and resolved %v", fired, resolved)
+	}
+	entries, err := history.Entries(now.Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 5 || entries[0].ResolvedAt != nil || entries[len(entries)-1].ResolvedAt == nil {
+		t.Fatalf("expected 5 entries with the active ones first, got %v", entries)
+	}
+
+	now = now.Add(48 * time.Hour)
+	if err := history.Prune(24 * time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ = history.Entries(time.Time{}); len(entries) != 3 {
+		t.Errorf("expected the resolved alarms to be pruned, got %d entries", len(entries))
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		45 * time.Second:            "45s",
+		12 * time.Minute:            "12m",
+		3*time.Hour + 5*time.Minute: "3h 5m",
+		52 * time.Hour:              "2d 4h",
+	}
+	for duration, expected := range tests {
+		if actual := formatDuration(duration); actual != expected {
+			t.Errorf("formatDuration(%s): expected %s, got %s", duration, expected, actual)
+		}
+	}
+}
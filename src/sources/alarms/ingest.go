@@ -0,0 +1,426 @@
+package alarms
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// webhookName is the name of the integration with the alarms received by the ingest route
+const webhookName = "webhook"
+
+var (
+	// historyIngestedBucket has the alarms received by the ingest route, by ID
+	historyIngestedBucket = []byte("ingested")
+	// memoryIngested keeps the received alarms when the history is disabled, so they're lost on restart
+	memoryIngested = newMemoryIngestStore()
+)
+
+func init() {
+	sources.Register(sources.Integration{
+		Name:   webhookName,
+		Title:  "Webhook",
+		Alarms: webhookAlarms,
+	})
+}
+
+// IngestedAlarm is an alarm received by the ingest route, like an Alertmanager alert
+type IngestedAlarm struct {
+	// ID identifies the alarm, so sending it again replaces it
+	ID         string    `json:"id"`
+	Alarm      Alarm     `json:"alarm"`
+	ReceivedAt time.Time `json:"received_at"`
+	// ExpiresAt is when the alarm stops being shown, if it's not sent again or resolved before
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ingestStore stores the alarms received by the ingest route
+type ingestStore interface {
+	// Ingested returns the alarms that didn't expire, sorted by ID
+	Ingested() ([]IngestedAlarm, error)
+	// UpdateIngested adds or replaces the alarms, and removes the resolved ones by ID
+	UpdateIngested(alarms []IngestedAlarm, resolved []string) error
+}
+
+// getIngestStore returns the history if it's enabled, so the received
+// alarms are kept after a restart, or a store in memory otherwise
+func getIngestStore() (ingestStore, error) {
+	history, err := getHistory()
+	if err != nil {
+		return nil, err
+	}
+	if history == nil {
+		return memoryIngested, nil
+	}
+
+	return history, nil
+}
+
+// webhookAlarms returns the alarms received by the ingest route
+func webhookAlarms(_ string, _ url.Values) ([]Alarm, error) {
+	store, err := getIngestStore()
+	if err != nil {
+		return nil, err
+	}
+	ingested, err := store.Ingested()
+	if err != nil {
+		return nil, err
+	}
+
+	alarms := make([]Alarm, len(ingested))
+	for i, alarm := range ingested {
+		alarms[i] = alarm.Alarm
+	}
+
+	return alarms, nil
+}
+
+// IngestAlarm is the generic alarm accepted by the ingest route, used by scripts
+type IngestAlarm struct {
+	// ID identifies the alarm, so sending it again replaces it. Defaults to the source and summary.
+	ID      string `json:"id"`
+	Summary string `json:"summary"`
+	// Status is like "WARNING", "ERROR", or "CRITICAL". Defaults to "WARNING".
+	Status string `json:"status"`
+	// Source defaults to the source query parameter, or "Webhook"
+	Source   string     `json:"source"`
+	Value    string     `json:"value"`
+	Property string     `json:"property"`
+	URL      string     `json:"url"`
+	Time     *time.Time `json:"time"`
+	// TTL is how long the alarm is shown if it's not sent again, like "1h". Defaults to ALARMS_INGEST_TTL.
+	TTL string `json:"ttl"`
+	// Resolved removes the alarm
+	Resolved bool `json:"resolved"`
+}
+
+// alertmanagerPayload is the payload of the Alertmanager webhook receiver, also sent by the Grafana alerting webhook
+type alertmanagerPayload struct {
+	ExternalURL string `json:"externalURL"`
+	Alerts      []struct {
+		// Status is "firing" or "resolved"
+		Status       string            `json:"status"`
+		Labels       map[string]string `json:"labels"`
+		Annotations  map[string]string `json:"annotations"`
+		StartsAt     time.Time         `json:"startsAt"`
+		EndsAt       time.Time         `json:"endsAt"`
+		GeneratorURL string            `json:"generatorURL"`
+		Fingerprint  string            `json:"fingerprint"`
+	} `json:"alerts"`
+}
+
+// parseIngestPayload parses an Alertmanager payload, a generic alarm, or a list of generic alarms.
+// It returns the alarms to add or replace and the IDs of the resolved ones.
+// source is the source name of the alarms without one, or "" to use the default.
+func parseIngestPayload(body []byte, source string, now time.Time) (alarms []IngestedAlarm, resolved []string, err error) {
+	body = []byte(strings.TrimSpace(string(body)))
+	if len(body) == 0 {
+		return nil, nil, fmt.Errorf("the body must be an Alertmanager payload, an alarm, or a list of alarms")
+	}
+
+	if body[0] == '{' {
+		var payload struct {
+			Alerts json.RawMessage `json:"alerts"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, nil, fmt.Errorf("error parsing body: %w", err)
+		}
+		if payload.Alerts != nil {
+			return parseAlertmanagerPayload(body, source, now)
+		}
+		body = []byte("[" + string(body) + "]")
+	}
+
+	var genericAlarms []IngestAlarm
+	if err := json.Unmarshal(body, &genericAlarms); err != nil {
+		return nil, nil, fmt.Errorf("error parsing body: %w", err)
+	}
+	if source == "" {
+		source = "Webhook"
+	}
+	ttl := config.Current().IFrames.AlarmsIngestTTL
+	for i, generic := range genericAlarms {
+		if generic.Summary == "" && generic.ID == "" {
+			return nil, nil, fmt.Errorf("alarm %d: summary or id is required", i)
+		}
+		if generic.Source == "" {
+			generic.Source = source
+		}
+		id := generic.ID
+		if id == "" {
+			id = ingestID(generic.Source, generic.Summary)
+		}
+		if generic.Resolved {
+			resolved = append(resolved, id)
+			continue
+		}
+		if generic.Summary == "" {
+			return nil, nil, fmt.Errorf("alarm %d: summary is required", i)
+		}
+
+		alarmTTL := ttl
+		if generic.TTL != "" {
+			alarmTTL, err = time.ParseDuration(generic.TTL)
+			if err != nil || alarmTTL <= 0 {
+				return nil, nil, fmt.Errorf("alarm %d: ttl must be a positive duration, like '1h'", i)
+			}
+		}
+		status := strings.ToUpper(generic.Status)
+		if status == "" {
+			status = "WARNING"
+		}
+		alarm := Alarm{
+			Summary:         generic.Summary,
+			URL:             generic.URL,
+			Status:          status,
+			Value:           generic.Value,
+			Property:        generic.Property,
+			Source:          generic.Source,
+			BackgroundColor: "black",
+		}
+		if generic.Time != nil {
+			alarm.Time = generic.Time.Local()
+		}
+		alarms = append(alarms, IngestedAlarm{ID: id, Alarm: alarm, ReceivedAt: now, ExpiresAt: now.Add(alarmTTL)})
+	}
+
+	return alarms, resolved, nil
+}
+
+func parseAlertmanagerPayload(body []byte, source string, now time.Time) (alarms []IngestedAlarm, resolved []string, err error) {
+	var payload alertmanagerPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, nil, fmt.Errorf("error parsing Alertmanager payload: %w", err)
+	}
+	if source == "" {
+		source = "Alertmanager"
+	}
+	ttl := config.Current().IFrames.AlarmsIngestTTL
+
+	for _, alert := range payload.Alerts {
+		id := alert.Fingerprint
+		if id == "" {
+			id = labelsFingerprint(alert.Labels)
+		}
+		id = "alertmanager:" + id
+		if alert.Status == "resolved" {
+			resolved = append(resolved, id)
+			continue
+		}
+
+		summary := firstNonEmpty(alert.Annotations["summary"], alert.Annotations["title"], alert.Labels["alertname"])
+		status := strings.ToUpper(alert.Labels["severity"])
+		switch status {
+		case "":
+			status = "WARNING"
+		case "WARN":
+			status = "WARNING"
+		}
+		url := firstNonEmpty(alert.GeneratorURL, payload.ExternalURL)
+		expiresAt := now.Add(ttl)
+		if alert.EndsAt.After(now) {
+			expiresAt = alert.EndsAt
+		}
+
+		alarms = append(alarms, IngestedAlarm{
+			ID: id,
+			Alarm: Alarm{
+				Time:            alert.StartsAt.Local(),
+				Summary:         summary,
+				URL:             url,
+				Status:          status,
+				Value:           alert.Labels["instance"],
+				Property:        firstNonEmpty(alert.Annotations["description"], alert.Annotations["message"]),
+				Source:          source,
+				BackgroundColor: "black",
+			},
+			ReceivedAt: now,
+			ExpiresAt:  expiresAt,
+		})
+	}
+
+	return alarms, resolved, nil
+}
+
+// ingestID returns the default ID of a generic alarm
+func ingestID(source, summary string) string {
+	hash := sha256.Sum256([]byte(source + "\x00" + summary))
+	return hex.EncodeToString(hash[:8])
+}
+
+// labelsFingerprint returns an ID of an Alertmanager alert without fingerprint, like the ones of old versions
+func labelsFingerprint(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	for _, name := range names {
+		hash.Write([]byte(name + "\x00" + labels[name] + "\x00"))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)[:8])
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// ingest stores the alarms received by the ingest route, and records them in the
+// history and sends the notifications without waiting for the next poll
+func ingest(alarms []IngestedAlarm, resolved []string) error {
+	store, err := getIngestStore()
+	if err != nil {
+		return err
+	}
+	if err := store.UpdateIngested(alarms, resolved); err != nil {
+		return err
+	}
+
+	current, err := webhookAlarms("", nil)
+	if err != nil {
+		return err
+	}
+	recordAlarms(webhookName, "", current, false)
+
+	return nil
+}
+
+// Ingested returns the alarms received by the ingest route that didn't expire, sorted by ID
+func (h *History) Ingested() ([]IngestedAlarm, error) {
+	now := h.now()
+	var alarms []IngestedAlarm
+	err := h.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(historyIngestedBucket).ForEach(func(_, value []byte) error {
+			var alarm IngestedAlarm
+			if err := json.Unmarshal(value, &alarm); err != nil {
+				return fmt.Errorf("error unmarshaling ingested alarm: %w", err)
+			}
+			if now.Before(alarm.ExpiresAt) {
+				alarms = append(alarms, alarm)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting ingested alarms: %w", err)
+	}
+
+	return alarms, nil
+}
+
+// UpdateIngested adds or replaces the alarms received by the ingest route, and removes the resolved ones by ID
+func (h *History) UpdateIngested(alarms []IngestedAlarm, resolved []string) error {
+	err := h.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(historyIngestedBucket)
+		for _, alarm := range alarms {
+			value, err := json.Marshal(alarm)
+			if err != nil {
+				return fmt.Errorf("error marshaling ingested alarm: %w", err)
+			}
+			if err := bucket.Put([]byte(alarm.ID), value); err != nil {
+				return err
+			}
+		}
+		for _, id := range resolved {
+			if err := bucket.Delete([]byte(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error saving ingested alarms: %w", err)
+	}
+
+	return nil
+}
+
+// pruneIngested removes the expired alarms received by the ingest route. It's called by Prune, in the same transaction.
+func pruneIngested(tx *bolt.Tx, now time.Time) error {
+	bucket := tx.Bucket(historyIngestedBucket)
+	var keys [][]byte
+	err := bucket.ForEach(func(key, value []byte) error {
+		var alarm IngestedAlarm
+		if err := json.Unmarshal(value, &alarm); err != nil {
+			return fmt.Errorf("error unmarshaling ingested alarm: %w", err)
+		}
+		if !now.Before(alarm.ExpiresAt) {
+			keys = append(keys, bytes.Clone(key))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := bucket.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type memoryIngestStore struct {
+	mu     sync.Mutex
+	alarms map[string]IngestedAlarm
+	now    func() time.Time
+}
+
+func newMemoryIngestStore() *memoryIngestStore {
+	return &memoryIngestStore{alarms: map[string]IngestedAlarm{}, now: time.Now}
+}
+
+func (s *memoryIngestStore) Ingested() ([]IngestedAlarm, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	alarms := make([]IngestedAlarm, 0, len(s.alarms))
+	for id, alarm := range s.alarms {
+		if !now.Before(alarm.ExpiresAt) {
+			delete(s.alarms, id)
+			continue
+		}
+		alarms = append(alarms, alarm)
+	}
+	sort.Slice(alarms, func(i, j int) bool {
+		return alarms[i].ID < alarms[j].ID
+	})
+
+	return alarms, nil
+}
+
+func (s *memoryIngestStore) UpdateIngested(alarms []IngestedAlarm, resolved []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, alarm := range alarms {
+		s.alarms[alarm.ID] = alarm
+	}
+	for _, id := range resolved {
+		delete(s.alarms, id)
+	}
+
+	return nil
+}
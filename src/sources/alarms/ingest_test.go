@@ -0,0 +1,120 @@
+package alarms
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+func TestParseIngestPayload(t *testing.T) {
+	defer config.Set(config.Current())
+	configs := &config.Configs{}
+	configs.IFrames.AlarmsIngestTTL = time.Hour
+	config.Set(configs)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	alertmanager := `{
+		"externalURL": "http://alertmanager:9093",
+		"alerts": [
+			{
+				"status": "firing",
+				"labels": {"alertname": "DiskFull", "severity": "critical", "instance": "nas:9100"},
+				"annotations": {"summary": "Disk almost full", "description": "/ is 95% full"},
+				"startsAt": "2024-12-31T23:00:00Z",
+				"endsAt": "0001-01-01T00:00:00Z",
+				"generatorURL": "http://prometheus:9090/graph",
+				"fingerprint": "a1b2c3"
+			},
+			{
+				"status": "resolved",
+				"labels": {"alertname": "HighLoad"},
+				"fingerprint": "d4e5f6"
+			}
+		]
+	}`
+	alarms, resolved, err := parseIngestPayload([]byte(alertmanager), "", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alarms) != 1 || len(resolved) != 1 || resolved[0] != "alertmanager:d4e5f6" {
+		t.Fatalf("expected a firing and a resolved alert, got %v and %v", alarms, resolved)
+	}
+	alarm := alarms[0]
+	if alarm.ID != "alertmanager:a1b2c3" || alarm.Alarm.Summary != "Disk almost full" || alarm.Alarm.Status != "CRITICAL" ||
+		alarm.Alarm.Source != "Alertmanager" || alarm.Alarm.Value != "nas:9100" || alarm.Alarm.URL != "http://prometheus:9090/graph" {
+		t.Errorf("unexpected Alertmanager alarm: %+v", alarm)
+	}
+	if !alarm.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("expected the alarm to expire after the TTL, got %s", alarm.ExpiresAt)
+	}
+
+	alarms, resolved, err = parseIngestPayload([]byte(`[
+		{"summary": "Backup failed", "status": "error", "ttl": "10m"},
+		{"id": "job-2", "resolved": true}
+	]`), "Cron", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alarms) != 1 || len(resolved) != 1 || resolved[0] != "job-2" {
+		t.Fatalf("expected an alarm and a resolved alarm, got %v and %v", alarms, resolved)
+	}
+	if alarm = alarms[0]; alarm.Alarm.Status != "ERROR" || alarm.Alarm.Source != "Cron" || !alarm.ExpiresAt.Equal(now.Add(10*time.Minute)) {
+		t.Errorf("unexpected generic alarm: %+v", alarm)
+	}
+
+	alarms, _, err = parseIngestPayload([]byte(`{"summary": "Certificate expires soon"}`), "", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alarms) != 1 || alarms[0].Alarm.Status != "WARNING" || alarms[0].Alarm.Source != "Webhook" {
+		t.Errorf("expected a WARNING alarm from Webhook, got %v", alarms)
+	}
+
+	for _, body := range []string{``, `{"status": "ERROR"}`, `[{"summary": "Backup failed", "ttl": "-1h"}]`, `"alarm"`} {
+		if _, _, err := parseIngestPayload([]byte(body), "", now); err == nil {
+			t.Errorf("expected an error parsing %q", body)
+		}
+	}
+}
+
+func TestIngested(t *testing.T) {
+	history, err := OpenHistory(filepath.Join(t.TempDir(), "alarms.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer history.Close()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	history.now = func() time.Time { return now }
+
+	memory := newMemoryIngestStore()
+	memory.now = history.now
+
+	for _, store := range []ingestStore{history, memory} {
+		err := store.UpdateIngested([]IngestedAlarm{
+			{ID: "backup", Alarm: Alarm{Summary: "Backup failed"}, ReceivedAt: now, ExpiresAt: now.Add(time.Hour)},
+			{ID: "disk", Alarm: Alarm{Summary: "Disk almost full"}, ReceivedAt: now, ExpiresAt: now.Add(-time.Minute)},
+			{ID: "load", Alarm: Alarm{Summary: "High load"}, ReceivedAt: now, ExpiresAt: now.Add(time.Hour)},
+		}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.UpdateIngested(nil, []string{"load"}); err != nil {
+			t.Fatal(err)
+		}
+		if store == history {
+			if err := history.Prune(time.Hour); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		alarms, err := store.Ingested()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(alarms) != 1 || alarms[0].ID != "backup" {
+			t.Errorf("%T: expected only the backup alarm, as the disk alarm expired and the load alarm was resolved, got %v", store, alarms)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package alarms
+
+import (
+	"slices"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+// maintenanceWindow returns the active maintenance window of an alarm of an integration instance.
+// If the alarm is in many windows, the one with the strongest action is returned, like "hide" over "mark".
+func maintenanceWindow(integration, instance string, alarm Alarm, now time.Time) (config.MaintenanceWindow, bool) {
+	var window config.MaintenanceWindow
+	found := false
+	for _, w := range config.Current().Maintenance {
+		if !w.Active(now) || !w.Matches(integration, instance, alarmField(alarm)) {
+			continue
+		}
+		if !found || slices.Index(config.MaintenanceActions, w.Action) < slices.Index(config.MaintenanceActions, window.Action) {
+			window, found = w, true
+		}
+	}
+
+	return window, found
+}
+
+// applyMaintenance removes the alarms of an integration instance in a maintenance window with the "hide" action,
+// and sets the maintenance window of the ones with the "dim" and "mark" actions
+func applyMaintenance(integration, instance string, alarms []Alarm, now time.Time) []Alarm {
+	if len(config.Current().Maintenance) == 0 {
+		return alarms
+	}
+
+	var result []Alarm
+	for _, alarm := range alarms {
+		window, ok := maintenanceWindow(integration, instance, alarm, now)
+		if ok && window.Action == "hide" {
+			continue
+		}
+		if ok {
+			alarm.Maintenance, alarm.MaintenanceAction = window.Name, window.Action
+		}
+		result = append(result, alarm)
+	}
+
+	return result
+}
+
+// inMaintenance returns true if an alarm of an integration instance is in a maintenance window at a time
+func inMaintenance(integration, instance string, alarm Alarm, at time.Time) bool {
+	_, ok := maintenanceWindow(integration, instance, alarm, at)
+	return ok
+}
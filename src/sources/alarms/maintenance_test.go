@@ -0,0 +1,74 @@
+package alarms
+
+import (
+	"context"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/notify"
+)
+
+func TestMaintenance(t *testing.T) {
+	defer config.Set(config.Current())
+	t.Setenv("ALARMS_MAINTENANCE_BACKUPS_SCHEDULE", "00:00-24:00")
+	t.Setenv("ALARMS_MAINTENANCE_BACKUPS_SOURCES", "backrest")
+	t.Setenv("ALARMS_MAINTENANCE_BACKUPS_ACTION", "dim")
+	t.Setenv("ALARMS_MAINTENANCE_DISK_SCHEDULE", "00:00-24:00")
+	t.Setenv("ALARMS_MAINTENANCE_DISK_FILTER", `summary~"(?i)disk"`)
+	t.Setenv("ALARMS_MAINTENANCE_DISK_ACTION", "hide")
+	t.Setenv("ALARMS_MAINTENANCE_NETDATA_SCHEDULE", "00:00-24:00")
+	t.Setenv("ALARMS_MAINTENANCE_NETDATA_SOURCES", "netdata")
+	if err := config.SetConfigs(""); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	backrest := applyMaintenance("backrest", "", []Alarm{
+		{Source: "Backrest", Summary: "Backup running", Status: "WARNING"},
+		{Source: "Backrest", Summary: "Disk full", Status: "ERROR"},
+	}, now)
+	if len(backrest) != 1 || backrest[0].Maintenance != "backups" || backrest[0].MaintenanceAction != "dim" {
+		t.Errorf("expected the backup alarm to be dimmed and the disk alarm to be hidden, got %v", backrest)
+	}
+	netdata := applyMaintenance("netdata", "", []Alarm{{Source: "Netdata", Summary: "High CPU", Status: "CRITICAL"}}, now)
+	if len(netdata) != 1 || netdata[0].Maintenance != "netdata" || netdata[0].MaintenanceAction != "mark" {
+		t.Errorf("expected the Netdata alarm to be marked, got %v", netdata)
+	}
+	radarr := applyMaintenance("radarr", "4k", []Alarm{{Source: "Radarr", Summary: "Indexers unavailable", Status: "WARNING"}}, now)
+	if len(radarr) != 1 || radarr[0].Maintenance != "" {
+		t.Errorf("expected the Radarr alarm to not be in maintenance, got %v", radarr)
+	}
+
+	configs := config.Current()
+	configs.Notifiers = []config.NotifierConfigs{{
+		Name:    "phone",
+		Events:  []string{"fired", "resolved"},
+		Title:   template.Must(template.New("title").Parse("{{ .Event }}: {{ .Alarm.Summary }}")),
+		Message: template.Must(template.New("message").Parse("{{ .Alarm.Source }}")),
+	}}
+	messages := make(chan notify.Message, 10)
+	defer func() { sendNotification = notify.Send }()
+	sendNotification = func(_ context.Context, _ config.NotifierConfigs, message notify.Message) error {
+		messages <- message
+		return nil
+	}
+
+	resolvedAt := now
+	notifyAlarms("netdata", "", []HistoryEntry{{Fingerprint: "cpu-maintenance", Alarm: netdata[0], FirstSeen: now}}, nil)
+	notifyAlarms("radarr", "4k", nil, []HistoryEntry{{Fingerprint: "indexers-maintenance", Alarm: radarr[0], FirstSeen: now, ResolvedAt: &resolvedAt}})
+	select {
+	case message := <-messages:
+		if message.Title != "resolved: Indexers unavailable" {
+			t.Errorf("expected only the Radarr alarm to be notified, got %+v", message)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a notification")
+	}
+	select {
+	case message := <-messages:
+		t.Errorf("expected the Netdata alarm in maintenance to not be notified, got %+v", message)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
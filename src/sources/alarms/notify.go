@@ -0,0 +1,224 @@
+package alarms
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/notify"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// notifyTimeout is how long a notifier can take to send a notification
+const notifyTimeout = 30 * time.Second
+
+var (
+	// memoryTracker finds the new and resolved alarms when the history is disabled
+	memoryTracker = newAlarmTracker()
+	// throttledUntil has when each alarm event can be sent again to each notifier
+	throttledUntil = map[string]time.Time{}
+	throttledMu    sync.Mutex
+	// sendNotification is replaced in tests
+	sendNotification = notify.Send
+)
+
+// notificationData is the data of the notifiers title and message templates
+type notificationData struct {
+	// Event is "fired" or "resolved"
+	Event string
+	// Integration is the name of the integration that returned the alarm, like "radarr"
+	Integration string
+	Alarm       Alarm
+	FirstSeen   time.Time
+	ResolvedAt  *time.Time
+	// Duration is how long the alarm was active, like "3h 5m", only in the resolved event
+	Duration string
+}
+
+// notifyAlarms sends the fired and resolved alarms to the notifiers that route them, in the background
+func notifyAlarms(integration, instance string, fired, resolved []HistoryEntry) {
+	notifiers := config.Current().Notifiers
+	if len(notifiers) == 0 || len(fired)+len(resolved) == 0 {
+		return
+	}
+
+	now := time.Now()
+	fired = slices.DeleteFunc(slices.Clone(fired), func(entry HistoryEntry) bool {
+		return suppressedByMaintenance(integration, instance, "fired", entry, now)
+	})
+	resolved = slices.DeleteFunc(slices.Clone(resolved), func(entry HistoryEntry) bool {
+		// The alarms that fired in a maintenance window were not notified, so their resolution isn't too
+		return suppressedByMaintenance(integration, instance, "resolved", entry, now) ||
+			suppressedByMaintenance(integration, instance, "resolved", entry, entry.FirstSeen)
+	})
+
+	for _, notifier := range notifiers {
+		if !notifier.Routes(integration, instance) {
+			continue
+		}
+		for _, event := range []string{"fired", "resolved"} {
+			if !slices.Contains(notifier.Events, event) {
+				continue
+			}
+			entries := fired
+			if event == "resolved" {
+				entries = resolved
+			}
+			for _, entry := range entries {
+				if throttled(notifier, event, entry.Fingerprint, now) {
+					slog.Debug("alarm notification throttled", "notifier", notifier.Name, "event", event, "summary", entry.Alarm.Summary)
+					continue
+				}
+				message, err := notificationMessage(notifier, event, entry)
+				if err != nil {
+					slog.Error("error creating alarm notification", "notifier", notifier.Name, "error", err)
+					continue
+				}
+				go func() {
+					ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+					defer cancel()
+					if err := sendNotification(ctx, notifier, message); err != nil {
+						slog.Error("error sending alarm notification", "notifier", notifier.Name, "event", event, "summary", entry.Alarm.Summary, "error", err)
+					}
+				}()
+			}
+		}
+	}
+}
+
+// suppressedByMaintenance returns true if the alarm of an event was in a maintenance window at a time, so it's not notified
+func suppressedByMaintenance(integration, instance, event string, entry HistoryEntry, at time.Time) bool {
+	if !inMaintenance(integration, instance, entry.Alarm, at) {
+		return false
+	}
+	slog.Debug("alarm notification suppressed by a maintenance window", "event", event, "summary", entry.Alarm.Summary)
+
+	return true
+}
+
+// throttled returns true if the event of an alarm was sent to a notifier less than its
+// throttle ago. Otherwise, it records that the event is being sent now.
+func throttled(notifier config.NotifierConfigs, event, fingerprint string, now time.Time) bool {
+	key := notifier.Name + "/" + event + "/" + fingerprint
+
+	throttledMu.Lock()
+	defer throttledMu.Unlock()
+	if until, ok := throttledUntil[key]; ok && now.Before(until) {
+		return true
+	}
+	// Removes the expired keys, so the map doesn't grow forever
+	for key, until := range throttledUntil {
+		if !now.Before(until) {
+			delete(throttledUntil, key)
+		}
+	}
+	if notifier.Throttle > 0 {
+		throttledUntil[key] = now.Add(notifier.Throttle)
+	}
+
+	return false
+}
+
+// notificationMessage returns the message of an alarm event using the notifier templates
+func notificationMessage(notifier config.NotifierConfigs, event string, entry HistoryEntry) (notify.Message, error) {
+	data := notificationData{
+		Event:       event,
+		Integration: entry.Integration,
+		Alarm:       entry.Alarm,
+		FirstSeen:   entry.FirstSeen,
+		ResolvedAt:  entry.ResolvedAt,
+	}
+	if entry.ResolvedAt != nil {
+		data.Duration = formatDuration(entry.Duration(*entry.ResolvedAt))
+	}
+
+	var title, body strings.Builder
+	if err := notifier.Title.Execute(&title, data); err != nil {
+		return notify.Message{}, fmt.Errorf("error executing title template: %w", err)
+	}
+	if err := notifier.Message.Execute(&body, data); err != nil {
+		return notify.Message{}, fmt.Errorf("error executing message template: %w", err)
+	}
+
+	return notify.Message{Title: title.String(), Body: body.String(), Level: notificationLevel(event, entry.Alarm.NormalizedSeverity())}, nil
+}
+
+func notificationLevel(event string, severity sources.Severity) notify.Level {
+	if event == "resolved" {
+		return notify.LevelSuccess
+	}
+	switch {
+	case severity >= sources.SeverityError:
+		return notify.LevelFailure
+	case severity == sources.SeverityWarning:
+		return notify.LevelWarning
+	default:
+		return notify.LevelInfo
+	}
+}
+
+// alarmTracker finds the new and resolved alarms in memory, like the History does in the file.
+// The first alarms of each integration instance are not new, so they are not sent again when the API restarts.
+type alarmTracker struct {
+	mu sync.Mutex
+	// active are the active alarms by fingerprint
+	active map[string]HistoryEntry
+	// seen are the integration instances already recorded
+	seen map[string]bool
+	now  func() time.Time
+}
+
+func newAlarmTracker() *alarmTracker {
+	return &alarmTracker{active: map[string]HistoryEntry{}, seen: map[string]bool{}, now: time.Now}
+}
+
+// Record records the alarms returned by an integration instance and returns the ones that fired and were resolved,
+// with the same rules as History.Record
+func (t *alarmTracker) Record(integration, instance string, alarms []Alarm, failed bool) (fired, resolved []HistoryEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	instanceKey := integration + ":" + instance
+	firstRecord := !t.seen[instanceKey]
+	t.seen[instanceKey] = true
+
+	seen := map[string]bool{}
+	for _, alarm := range alarms {
+		if alarm.Status == "CLEAR" {
+			continue
+		}
+		fingerprint := Fingerprint(alarm)
+		seen[fingerprint] = true
+
+		entry, ok := t.active[fingerprint]
+		if !ok {
+			entry = HistoryEntry{Fingerprint: fingerprint, Integration: integration, FirstSeen: now}
+		}
+		entry.Alarm, entry.LastSeen = alarm, now
+		t.active[fingerprint] = entry
+		if !ok && !firstRecord {
+			fired = append(fired, entry)
+		}
+	}
+	if failed {
+		return fired, nil
+	}
+
+	for fingerprint, entry := range t.active {
+		if seen[fingerprint] || entry.Integration != integration || entry.Alarm.Instance != instance {
+			continue
+		}
+		resolvedAt := now
+		entry.ResolvedAt = &resolvedAt
+		delete(t.active, fingerprint)
+		resolved = append(resolved, entry)
+	}
+
+	return fired, resolved
+}
@@ -0,0 +1,207 @@
+package alarms
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/metrics"
+	"github.com/diogovalentte/homarr-iframes/src/notify"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+func TestAlarmTracker(t *testing.T) {
+	tracker := newAlarmTracker()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	diskAlarm := Alarm{Source: "Netdata", Summary: "Low disk space", Status: "WARNING"}
+	cpuAlarm := Alarm{Source: "Netdata", Summary: "High CPU", Status: "CRITICAL"}
+
+	// The first alarms are not new, as they could have been sent before a restart
+	if fired, resolved := tracker.Record("netdata", "", []Alarm{diskAlarm}, false); len(fired) != 0 || len(resolved) != 0 {
+		t.Fatalf("expected no fired or resolved alarms in the first record, got %v and %v", fired, resolved)
+	}
+	if fired, _ := tracker.Record("netdata", "", []Alarm{diskAlarm, cpuAlarm}, false); len(fired) != 1 || fired[0].Alarm.Summary != "High CPU" {
+		t.Fatalf("expected the CPU alarm to fire, got %v", fired)
+	}
+	if _, resolved := tracker.Record("netdata", "", nil, true); len(resolved) != 0 {
+		t.Fatalf("a failed integration should not resolve its alarms, got %v", resolved)
+	}
+
+	now = now.Add(time.Hour)
+	_, resolved := tracker.Record("netdata", "", []Alarm{{Source: "Netdata", Summary: "High CPU", Status: "CLEAR"}}, false)
+	if len(resolved) != 2 || resolved[0].ResolvedAt == nil || resolved[0].Duration(now) != time.Hour {
+		t.Fatalf("expected the two alarms to be resolved after 1h, got %v", resolved)
+	}
+}
+
+func TestNotifyAlarms(t *testing.T) {
+	defer config.Set(config.Current())
+	config.Set(&config.Configs{Notifiers: []config.NotifierConfigs{
+		{
+			Name:     "phone",
+			Sources:  []string{"netdata"},
+			Events:   []string{"fired", "resolved"},
+			Title:    template.Must(template.New("title").Parse("{{ .Event }}: {{ .Alarm.Summary }}")),
+			Message:  template.Must(template.New("message").Parse("{{ .Alarm.Source }}{{ with .Duration }} for {{ . }}{{ end }}")),
+			Throttle: time.Hour,
+		},
+		{
+			Name:    "radarr",
+			Sources: []string{"radarr:4k"},
+			Events:  []string{"fired"},
+			Title:   template.Must(template.New("title").Parse("{{ .Alarm.Summary }}")),
+			Message: template.Must(template.New("message").Parse("{{ .Alarm.Source }}")),
+		},
+	}})
+	messages := make(chan notify.Message, 10)
+	defer func() { sendNotification = notify.Send }()
+	sendNotification = func(_ context.Context, notifier config.NotifierConfigs, message notify.Message) error {
+		if notifier.Name != "phone" {
+			t.Errorf("the netdata alarms should not be sent to the %s notifier", notifier.Name)
+		}
+		messages <- message
+		return nil
+	}
+	receive := func() notify.Message {
+		select {
+		case message := <-messages:
+			return message
+		case <-time.After(time.Second):
+			t.Fatal("expected a notification")
+			return notify.Message{}
+		}
+	}
+
+	firstSeen := time.Now().Add(-time.Hour)
+	entry := HistoryEntry{Fingerprint: "cpu", Integration: "netdata", Alarm: Alarm{Source: "Netdata", Summary: "High CPU", Status: "CRITICAL"}, FirstSeen: firstSeen}
+	notifyAlarms("netdata", "", []HistoryEntry{entry}, nil)
+	if message := receive(); message.Title != "fired: High CPU" || message.Body != "Netdata" || message.Level != notify.LevelFailure {
+		t.Errorf("unexpected fired notification: %+v", message)
+	}
+
+	resolvedAt := firstSeen.Add(2 * time.Hour)
+	entry.ResolvedAt = &resolvedAt
+	notifyAlarms("netdata", "", nil, []HistoryEntry{entry})
+	if message := receive(); message.Title != "resolved: High CPU" || message.Body != "Netdata for 2h 0m" || message.Level != notify.LevelSuccess {
+		t.Errorf("unexpected resolved notification: %+v", message)
+	}
+
+	// The alarm flaps, but the events were sent less than the throttle ago
+	entry.ResolvedAt = nil
+	notifyAlarms("netdata", "", []HistoryEntry{entry}, nil)
+	select {
+	case message := <-messages:
+		t.Errorf("expected the notification to be throttled, got %+v", message)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestGetIntegrationAlarmsRecord(t *testing.T) {
+	defer config.Set(config.Current())
+	config.Set(&config.Configs{})
+	defer func(tracker *alarmTracker) { memoryTracker = tracker }(memoryTracker)
+	memoryTracker = newAlarmTracker()
+
+	// Like Changedetection.io, the alarms returned depend on the query parameters
+	var err error
+	integration := &sources.Integration{Name: "changes", Title: "Changes", Alarms: func(_ string, params url.Values) ([]Alarm, error) {
+		alarms := []Alarm{{Source: "Changes", Summary: "Page changed", Status: "CHANGED"}}
+		if params.Get("show_viewed") == "true" {
+			alarms = append(alarms, Alarm{Source: "Changes", Summary: "Page viewed", Status: "CHANGED"})
+		}
+		return alarms, err
+	}}
+	// SetAlarms replaces the gauges, so they're got again every time
+	changedAlarms := func() float64 { return testutil.ToFloat64(metrics.Alarms.WithLabelValues("changes", "", "CHANGED")) }
+
+	getIntegrationAlarms(integration, "", url.Values{}, time.Second, true)
+	if len(memoryTracker.active) != 1 {
+		t.Fatalf("expected the polled alarm to be recorded, got %v", memoryTracker.active)
+	}
+	if count := changedAlarms(); count != 1 {
+		t.Errorf("expected the polled alarm in the metrics, got %v", count)
+	}
+
+	alarms := getIntegrationAlarms(integration, "", url.Values{"show_viewed": {"true"}}, time.Second, false)
+	if len(alarms) != 2 {
+		t.Fatalf("expected the viewed alarm to be returned, got %v", alarms)
+	}
+	if len(memoryTracker.active) != 1 {
+		t.Errorf("the alarms of a request should not be recorded, got %v", memoryTracker.active)
+	}
+	if count := changedAlarms(); count != 1 {
+		t.Errorf("the alarms of a request should not be set in the metrics, got %v", count)
+	}
+
+	err = errors.New("connection refused")
+	getIntegrationAlarms(integration, "", url.Values{}, time.Second, true)
+	if up := testutil.ToFloat64(metrics.AlarmsSourceUp.WithLabelValues("changes", "")); up != 0 {
+		t.Errorf("expected the failed source to be down, got %v", up)
+	}
+	if count := testutil.ToFloat64(metrics.Alarms.WithLabelValues("changes", "", "ERROR")); count != 0 {
+		t.Errorf("the unreachable alarm should not be counted as an alarm, got %v", count)
+	}
+	if count := changedAlarms(); count != 1 {
+		t.Errorf("expected the alarms of the failed source to be kept, got %v", count)
+	}
+}
+
+func TestNotifyPolledAlarms(t *testing.T) {
+	defer config.Set(config.Current())
+	config.Set(&config.Configs{Notifiers: []config.NotifierConfigs{{
+		Name:    "phone",
+		Sources: []string{"changes"},
+		Events:  []string{"fired", "resolved"},
+		Title:   template.Must(template.New("title").Parse("{{ .Event }}: {{ .Alarm.Summary }}")),
+		Message: template.Must(template.New("message").Parse("{{ .Alarm.Source }}")),
+	}}})
+	defer func(tracker *alarmTracker) { memoryTracker = tracker }(memoryTracker)
+	memoryTracker = newAlarmTracker()
+	messages := make(chan notify.Message, 10)
+	defer func() { sendNotification = notify.Send }()
+	sendNotification = func(_ context.Context, _ config.NotifierConfigs, message notify.Message) error {
+		messages <- message
+		return nil
+	}
+
+	pageDown := false
+	integration := &sources.Integration{Name: "changes", Title: "Changes", Alarms: func(_ string, params url.Values) ([]Alarm, error) {
+		var alarms []Alarm
+		if params.Get("show_viewed") == "true" {
+			alarms = append(alarms, Alarm{Source: "Changes", Summary: "Viewed page changed", Status: "CHANGED"})
+		}
+		if pageDown {
+			alarms = append(alarms, Alarm{Source: "Changes", Summary: "Page down", Status: "ERROR"})
+		}
+		return alarms, nil
+	}}
+
+	getIntegrationAlarms(integration, "", url.Values{}, time.Second, true)
+	// An iFrame showing more alarms than the poll doesn't fire them, nor resolves them when it shows less
+	getIntegrationAlarms(integration, "", url.Values{"show_viewed": {"true"}}, time.Second, false)
+	getIntegrationAlarms(integration, "", url.Values{}, time.Second, false)
+	select {
+	case message := <-messages:
+		t.Fatalf("the alarms of a request should not be notified, got %+v", message)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	pageDown = true
+	getIntegrationAlarms(integration, "", url.Values{}, time.Second, true)
+	select {
+	case message := <-messages:
+		if message.Title != "fired: Page down" {
+			t.Errorf("unexpected notification: %+v", message)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the polled alarm to be notified")
+	}
+}
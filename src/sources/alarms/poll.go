@@ -0,0 +1,95 @@
+package alarms
+
+import (
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// historyPruneInterval is how often the resolved alarms older than ALARMS_HISTORY_DAYS are removed
+const historyPruneInterval = time.Hour
+
+// PollAlarms gets the alarms of every configured source every ALARMS_POLL_INTERVAL, so the history
+// is updated and the notifications are sent even when no alarms iFrame is open. It only polls if the
+// history is enabled or there are notifiers, and it blocks, so it should be called in a goroutine.
+func PollAlarms() {
+	var lastPrune time.Time
+	for {
+		iframesConfigs := config.Current().IFrames
+		interval := iframesConfigs.AlarmsPollInterval
+		if interval <= 0 || (iframesConfigs.AlarmsHistoryFile == "" && len(config.Current().Notifiers) == 0) {
+			// Checks again later, as the configs can be reloaded
+			time.Sleep(time.Minute)
+			continue
+		}
+
+		pollAlarms()
+		if time.Since(lastPrune) >= historyPruneInterval {
+			pruneHistory()
+			lastPrune = time.Now()
+		}
+		time.Sleep(interval)
+	}
+}
+
+// pollAlarms gets the alarms of every configured source instance without query parameters, records them
+// in the history, and sends the notifications. It's the only place where the alarms are recorded, so the
+// history and notifications don't depend on the parameters of the iFrame and feed requests.
+func pollAlarms() {
+	var alarmNames []string
+	for _, name := range sources.AlarmProviders() {
+		// The webhook alarms are polled too, so the expired ones are resolved
+		if name == webhookName {
+			alarmNames = append(alarmNames, name)
+			continue
+		}
+		for _, instance := range sources.ConfiguredInstances(name) {
+			alarmName := name
+			if instance != "" {
+				alarmName += ":" + instance
+			}
+			alarmNames = append(alarmNames, alarmName)
+		}
+	}
+	if len(alarmNames) == 0 {
+		return
+	}
+
+	if _, err := getAlarms(alarmNames, url.Values{}, config.Current().IFrames.AlarmsSourceTimeout, true); err != nil {
+		slog.Error("error polling alarms", "error", err)
+	}
+}
+
+// recordAlarms records the alarms of an integration instance in the history, if it's enabled,
+// and sends the alarms that fired and were resolved to the notifiers
+func recordAlarms(integration, instance string, alarms []Alarm, failed bool) {
+	history, err := getHistory()
+	if err != nil {
+		slog.Error("error opening alarms history", "error", err)
+	}
+	if history == nil {
+		fired, resolved := memoryTracker.Record(integration, instance, alarms, failed)
+		notifyAlarms(integration, instance, fired, resolved)
+		return
+	}
+
+	fired, resolved, err := history.Record(integration, instance, alarms, failed)
+	if err != nil {
+		slog.Error("error recording alarms history", "source", integration, "instance", instance, "error", err)
+		return
+	}
+	notifyAlarms(integration, instance, fired, resolved)
+}
+
+func pruneHistory() {
+	history, err := getHistory()
+	if err != nil || history == nil {
+		return
+	}
+	if err := history.Prune(config.Current().IFrames.AlarmsHistoryRetention); err != nil {
+		slog.Error("error pruning alarms history", "error", err)
+	}
+}
@@ -0,0 +1,385 @@
+package alarms
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// getStatusColor returns the color of the status label of an alarm
+func getStatusColor(status string) string {
+	switch status {
+	case "CLEAR":
+		return "green"
+	case "WARNING", "CHANGED":
+		return "orange"
+	case "ERROR", "CRITICAL", "FAILED":
+		return "red"
+	default:
+		return "gray"
+	}
+}
+
+// severityStatuses are the statuses whose colors are used for each severity by the summary and badge
+var severityStatuses = map[sources.Severity]string{
+	sources.SeverityOK:       "CLEAR",
+	sources.SeverityWarning:  "WARNING",
+	sources.SeverityError:    "ERROR",
+	sources.SeverityCritical: "CRITICAL",
+}
+
+// severityColor returns the color of a severity in the summary and badge, the same as the status labels of the alarm cards
+func severityColor(severity sources.Severity) string {
+	return getStatusColor(severityStatuses[severity])
+}
+
+// alarmsSummary is the number of alarms of each source by severity
+type alarmsSummary struct {
+	Total   int
+	Highest sources.Severity
+	// Sources are sorted by their highest severity, then by name
+	Sources []sourceSummary
+}
+
+// AllClear returns true if there are no alarms, or all of them are OK
+func (s alarmsSummary) AllClear() bool {
+	return s.Highest <= sources.SeverityOK
+}
+
+// sourceSummary is the number of alarms of a source instance by severity
+type sourceSummary struct {
+	// Name is the source with the instance, like "Radarr (4k)"
+	Name    string
+	Highest sources.Severity
+	// Counts are sorted by severity, the highest first
+	Counts []severityCount
+}
+
+type severityCount struct {
+	Severity sources.Severity
+	Count    int
+}
+
+// summarizeAlarms counts the alarms of each source by severity
+func summarizeAlarms(alarms []trackedAlarm) alarmsSummary {
+	summary := alarmsSummary{Total: len(alarms)}
+	counts := map[string]map[sources.Severity]int{}
+	for _, alarm := range alarms {
+		name := alarm.Source
+		if alarm.Instance != "" {
+			name += " (" + alarm.Instance + ")"
+		}
+		if counts[name] == nil {
+			counts[name] = map[sources.Severity]int{}
+		}
+		severity := alarm.NormalizedSeverity()
+		counts[name][severity]++
+		summary.Highest = max(summary.Highest, severity)
+	}
+
+	for name, severities := range counts {
+		source := sourceSummary{Name: name}
+		for severity, count := range severities {
+			source.Counts = append(source.Counts, severityCount{Severity: severity, Count: count})
+			source.Highest = max(source.Highest, severity)
+		}
+		sort.Slice(source.Counts, func(i, j int) bool { return source.Counts[i].Severity > source.Counts[j].Severity })
+		summary.Sources = append(summary.Sources, source)
+	}
+	sort.Slice(summary.Sources, func(i, j int) bool {
+		if summary.Sources[i].Highest != summary.Sources[j].Highest {
+			return summary.Sources[i].Highest > summary.Sources[j].Highest
+		}
+		return summary.Sources[i].Name < summary.Sources[j].Name
+	})
+
+	return summary
+}
+
+// summaryLink returns the URL of the full alarms iFrame with the same query parameters as the summary
+func summaryLink(query url.Values, apiURL string) string {
+	query = cloneValues(query)
+	query.Del("layout")
+	query.Del("link")
+	if apiURL == "" {
+		// Relative to the summary, which is served by the same route
+		return "alarms?" + query.Encode()
+	}
+
+	return apiURL + "/v1/iframe/alarms?" + query.Encode()
+}
+
+func cloneValues(values url.Values) url.Values {
+	clone := url.Values{}
+	for key, value := range values {
+		clone[key] = append([]string(nil), value...)
+	}
+
+	return clone
+}
+
+func getSummaryiFrame(summary alarmsSummary, theme, apiURL, link string) ([]byte, error) {
+	html := `
+<!doctype html>
+<html lang="en">
+  <head>
+    <meta charset="UTF-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1.0" />
+    <meta name="referrer" content="no-referrer">
+    <meta name="color-scheme" content="{{ .Theme }}">
+    <script src="https://kit.fontawesome.com/3f763b063a.js" crossorigin="anonymous"></script>
+    <title>Alarms Summary iFrame</title>
+    <style>
+      ::-webkit-scrollbar {
+        width: 7px;
+      }
+
+      ::-webkit-scrollbar-thumb {
+        background-color: {{ .ScrollbarThumbBackgroundColor }};
+        border-radius: 2.3px;
+      }
+
+      ::-webkit-scrollbar-track {
+        background-color: transparent;
+      }
+
+      ::-webkit-scrollbar-track:hover {
+        background-color: {{ .ScrollbarTrackBackgroundColor }};
+      }
+    </style>
+    <style>
+        body {
+            background: transparent !important;
+            margin: 0;
+            padding: 0;
+            font-family: ui-sans-serif, system-ui, -apple-system, BtaskMacSystemFont,
+              Segoe UI, Roboto, Helvetica Neue, Arial, Noto Sans, sans-serif, Apple Color Emoji,
+              Segoe UI Emoji, Segoe UI Symbol, Noto Color Emoji;
+        }
+
+        .summary-container {
+            display: block;
+            margin: 8.50px;
+            padding: 10px 14px;
+            border-radius: 10px;
+            border: 1px solid rgba(56, 58, 64, 1);
+            color: {{ .TextColor }};
+            text-decoration: none;
+        }
+
+        .summary-container:hover {
+            filter: brightness(1.1);
+        }
+
+        .summary-title {
+            display: flex;
+            align-items: center;
+            gap: 8px;
+            font-size: 15px;
+            font-weight: bold;
+        }
+
+        .summary-source {
+            display: flex;
+            align-items: center;
+            justify-content: space-between;
+            gap: 8px;
+            margin-top: 6px;
+            font-size: 0.8125rem;
+            font-weight: 600;
+        }
+
+        .summary-source-name {
+            overflow: hidden;
+            white-space: nowrap;
+            text-overflow: ellipsis;
+            color: {{ .SecondaryTextColor }};
+        }
+
+        .summary-counts {
+            display: flex;
+            gap: 4px;
+        }
+
+        .summary-count {
+            color: white;
+            font-size: 0.6875rem;
+            font-weight: 700;
+            line-height: 1.125rem;
+            padding: 0 0.5rem;
+            border-radius: 1rem;
+        }
+    </style>
+
+    {{ .LiveUpdates }}
+</head>
+<body>
+    <a class="summary-container" href="{{ .Link }}" target="_blank" title="Show the alarms">
+        {{ if .Summary.AllClear }}
+            <div class="summary-title"><i class="fa-solid fa-circle-check" style="color: {{ .AllClearColor }};"></i> All clear</div>
+        {{ else }}
+            <div class="summary-title"><i class="fa-solid fa-bell" style="color: {{ getSeverityColor .Summary.Highest }};"></i> {{ .Summary.Total }} alarm{{ if ne .Summary.Total 1 }}s{{ end }}</div>
+        {{ end }}
+        {{ range .Summary.Sources }}
+            <div class="summary-source">
+                <span class="summary-source-name" title="{{ .Name }}"><i class="fa-solid fa-cube"></i> {{ .Name }}</span>
+                <span class="summary-counts">
+                    {{ range .Counts }}<span class="summary-count" style="background-color: {{ getSeverityColor .Severity }};" title="{{ .Count }} {{ .Severity }}">{{ .Count }}</span>{{ end }}
+                </span>
+            </div>
+        {{ end }}
+    </a>
+</body>
+</html>
+	`
+	// Homarr theme
+	scrollbarThumbBackgroundColor := "#d1dbe3"
+	scrollbarTrackBackgroundColor := "#ffffff"
+	textColor := "#000000"
+	secondaryTextColor := "#5b6762"
+	if theme == "dark" {
+		scrollbarThumbBackgroundColor = "#484d64"
+		scrollbarTrackBackgroundColor = "rgba(37, 40, 53, 1)"
+		textColor = "white"
+		secondaryTextColor = "#99b6bb"
+	}
+
+	templateData := summaryTemplateData{
+		Summary:                       summary,
+		Link:                          link,
+		AllClearColor:                 severityColor(sources.SeverityOK),
+		Theme:                         theme,
+		LiveUpdates:                   sources.LiveUpdatesScript(apiURL, "alarms"),
+		TextColor:                     textColor,
+		SecondaryTextColor:            secondaryTextColor,
+		ScrollbarThumbBackgroundColor: scrollbarThumbBackgroundColor,
+		ScrollbarTrackBackgroundColor: scrollbarTrackBackgroundColor,
+	}
+
+	templateFuncs := template.FuncMap{
+		"getSeverityColor": severityColor,
+	}
+
+	tmpl := template.Must(template.New("summary").Funcs(templateFuncs).Parse(html))
+
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, &templateData)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+type summaryTemplateData struct {
+	Summary                       alarmsSummary
+	Link                          string
+	AllClearColor                 string
+	Theme                         string
+	LiveUpdates                   template.HTML
+	TextColor                     string
+	SecondaryTextColor            string
+	ScrollbarThumbBackgroundColor string
+	ScrollbarTrackBackgroundColor string
+}
+
+// GetBadge returns an SVG badge of the alarms
+func (a *Alarms) GetBadge(c *gin.Context) {
+	label := c.DefaultQuery("label", "alarms")
+	alarms, ok := a.getQueryData(c)
+	if !ok {
+		return
+	}
+
+	svg, err := getBadge(summarizeAlarms(alarms), label)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	// The badges embedded in other sites are cached by their image proxies if there is no Cache-Control header
+	c.Header("Cache-Control", "no-cache, max-age=0")
+	c.Data(http.StatusOK, "image/svg+xml", svg)
+}
+
+// badgeCharWidth is the approximate width of a character of the badge font, in pixels
+const badgeCharWidth = 7
+
+// getBadge returns an SVG badge with the label on the left and the alarm counts on the
+// right, like "alarms | 2 critical, 1 warning", colored with the highest severity
+func getBadge(summary alarmsSummary, label string) ([]byte, error) {
+	message := "all clear"
+	color := severityColor(sources.SeverityOK)
+	if !summary.AllClear() {
+		counts := map[sources.Severity]int{}
+		for _, source := range summary.Sources {
+			for _, count := range source.Counts {
+				counts[count.Severity] += count.Count
+			}
+		}
+		var parts []string
+		for severity := sources.SeverityCritical; severity > sources.SeverityOK; severity-- {
+			if counts[severity] > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s", counts[severity], severity))
+			}
+		}
+		message = strings.Join(parts, ", ")
+		color = severityColor(summary.Highest)
+	}
+
+	svg := `<svg xmlns="http://www.w3.org/2000/svg" width="{{ .Width }}" height="20" role="img" aria-label="{{ .Label }}: {{ .Message }}">
+  <title>{{ .Label }}: {{ .Message }}</title>
+  <clipPath id="r"><rect width="{{ .Width }}" height="20" rx="3" fill="#fff"/></clipPath>
+  <g clip-path="url(#r)">
+    <rect width="{{ .LabelWidth }}" height="20" fill="#555"/>
+    <rect x="{{ .LabelWidth }}" width="{{ .MessageWidth }}" height="20" fill="{{ .Color }}"/>
+  </g>
+  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+    <text x="{{ .LabelX }}" y="14">{{ .Label }}</text>
+    <text x="{{ .MessageX }}" y="14">{{ .Message }}</text>
+  </g>
+</svg>
+`
+	labelWidth := utf8.RuneCountInString(label)*badgeCharWidth + 10
+	messageWidth := utf8.RuneCountInString(message)*badgeCharWidth + 10
+	data := badgeTemplateData{
+		Label:        label,
+		Message:      message,
+		Color:        color,
+		Width:        labelWidth + messageWidth,
+		LabelWidth:   labelWidth,
+		MessageWidth: messageWidth,
+		LabelX:       labelWidth / 2,
+		MessageX:     labelWidth + messageWidth/2,
+	}
+
+	// html/template escapes the text and attributes of the SVG like in HTML
+	tmpl := template.Must(template.New("badge").Parse(svg))
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, &data); err != nil {
+		return nil, fmt.Errorf("error creating badge: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+type badgeTemplateData struct {
+	Label        string
+	Message      string
+	Color        string
+	Width        int
+	LabelWidth   int
+	MessageWidth int
+	LabelX       int
+	MessageX     int
+}
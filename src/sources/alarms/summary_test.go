@@ -0,0 +1,97 @@
+package alarms
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+func TestSummarizeAlarms(t *testing.T) {
+	summary := summarizeAlarms(nil)
+	if !summary.AllClear() || summary.Total != 0 {
+		t.Errorf("expected all clear, got %+v", summary)
+	}
+
+	summary = summarizeAlarms([]trackedAlarm{
+		{Alarm: Alarm{Source: "Netdata", Status: "WARNING"}},
+		{Alarm: Alarm{Source: "Radarr", Instance: "4k", Status: "ERROR"}},
+		{Alarm: Alarm{Source: "Netdata", Status: "CRITICAL"}},
+		{Alarm: Alarm{Source: "Netdata", Status: "WARNING"}},
+		{Alarm: Alarm{Source: "Backrest", Status: "SUCCESS"}},
+	})
+	if summary.AllClear() || summary.Total != 5 || summary.Highest != sources.SeverityCritical {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	var names []string
+	for _, source := range summary.Sources {
+		names = append(names, source.Name)
+	}
+	if strings.Join(names, ",") != "Netdata,Radarr (4k),Backrest" {
+		t.Errorf("unexpected sources order: %v", names)
+	}
+	netdata := summary.Sources[0].Counts
+	if len(netdata) != 2 || netdata[0] != (severityCount{sources.SeverityCritical, 1}) || netdata[1] != (severityCount{sources.SeverityWarning, 2}) {
+		t.Errorf("unexpected Netdata counts: %+v", netdata)
+	}
+
+	badge, err := getBadge(summary, "home<lab>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(badge), ">1 critical, 1 error, 2 warning<") || !strings.Contains(string(badge), `fill="red"`) || !strings.Contains(string(badge), "home&lt;lab&gt;") {
+		t.Errorf("unexpected badge: %s", badge)
+	}
+	badge, err = getBadge(summarizeAlarms(nil), "alarms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(badge), ">all clear<") || !strings.Contains(string(badge), `fill="green"`) {
+		t.Errorf("unexpected badge: %s", badge)
+	}
+}
+
+func TestSummaryLink(t *testing.T) {
+	query := url.Values{"alarms": {"netdata"}, "layout": {"summary"}, "link": {""}}
+	if link := summaryLink(query, ""); link != "alarms?alarms=netdata" {
+		t.Errorf("unexpected link: %s", link)
+	}
+	if link := summaryLink(query, "https://api.domain.com"); link != "https://api.domain.com/v1/iframe/alarms?alarms=netdata" {
+		t.Errorf("unexpected link: %s", link)
+	}
+	if query.Get("layout") != "summary" {
+		t.Errorf("the query was changed")
+	}
+}
+
+func TestStatusColor(t *testing.T) {
+	statusColors := map[string]string{
+		"CLEAR":     "green",
+		"SUCCESS":   "gray",
+		"CHANGED":   "orange",
+		"WARNING":   "orange",
+		"DOWNLOAD":  "gray",
+		"UNDEFINED": "gray",
+		"FAILED":    "red",
+		"CRITICAL":  "red",
+	}
+	for status, expected := range statusColors {
+		if color := getStatusColor(status); color != expected {
+			t.Errorf("status %s: expected %s, got %s", status, expected, color)
+		}
+	}
+
+	severityColors := map[sources.Severity]string{
+		sources.SeverityOK:       "green",
+		sources.SeverityInfo:     "gray",
+		sources.SeverityWarning:  "orange",
+		sources.SeverityError:    "red",
+		sources.SeverityCritical: "red",
+	}
+	for severity, expected := range severityColors {
+		if color := severityColor(severity); color != expected {
+			t.Errorf("severity %s: expected %s, got %s", severity, expected, color)
+		}
+	}
+}
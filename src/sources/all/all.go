@@ -0,0 +1,27 @@
+// Package all imports every source package so they register themselves in the sources registry.
+// New sources should be added here.
+package all
+
+import (
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/alarms"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/backrest"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/changedetectionio"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/cinemark"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/jellyseerr"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/kaizoku"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/kavita"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/lidarr"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/linkwarden"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/media"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/media-requets"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/netdata"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/openarchiver"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/overseerr"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/pihole"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/prowlarr"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/radarr"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/sonarr"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/speedtest-tracker"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/uptime-kuma"
+	_ "github.com/diogovalentte/homarr-iframes/src/sources/vikunja"
+)
@@ -0,0 +1,78 @@
+package backrest
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// GetIframeAlarms returns the not successful backups of the last 24 hours as alarms
+func (b *Backrest) GetIframeAlarms() ([]sources.Alarm, error) {
+	summary, err := b.GetSummaryDashboard()
+	if err != nil {
+		return nil, err
+	}
+
+	var alarms []sources.Alarm
+	for _, planSummary := range summary.PlanSummaries {
+		if planSummary.ID == "" {
+			planSummary.ID = "Unknown"
+		}
+
+		if planSummary.BackupsFailed30days == "0" {
+			continue
+		}
+
+		for i := range planSummary.RecentBackups.FlowID {
+			status := "Unknown"
+			switch planSummary.RecentBackups.Status[i] {
+			case "STATUS_SUCCESS":
+				continue
+			case "STATUS_WARNING":
+				status = "WARNING"
+			case "STATUS_ERROR":
+				status = "ERROR"
+			case "STATUS_INPROGRESS":
+				continue
+			}
+
+			t, err := strconv.ParseInt(planSummary.RecentBackups.TimeStampMs[i], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			timestamp := time.UnixMilli(t)
+			timestamp = timestamp.In(time.Local)
+			last24Hours := time.Now().Add(-24 * time.Hour)
+			if timestamp.Before(last24Hours) {
+				continue
+			}
+
+			durationMilliseconds, err := strconv.ParseFloat(planSummary.RecentBackups.DurationMs[i], 64)
+			if err != nil {
+				return nil, err
+			}
+			durationFormated := "00:00:00 min"
+			if durationMilliseconds != 0 {
+				duration := time.Duration(durationMilliseconds) * time.Millisecond
+				hours := int(duration.Hours())
+				minutes := int(duration.Minutes())
+				seconds := int(duration.Seconds()) % 60
+				durationFormated = fmt.Sprintf("%02d:%02d:%02d min", hours, minutes, seconds)
+			}
+
+			alarms = append(alarms, sources.Alarm{
+				Source:          "Backrest",
+				BackgroundColor: "black",
+				Summary:         fmt.Sprintf("Plan %s was not successful", planSummary.ID),
+				URL:             b.Address + "/#/plan/" + planSummary.ID,
+				Status:          status,
+				Property:        durationFormated,
+				Time:            timestamp,
+			})
+		}
+	}
+
+	return alarms, nil
+}
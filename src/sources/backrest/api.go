@@ -6,10 +6,12 @@ import (
 	"io"
 	"net/http"
 	"strings"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
 func (b *Backrest) baseRequest(method, url string, body io.Reader, target any) error {
-	client := &http.Client{}
+	client := sources.HTTPClient("backrest", b.Instance)
 	if body == nil {
 		body = strings.NewReader("{}")
 	}
@@ -2,14 +2,52 @@ package backrest
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
-var b *Backrest
+var instances = sources.NewInstances[*Backrest]("backrest")
+
+func init() {
+	sources.Register(sources.Integration{
+		Name:  "backrest",
+		Title: "Backrest",
+		Config: config.Schema{
+			Prefix:        "BACKREST",
+			MultiInstance: true,
+			Vars: []config.Var{
+				{Key: "ADDRESS", Required: true, Validate: config.ValidateURL},
+				{Key: "INTERNAL_ADDRESS", Validate: config.ValidateURL},
+				{Key: "USERNAME"},
+				{Key: "PASSWORD", Secret: true},
+			},
+		},
+		Alarms: func(instance string, _ url.Values) ([]sources.Alarm, error) {
+			b, err := NewInstance(instance)
+			if err != nil {
+				return nil, err
+			}
+
+			return b.GetIframeAlarms()
+		},
+		Check: func(instance string) (string, error) {
+			b, err := NewInstance(instance)
+			if err != nil {
+				return "", err
+			}
+			_, err = b.GetSummaryDashboard()
+
+			return "", err
+		},
+	})
+}
 
 type Backrest struct {
+	// Instance is the name of the instance, "" for the default instance
+	Instance        string
 	Address         string
 	InternalAddress string
 	username        string
@@ -17,26 +55,28 @@ type Backrest struct {
 }
 
 func New() (*Backrest, error) {
-	if b != nil {
-		return b, nil
-	}
-
-	newR := &Backrest{}
-	err := newR.Init()
-	if err != nil {
-		return nil, err
-	}
+	return NewInstance("")
+}
 
-	b = newR
+// NewInstance returns the client of a named Backrest instance, configured with the BACKREST_<INSTANCE>_* variables
+func NewInstance(instance string) (*Backrest, error) {
+	return instances.Get(instance, func(instance string) (*Backrest, error) {
+		newClient := &Backrest{Instance: instance}
+		err := newClient.Init()
+		if err != nil {
+			return nil, err
+		}
 
-	return b, nil
+		return newClient, nil
+	})
 }
 
 func (b *Backrest) Init() error {
-	address, internalAddress, username, password := config.GlobalConfigs.Backrest.Address, config.GlobalConfigs.Backrest.InternalAddress, config.GlobalConfigs.Backrest.Username, config.GlobalConfigs.Backrest.Password
-	if address == "" {
-		return fmt.Errorf("BACKREST_ADDRESS variable should be set")
+	sourceConfigs, err := config.Current().LoadInstance("backrest", b.Instance)
+	if err != nil {
+		return err
 	}
+	address, internalAddress, username, password := sourceConfigs.Get("ADDRESS"), sourceConfigs.Get("INTERNAL_ADDRESS"), sourceConfigs.Get("USERNAME"), sourceConfigs.Get("PASSWORD")
 
 	b.Address = strings.TrimSuffix(address, "/")
 	if internalAddress == "" {
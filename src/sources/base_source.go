@@ -3,12 +3,14 @@ package sources
 import (
 	"crypto/sha256"
 	"fmt"
+	"html/template"
 	"time"
 )
 
 // GetBaseNothingToShowiFrame returns an HTML code for when there is nothing to show
 // The template is a background image with some message
-func GetBaseNothingToShowiFrame(backgroundColor, backgroundImageURL, backgroundPosition, backgroundSize, backgroundFilter, APIURLPath string) []byte {
+// liveUpdates is the script returned by LiveUpdatesScript, it can be empty.
+func GetBaseNothingToShowiFrame(backgroundColor, backgroundImageURL, backgroundPosition, backgroundSize, backgroundFilter string, liveUpdates template.HTML) []byte {
 	html := `
 <!DOCTYPE html>
 <html lang="en">
@@ -42,35 +44,7 @@ func GetBaseNothingToShowiFrame(backgroundColor, backgroundImageURL, backgroundP
         }
     </style>
 
-    <script>
-        let lastHash = null;
-
-        async function fetchData() {
-            try {
-                var url = '%s';
-                const response = await fetch(url);
-                const data = await response.json();
-
-                if (lastHash === null) {
-                    lastHash = data.hash;
-                } else {
-                    if (data.hash !== lastHash) {
-                        lastHash = data.hash;
-                        location.reload();
-                    }
-                }
-            } catch (error) {
-                console.error('Error getting last update from the API:', error);
-            }
-        }
-
-        function fetchAndUpdate() {
-            fetchData();
-            setTimeout(fetchAndUpdate, 10000); // 10 seconds
-        }
-
-        %s
-    </script>
+    %s
 </head>
 <body>
     <div class="background-image"></div>
@@ -83,11 +57,7 @@ func GetBaseNothingToShowiFrame(backgroundColor, backgroundImageURL, backgroundP
 	case "dark":
 		backgroundColor = "#25262b"
 	}
-	var refreshIframe string
-	if APIURLPath != "" {
-		refreshIframe = "fetchAndUpdate();"
-	}
-	html = fmt.Sprintf(html, backgroundColor, backgroundImageURL, backgroundPosition, backgroundSize, backgroundFilter, APIURLPath, refreshIframe)
+	html = fmt.Sprintf(html, backgroundColor, backgroundImageURL, backgroundPosition, backgroundSize, backgroundFilter, liveUpdates)
 
 	return []byte(html)
 }
@@ -104,3 +74,13 @@ func IsToday(date time.Time) bool {
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	return date.After(today) && date.Before(today.Add(24*time.Hour))
 }
+
+// HashResponse is the response of the hash routes
+type HashResponse struct {
+	Hash string `json:"hash"`
+}
+
+// MessageResponse is the response of routes that only return a message
+type MessageResponse struct {
+	Message string `json:"message"`
+}
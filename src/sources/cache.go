@@ -0,0 +1,290 @@
+package sources
+
+import (
+	"log/slog"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/metrics"
+)
+
+// minCacheIdleTimeout is the minimum time an entry is kept refreshing without being read
+const minCacheIdleTimeout = 5 * time.Minute
+
+var (
+	defaultCache     *Cache
+	defaultCacheOnce sync.Once
+)
+
+// Cache caches the data of the iFrames, so the iFrame and hash routes don't
+// request the sources every time. The entries are refreshed in the background
+// every interval, and removed when they are not read for a while.
+// Concurrent fetches of the same key are deduplicated.
+type Cache struct {
+	interval    time.Duration
+	mu          sync.Mutex
+	entries     map[string]*cacheEntry
+	subscribers map[string]map[chan struct{}]struct{}
+	group       singleflight.Group
+	// running is true while the refresh loop is running
+	running bool
+	// intervalChanged wakes up the refresh loop when the interval changes
+	intervalChanged chan struct{}
+	now             func() time.Time
+}
+
+type cacheEntry struct {
+	value    any
+	fetch    func() (any, error)
+	lastRead time.Time
+}
+
+// NewCache returns a new cache that refreshes its entries every interval.
+// If interval is zero or negative, nothing is cached, but concurrent fetches are still deduplicated.
+func NewCache(interval time.Duration) *Cache {
+	return &Cache{
+		interval:        interval,
+		entries:         map[string]*cacheEntry{},
+		subscribers:     map[string]map[chan struct{}]struct{}{},
+		intervalChanged: make(chan struct{}, 1),
+		now:             time.Now,
+	}
+}
+
+// getDefaultCache returns the default cache, creating it with the CACHE_REFRESH_INTERVAL config
+func getDefaultCache() *Cache {
+	defaultCacheOnce.Do(func() {
+		defaultCache = NewCache(config.Current().IFrames.CacheRefreshInterval)
+	})
+
+	return defaultCache
+}
+
+// Cached returns the value of the key from the default cache, fetching it if it's not cached.
+// The default cache uses the CACHE_REFRESH_INTERVAL config.
+func Cached[T any](key string, fetch func() (T, error)) (T, error) {
+	return Fetch[T](getDefaultCache(), key, fetch)
+}
+
+// SubscribeCache returns a channel that receives a value when the cached data
+// of a source changes in the default cache. Call unsubscribe when done.
+// If the cache is disabled, the channel never receives.
+func SubscribeCache(name string) (changes <-chan struct{}, unsubscribe func()) {
+	return getDefaultCache().Subscribe(name)
+}
+
+// InvalidateCache removes the entries of a source from the default cache, like
+// after setting a task as done, so the next request gets the updated data.
+func InvalidateCache(name string) {
+	if defaultCache != nil {
+		defaultCache.Invalidate(name)
+	}
+}
+
+// CacheKey returns a cache key for a source using only the query parameters in keys,
+// the ones that change the data fetched from the source. The parameters are sorted
+// and the empty ones are ignored, so equivalent requests have the same key.
+func CacheKey(name string, query url.Values, keys ...string) string {
+	params := url.Values{}
+	for _, key := range keys {
+		for _, value := range query[key] {
+			if value != "" {
+				params.Add(key, value)
+			}
+		}
+	}
+
+	return name + "?" + params.Encode()
+}
+
+// Fetch returns the value of the key, fetching it with fetch if it's not cached.
+// Errors are not cached.
+func Fetch[T any](c *Cache, key string, fetch func() (T, error)) (T, error) {
+	value, err := c.get(key, func() (any, error) {
+		return fetch()
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return value.(T), nil
+}
+
+func (c *Cache) get(key string, fetch func() (any, error)) (any, error) {
+	c.mu.Lock()
+	if c.interval <= 0 {
+		c.mu.Unlock()
+		value, err, _ := c.group.Do(key, fetch)
+		return value, err
+	}
+	if !c.running {
+		c.running = true
+		go c.refreshLoop()
+	}
+
+	name, _, _ := strings.Cut(key, "?")
+	if entry, ok := c.entries[key]; ok {
+		entry.lastRead = c.now()
+		c.mu.Unlock()
+		metrics.CacheRequests.WithLabelValues(name, "hit").Inc()
+		return entry.value, nil
+	}
+	c.mu.Unlock()
+	metrics.CacheRequests.WithLabelValues(name, "miss").Inc()
+
+	value, err, _ := c.group.Do(key, func() (any, error) {
+		value, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		if c.interval > 0 {
+			c.entries[key] = &cacheEntry{value: value, fetch: fetch, lastRead: c.now()}
+		}
+		c.mu.Unlock()
+
+		return value, nil
+	})
+
+	return value, err
+}
+
+// Invalidate removes the entries of a source, the ones with keys starting with "<name>?"
+func (c *Cache) Invalidate(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if strings.HasPrefix(key, name+"?") {
+			delete(c.entries, key)
+		}
+	}
+	c.notify(name + "?")
+}
+
+// Subscribe returns a channel that receives a value when the entries of a source change,
+// like when a refresh gets different data. Many changes can be coalesced into a single value.
+func (c *Cache) Subscribe(name string) (changes <-chan struct{}, unsubscribe func()) {
+	ch := make(chan struct{}, 1)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.subscribers[name] == nil {
+		c.subscribers[name] = map[chan struct{}]struct{}{}
+	}
+	c.subscribers[name][ch] = struct{}{}
+
+	return ch, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.subscribers[name], ch)
+		if len(c.subscribers[name]) == 0 {
+			delete(c.subscribers, name)
+		}
+	}
+}
+
+// notify notifies the subscribers of the source of a key. c.mu must be held.
+func (c *Cache) notify(key string) {
+	name, _, _ := strings.Cut(key, "?")
+	for ch := range c.subscribers[name] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// SetInterval changes the refresh interval of the cache, like when the configs are reloaded.
+// If interval is zero or negative, the cache is disabled and its entries are removed.
+func (c *Cache) SetInterval(interval time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if interval == c.interval {
+		return
+	}
+
+	c.interval = interval
+	if interval <= 0 {
+		for key := range c.entries {
+			delete(c.entries, key)
+			c.notify(key)
+		}
+	}
+	select {
+	case c.intervalChanged <- struct{}{}:
+	default:
+	}
+}
+
+// refreshLoop refreshes the entries every interval. It stops when the cache is disabled,
+// and is started again by the next fetch if the cache is enabled again.
+func (c *Cache) refreshLoop() {
+	for {
+		c.mu.Lock()
+		interval := c.interval
+		if interval <= 0 {
+			c.running = false
+			c.mu.Unlock()
+			return
+		}
+		c.mu.Unlock()
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-timer.C:
+			c.refresh()
+		case <-c.intervalChanged:
+			timer.Stop()
+		}
+	}
+}
+
+// refresh fetches the entries again, removing the idle ones. If a fetch fails, the
+// entry is removed, so the next request fetches it again and gets the error.
+func (c *Cache) refresh() {
+	c.mu.Lock()
+	idleTimeout := max(minCacheIdleTimeout, 2*c.interval)
+	entries := make(map[string]*cacheEntry, len(c.entries))
+	for key, entry := range c.entries {
+		if c.now().Sub(entry.lastRead) > idleTimeout {
+			delete(c.entries, key)
+			continue
+		}
+		entries[key] = entry
+	}
+	c.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for key, entry := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err, _ := c.group.Do(key, entry.fetch)
+
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			if current, ok := c.entries[key]; !ok || current != entry {
+				return
+			}
+			if err != nil {
+				slog.Warn("error refreshing cached data", "key", key, "error", err)
+				delete(c.entries, key)
+				c.notify(key)
+				return
+			}
+			if !reflect.DeepEqual(entry.value, value) {
+				entry.value = value
+				c.notify(key)
+			}
+		}()
+	}
+	wg.Wait()
+}
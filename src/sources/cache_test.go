@@ -0,0 +1,186 @@
+package sources
+
+import (
+	"errors"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheFetch(t *testing.T) {
+	cache := NewCache(time.Hour)
+	var calls int
+	fetch := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	for range 3 {
+		value, err := Fetch(cache, "key", fetch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value != 1 {
+			t.Fatalf("expected cached value 1, got %d", value)
+		}
+	}
+
+	cache.refresh()
+	value, err := Fetch(cache, "key", fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != 2 {
+		t.Fatalf("expected refreshed value 2, got %d", value)
+	}
+}
+
+func TestCacheDeduplicatesFetches(t *testing.T) {
+	cache := NewCache(time.Hour)
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fetch := func() (int, error) {
+		calls.Add(1)
+		<-release
+		return 1, nil
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := Fetch(cache, "key", fetch); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected 1 fetch, got %d", calls.Load())
+	}
+}
+
+func TestCacheErrors(t *testing.T) {
+	cache := NewCache(time.Hour)
+	fail := true
+	var calls int
+	fetch := func() (int, error) {
+		calls++
+		if fail {
+			return 0, errors.New("source unreachable")
+		}
+		return calls, nil
+	}
+
+	if _, err := Fetch(cache, "key", fetch); err == nil {
+		t.Fatal("expected error")
+	}
+	fail = false
+	if _, err := Fetch(cache, "key", fetch); err != nil {
+		t.Fatalf("errors should not be cached, got %s", err)
+	}
+
+	// A failed refresh removes the entry, so the next request gets the error
+	fail = true
+	cache.refresh()
+	if _, err := Fetch(cache, "key", fetch); err == nil {
+		t.Fatal("expected error after failed refresh")
+	}
+}
+
+func TestCacheIdleEntries(t *testing.T) {
+	cache := NewCache(time.Hour)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	var calls int
+	fetch := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+	if _, err := Fetch(cache, "key", fetch); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(3 * time.Hour)
+	cache.refresh()
+	if calls != 1 {
+		t.Fatalf("idle entry should not be refreshed, got %d fetches", calls)
+	}
+	if _, ok := cache.entries["key"]; ok {
+		t.Fatal("idle entry should be removed")
+	}
+}
+
+func TestCacheDisabled(t *testing.T) {
+	cache := NewCache(0)
+	var calls int
+	fetch := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+	Fetch(cache, "key", fetch)
+	Fetch(cache, "key", fetch)
+	if calls != 2 {
+		t.Fatalf("expected 2 fetches with the cache disabled, got %d", calls)
+	}
+}
+
+func TestCacheSetInterval(t *testing.T) {
+	cache := NewCache(0)
+	var calls int
+	fetch := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+	Fetch(cache, "key", fetch)
+
+	cache.SetInterval(time.Hour)
+	Fetch(cache, "key", fetch)
+	Fetch(cache, "key", fetch)
+	if calls != 2 {
+		t.Fatalf("expected the value to be cached after enabling the cache, got %d fetches", calls)
+	}
+
+	cache.SetInterval(0)
+	if len(cache.entries) != 0 {
+		t.Fatal("the entries should be removed when the cache is disabled")
+	}
+	Fetch(cache, "key", fetch)
+	Fetch(cache, "key", fetch)
+	if calls != 4 {
+		t.Fatalf("expected 4 fetches with the cache disabled, got %d", calls)
+	}
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	cache := NewCache(time.Hour)
+	fetch := func() (int, error) { return 1, nil }
+	Fetch(cache, "vikunja?limit=5", fetch)
+	Fetch(cache, "vikunjaa?limit=5", fetch)
+
+	cache.Invalidate("vikunja")
+	if _, ok := cache.entries["vikunja?limit=5"]; ok {
+		t.Fatal("entry should be invalidated")
+	}
+	if _, ok := cache.entries["vikunjaa?limit=5"]; !ok {
+		t.Fatal("entry of another source should not be invalidated")
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	a := CacheKey("vikunja", url.Values{"project_id": {"2"}, "limit": {"5"}, "theme": {"dark"}}, "limit", "project_id")
+	b := CacheKey("vikunja", url.Values{"limit": {"5"}, "project_id": {"2"}, "exclude_project_ids": {""}}, "limit", "project_id", "exclude_project_ids")
+	if a != b {
+		t.Fatalf("expected equal keys, got %q and %q", a, b)
+	}
+	if expected := "vikunja?limit=5&project_id=2"; a != expected {
+		t.Fatalf("expected %q, got %q", expected, a)
+	}
+}
@@ -0,0 +1,79 @@
+package changedetectionio
+
+import (
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// GetIframeAlarms returns the watches with errors and the watches changed in the last ChangedLastHours as alarms
+func (c *ChangeDetectionIO) GetIframeAlarms(showViewed bool) ([]sources.Alarm, error) {
+	watches, err := c.GetWatches()
+	if err != nil {
+		return nil, err
+	}
+
+	var alarms []sources.Alarm
+	for ID, watch := range watches {
+		var hasError bool
+		if value, ok := watch.LastError.(bool); !ok {
+			errStr := watch.LastError.(string)
+			if errStr != "" {
+				hasError = true
+			}
+		} else if value {
+			hasError = true
+		}
+
+		if hasError {
+			errStr := watch.LastError.(string)
+			lastChecked := time.Unix(int64(watch.LastChecked), 0)
+			summary := watch.Title
+			if watch.Title == "" {
+				summary = watch.URL
+			}
+			alarms = append(alarms, sources.Alarm{
+				Source:            "ChangeDetection.io",
+				BackgroundImgURL:  BackgroundImgURL,
+				BackgroundImgSize: 100,
+				Summary:           summary,
+				URL:               c.Address,
+				Status:            "ERROR",
+				Property:          errStr,
+				Time:              lastChecked,
+			})
+
+			continue
+		}
+
+		if watch.Viewed && !showViewed {
+			continue
+		}
+
+		minChanged := time.Now().Add(-time.Hour * time.Duration(c.ChangedLastHours))
+		lastChanged := time.Unix(int64(watch.LastChanged), 0)
+		if lastChanged.After(minChanged) {
+			summary := watch.Title
+			if summary == "" {
+				summary = watch.URL
+			}
+			viewed := "Viewed"
+			if !watch.Viewed {
+				viewed = "Not Viewed"
+			}
+
+			alarms = append(alarms, sources.Alarm{
+				Source:            "ChangeDetection.io",
+				BackgroundImgURL:  BackgroundImgURL,
+				BackgroundImgSize: 100,
+				Summary:           summary,
+				URL:               c.Address + "/diff/" + ID,
+				Status:            "CHANGED",
+				Value:             viewed,
+				Time:              lastChanged,
+			})
+		}
+	}
+
+	return alarms, nil
+}
@@ -2,43 +2,95 @@ package changedetectionio
 
 import (
 	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
 var (
-	c                *ChangeDetectionIO
+	instances        = sources.NewInstances[*ChangeDetectionIO]("changedetectionio")
 	BackgroundImgURL = "https://i.imgur.com/16Q6GPD.png"
 )
 
+func init() {
+	sources.Register(sources.Integration{
+		Name:  "changedetectionio",
+		Title: "ChangeDetection.io",
+		Config: config.Schema{
+			Prefix:        "CHANGEDETECTIONIO",
+			MultiInstance: true,
+			Vars: []config.Var{
+				{Key: "ADDRESS", Required: true, Validate: config.ValidateURL},
+				{Key: "INTERNAL_ADDRESS", Validate: config.ValidateURL},
+				{Key: "API_KEY", Required: true, Secret: true},
+				{Key: "CHANGED_LAST_HOURS", Default: "24", Validate: config.ValidateInt},
+			},
+		},
+		Alarms: func(instance string, params url.Values) ([]sources.Alarm, error) {
+			showViewed := true
+			if showViewedStr := params.Get("changedetectionio_show_viewed"); showViewedStr != "" {
+				var err error
+				showViewed, err = strconv.ParseBool(showViewedStr)
+				if err != nil {
+					return nil, fmt.Errorf("changedetectionio_show_viewed must be a boolean")
+				}
+			}
+
+			c, err := NewInstance(instance)
+			if err != nil {
+				return nil, err
+			}
+
+			return c.GetIframeAlarms(showViewed)
+		},
+		Check: func(instance string) (string, error) {
+			c, err := NewInstance(instance)
+			if err != nil {
+				return "", err
+			}
+			_, err = c.GetWatches()
+
+			return "", err
+		},
+	})
+}
+
 type ChangeDetectionIO struct {
+	// Instance is the name of the instance, "" for the default instance
+	Instance        string
 	Address         string
 	InternalAddress string
 	APIKey          string
+	// ChangedLastHours is how many hours a watch is considered changed after its last change
+	ChangedLastHours int
 }
 
 func New() (*ChangeDetectionIO, error) {
-	if c != nil {
-		return c, nil
-	}
-
-	newR := &ChangeDetectionIO{}
-	err := newR.Init()
-	if err != nil {
-		return nil, err
-	}
+	return NewInstance("")
+}
 
-	c = newR
+// NewInstance returns the client of a named ChangeDetection.io instance, configured with the CHANGEDETECTIONIO_<INSTANCE>_* variables
+func NewInstance(instance string) (*ChangeDetectionIO, error) {
+	return instances.Get(instance, func(instance string) (*ChangeDetectionIO, error) {
+		newClient := &ChangeDetectionIO{Instance: instance}
+		err := newClient.Init()
+		if err != nil {
+			return nil, err
+		}
 
-	return c, nil
+		return newClient, nil
+	})
 }
 
 func (c *ChangeDetectionIO) Init() error {
-	address, internalAddress, APIKey := config.GlobalConfigs.ChangeDetectionIO.Address, config.GlobalConfigs.ChangeDetectionIO.InternalAddress, config.GlobalConfigs.ChangeDetectionIO.APIKey
-	if address == "" || APIKey == "" {
-		return fmt.Errorf("CHANGEDETECTIONIO_ADDRESS and CHANGEDETECTIONIO_API_KEY variables should be set")
+	sourceConfigs, err := config.Current().LoadInstance("changedetectionio", c.Instance)
+	if err != nil {
+		return err
 	}
+	address, internalAddress, APIKey := sourceConfigs.Get("ADDRESS"), sourceConfigs.Get("INTERNAL_ADDRESS"), sourceConfigs.Get("API_KEY")
 
 	c.Address = strings.TrimSuffix(address, "/")
 	if internalAddress == "" {
@@ -47,6 +99,7 @@ func (c *ChangeDetectionIO) Init() error {
 		c.InternalAddress = strings.TrimSuffix(internalAddress, "/")
 	}
 	c.APIKey = APIKey
+	c.ChangedLastHours = sourceConfigs.Int("CHANGED_LAST_HOURS")
 
 	return nil
 }
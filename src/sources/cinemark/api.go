@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
 var (
@@ -77,7 +78,7 @@ type onDisplayByTheaterResponse struct {
 }
 
 func (Cinemark) baseRequest(method, url string, body io.Reader, target any) error {
-	client := &http.Client{}
+	client := sources.HTTPClient("cinemark", "")
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
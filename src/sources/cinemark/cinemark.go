@@ -17,6 +17,16 @@ import (
 
 var backgroundImageURL = "https://static.vecteezy.com/system/resources/previews/025/470/292/large_2x/background-image-date-at-the-cinema-popcorn-ai-generated-photo.jpeg"
 
+func init() {
+	sources.Register(sources.Integration{
+		Name:   "cinemark",
+		Title:  "Cinemark",
+		IFrame: iFrameHandler,
+		Hash:   hashHandler,
+		Data:   dataHandler,
+	})
+}
+
 type Cinemark struct{}
 
 // GetiFrame returns an iframe with the in theater movies for a specific city
@@ -68,7 +78,7 @@ func (Cinemark) GetiFrame(c *gin.Context) {
 	}
 
 	cinemark := Cinemark{}
-	movies, err := cinemark.GetOnDisplayByTheater(theaterIDs, limit, limitProvided)
+	movies, err := cinemark.getMovies(c.Request.URL.Query(), theaterIDs, limit, limitProvided)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -76,13 +86,9 @@ func (Cinemark) GetiFrame(c *gin.Context) {
 
 	var html []byte
 	if len(movies) < 1 {
-		var apiURLPath string
-		if apiURL != "" {
-			apiURLPath = apiURL + "/v1/hash/cinemark?limit=" + strconv.Itoa(limit) + "&theaterIds=" + theaterIDsStr
-		}
-		html = sources.GetBaseNothingToShowiFrame(theme, backgroundImageURL, "center", "cover", "brightness(0.3)", apiURLPath)
+		html = sources.GetBaseNothingToShowiFrame(theme, backgroundImageURL, "center", "cover", "brightness(0.3)", sources.LiveUpdatesScript(apiURL, "cinemark"))
 	} else {
-		html, err = getMoviesiFrame(movies, theme, apiURL, limit, theaterIDsStr)
+		html, err = getMoviesiFrame(movies, theme, apiURL)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("Couldn't create HTML code: %s", err.Error())})
 			return
@@ -92,7 +98,7 @@ func (Cinemark) GetiFrame(c *gin.Context) {
 	c.Data(http.StatusOK, "text/html", []byte(html))
 }
 
-func getMoviesiFrame(movies []Movie, theme, apiURL string, limit int, theaterIDs string) ([]byte, error) {
+func getMoviesiFrame(movies []Movie, theme, apiURL string) ([]byte, error) {
 	html := `
 <!doctype html>
 <html lang="en">
@@ -245,37 +251,7 @@ func getMoviesiFrame(movies []Movie, theme, apiURL string, limit int, theaterIDs
         }
     </style>
 
-    <script>
-        let lastHash = null;
-
-        async function fetchData() {
-            try {
-                var url = '{{ .APIURL }}/v1/hash/cinemark?limit={{ .APILimit }}&theaterIds={{ .TheaterID }}';
-                const response = await fetch(url);
-                const data = await response.json();
-
-                if (lastHash === null) {
-                    lastHash = data.hash;
-                } else {
-                    if (data.hash !== lastHash) {
-                        lastHash = data.hash;
-                        location.reload();
-                    }
-                }
-            } catch (error) {
-                console.error('Error getting last update from the API:', error);
-            }
-        }
-
-        function fetchAndUpdate() {
-            fetchData();
-            setTimeout(fetchAndUpdate, 10000); // 10 seconds
-        }
-
-        {{ if .APIURL }}
-            fetchAndUpdate();
-        {{ end }}
-    </script>
+    {{ .LiveUpdates }}
 
 </head>
 <body>
@@ -322,9 +298,7 @@ func getMoviesiFrame(movies []Movie, theme, apiURL string, limit int, theaterIDs
 	templateData := iframeTemplateData{
 		Movies:                        movies,
 		Theme:                         theme,
-		APIURL:                        apiURL,
-		APILimit:                      limit,
-		TheaterID:                     theaterIDs,
+		LiveUpdates:                   sources.LiveUpdatesScript(apiURL, "cinemark"),
 		BackgroundImageURL:            backgroundImageURL,
 		ScrollbarThumbBackgroundColor: scrollbarThumbBackgroundColor,
 		ScrollbarTrackBackgroundColor: scrollbarTrackBackgroundColor,
@@ -344,9 +318,7 @@ func getMoviesiFrame(movies []Movie, theme, apiURL string, limit int, theaterIDs
 type iframeTemplateData struct {
 	Movies                        []Movie
 	Theme                         string
-	APIURL                        string
-	APILimit                      int
-	TheaterID                     string
+	LiveUpdates                   template.HTML
 	BackgroundImageURL            string
 	ScrollbarThumbBackgroundColor string
 	ScrollbarTrackBackgroundColor string
@@ -354,18 +326,31 @@ type iframeTemplateData struct {
 
 // GetHash returns the hash of the in theater movies for a specific city
 func (Cinemark) GetHash(c *gin.Context) {
+	movies, ok := getQueryData(c)
+	if !ok {
+		return
+	}
+
+	hash := sources.GetHash(movies, time.Now().Format("2006-01-02"))
+
+	c.JSON(http.StatusOK, gin.H{"hash": fmt.Sprintf("%x", hash)})
+}
+
+// getQueryData parses the query parameters used by the hash and data routes and returns the movies.
+// If it fails, it writes the error response and returns false.
+func getQueryData(c *gin.Context) ([]Movie, bool) {
 	theaterIDsStr := c.Query("theaterIds")
 	var theaterIDs []int
 	if theaterIDsStr == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"message": "theaterIds is required"})
-		return
+		return nil, false
 	}
 	theaterStrings := strings.Split(theaterIDsStr, ",")
 	for _, theaterStr := range theaterStrings {
 		theaterID, err := strconv.Atoi(theaterStr)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"message": "theaterIds must be a list of numbers"})
-			return
+			return nil, false
 		}
 		theaterIDs = append(theaterIDs, theaterID)
 	}
@@ -378,19 +363,26 @@ func (Cinemark) GetHash(c *gin.Context) {
 		limit, err = strconv.Atoi(queryLimit)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"message": "limit must be a number"})
-			return
+			return nil, false
 		}
 		limitProvided = true
 	}
 
 	cinemark := Cinemark{}
-	movies, err := cinemark.GetOnDisplayByTheater(theaterIDs, limit, limitProvided)
+	movies, err := cinemark.getMovies(c.Request.URL.Query(), theaterIDs, limit, limitProvided)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-		return
+		return nil, false
 	}
 
-	hash := sources.GetHash(movies, time.Now().Format("2006-01-02"))
+	return movies, true
+}
 
-	c.JSON(http.StatusOK, gin.H{"hash": fmt.Sprintf("%x", hash)})
+// getMovies returns the movies used by the iFrame and hash routes.
+// The movies are cached by the query parameters that change them.
+func (cinemark Cinemark) getMovies(query url.Values, theaterIDs []int, limit int, limitProvided bool) ([]Movie, error) {
+	key := sources.CacheKey("cinemark", query, "theaterIds", "limit")
+	return sources.Cached(key, func() ([]Movie, error) {
+		return cinemark.GetOnDisplayByTheater(theaterIDs, limit, limitProvided)
+	})
 }
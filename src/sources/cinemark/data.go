@@ -0,0 +1,46 @@
+package cinemark
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// Data is the response of the /v1/data/cinemark route
+type Data struct {
+	Version int         `json:"version"`
+	Movies  []MovieData `json:"movies"`
+}
+
+// MovieData is a movie in the /v1/data/cinemark route
+type MovieData struct {
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	CoverImgURL string `json:"cover_img_url"`
+	// AgeRating can be: L, 12, 14, 16, 18
+	AgeRating string `json:"age_rating"`
+	Genre     string `json:"genre"`
+}
+
+// GetData returns the movies as JSON
+func (Cinemark) GetData(c *gin.Context) {
+	movies, ok := getQueryData(c)
+	if !ok {
+		return
+	}
+
+	response := Data{Version: sources.DataVersion, Movies: []MovieData{}}
+	for _, movie := range movies {
+		response.Movies = append(response.Movies, MovieData{
+			Name:        movie.Name,
+			URL:         movie.URL,
+			CoverImgURL: movie.CoverImgURL,
+			AgeRating:   movie.AgeRating,
+			Genre:       movie.Genre,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
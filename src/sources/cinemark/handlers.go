@@ -0,0 +1,41 @@
+package cinemark
+
+import "github.com/gin-gonic/gin"
+
+// @Summary Cinemark Brazil iFrame
+// @Description Returns an iFrame with the on display movies in specific Cinemark theaters. I recommend you to get the movies from the theaters of your city.
+// @Success 200 {string} string "HTML content"
+// @Produce html
+// @Param theaterIds query string true "The theater IDs to get movies from. Access the cinemark site, select a theater, open your browser developer console, go to the "Network" tab, filter using the 'onDisplayByTheater' term, and get the theaterId value from the request URL. You have to do it for every theater. Example: 'theaterIds=715, 1222, 4555'" Example(715, 1222, 4555)
+// @Param theme query string false "Homarr theme, defaults to light. If it's different from your Homarr theme, the background turns white" Example(light)
+// @Param limit query int false "Limits the number of items in the iFrame." Example(5)
+// @Param api_url query string true "API URL used by your browser. Used by the iFrames to receive updates, if there is an update, the iFrame updates the changed items without reloading. If not specified, the iFrames will never try to reload." Example(https://sub.domain.com)
+// @Router /iframe/cinemark [get]
+func iFrameHandler(c *gin.Context) {
+	cin := Cinemark{}
+	cin.GetiFrame(c)
+}
+
+// @Summary Get the hash of the Cinemark movies
+// @Description Get the hash of the Cinemark movies. Used by the iFrames to check updates and reload the iframe.
+// @Success 200 {object} sources.HashResponse
+// @Produce json
+// @Param theaterIds query string true "The theater IDs to get movies from. It used to be easy to get, but now it's harder. To get it, you need to access the cinemark site, select a theater, open your browser developer console, go to the "Network" tab, filter using the 'onDisplayByTheater' term, and get the theaterId value from the request URL. You have to do it for every theater. Example: 'theaterIds=715, 1222, 4555'" Example(715, 1222, 4555)
+// @Param limit query int false "Limits the number of items in the iFrame." Example(5)
+// @Router /hash/cinemark [get]
+func hashHandler(c *gin.Context) {
+	cin := Cinemark{}
+	cin.GetHash(c)
+}
+
+// @Summary Get the Cinemark movies data
+// @Description Get the on display movies in specific Cinemark theaters as JSON, in the same order as the iFrame.
+// @Success 200 {object} Data
+// @Produce json
+// @Param theaterIds query string true "The theater IDs to get movies from. Example: 'theaterIds=715, 1222, 4555'" Example(715, 1222, 4555)
+// @Param limit query int false "Limits the number of items." Example(5)
+// @Router /data/cinemark [get]
+func dataHandler(c *gin.Context) {
+	cin := Cinemark{}
+	cin.GetData(c)
+}
@@ -0,0 +1,16 @@
+package sources
+
+import "time"
+
+// DataVersion is the version of the /v1/data/<name> routes response format.
+// It's increased when a field is removed or changes its meaning. New fields can be added in the same version.
+const DataVersion = 1
+
+// OptionalTime returns nil if the time is zero, so it's omitted in the data routes responses
+func OptionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
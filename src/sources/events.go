@@ -0,0 +1,197 @@
+package sources
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+var (
+	// eventsKeepAliveInterval is how often a comment is sent to keep the events connection open
+	eventsKeepAliveInterval = 30 * time.Second
+	// eventsPollInterval is how often the iFrame is rendered again when the cache is disabled
+	eventsPollInterval = 10 * time.Second
+)
+
+// EventsHandler returns a handler for the /v1/events/<name> route. It's a Server-Sent Events
+// stream that sends an "update" event with the iFrame HTML, as a JSON string, every time it changes.
+// The query parameters are the same as the iFrame route, and the iFrame is rendered again when
+// the cached data of the integration changes. The iFrames with actions are also rendered again
+// before their action tokens expire.
+func EventsHandler(integration *Integration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		changes, unsubscribe := SubscribeCache(integration.Name)
+		defer unsubscribe()
+
+		// The cache can be disabled by a configs reload, so it's checked at every poll
+		poll := time.NewTicker(eventsPollInterval)
+		defer poll.Stop()
+		var refreshTokens <-chan time.Time
+		if len(integration.Actions) > 0 {
+			ticker := time.NewTicker(ActionTokenTTL / 2)
+			defer ticker.Stop()
+			refreshTokens = ticker.C
+		}
+		keepAlive := time.NewTicker(eventsKeepAliveInterval)
+		defer keepAlive.Stop()
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+
+		var lastHash [32]byte
+		update := func() {
+			html, err := renderIFrame(integration.IFrame, c.Request)
+			if err != nil {
+				slog.Warn("error rendering iFrame for live updates", "source", integration.Name, "error", err)
+				c.SSEvent("error", config.Redact(err.Error()))
+				c.Writer.Flush()
+				return
+			}
+			hash := sha256.Sum256(html)
+			if hash == lastHash {
+				return
+			}
+			lastHash = hash
+
+			data, _ := json.Marshal(string(html))
+			c.SSEvent("update", string(data))
+			c.Writer.Flush()
+		}
+
+		update()
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case <-changes:
+				update()
+			case <-poll.C:
+				if config.Current().IFrames.CacheRefreshInterval <= 0 {
+					update()
+				}
+			case <-refreshTokens:
+				update()
+			case <-keepAlive.C:
+				fmt.Fprint(c.Writer, ": keep-alive\n\n")
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
+// renderIFrame calls an iFrame handler with the query parameters of the request and returns the HTML
+func renderIFrame(handler gin.HandlerFunc, request *http.Request) ([]byte, error) {
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = request.Clone(request.Context())
+	handler(ctx)
+
+	if recorder.Code != http.StatusOK {
+		var message MessageResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &message); err == nil && message.Message != "" {
+			return nil, fmt.Errorf("error rendering iFrame: %s", message.Message)
+		}
+		return nil, fmt.Errorf("error rendering iFrame: status code %d", recorder.Code)
+	}
+
+	return recorder.Body.Bytes(), nil
+}
+
+// LiveUpdatesScript returns a script that connects to the events route of an iFrame and
+// updates the page when the iFrame changes. Only the changed elements are replaced, so the
+// images don't flash and the scroll position is kept. If apiURL is empty, it returns nothing.
+// The script uses the query parameters of the iFrame page to connect to the events route.
+func LiveUpdatesScript(apiURL, name string) template.HTML {
+	if apiURL == "" {
+		return ""
+	}
+	eventsURL, _ := json.Marshal(apiURL + "/v1/events/" + name)
+
+	return template.HTML(fmt.Sprintf(liveUpdatesScript, eventsURL))
+}
+
+const liveUpdatesScript = `
+    <script>
+        (function () {
+            // Moves the children of next into current, reusing the current nodes that didn't change
+            function patchChildren(current, next) {
+                const available = Array.from(current.childNodes);
+                const result = [];
+                Array.from(next.childNodes).forEach((nextChild) => {
+                    let index = available.findIndex((node) => node.isEqualNode(nextChild));
+                    if (index !== -1) {
+                        result.push(available.splice(index, 1)[0]);
+                        return;
+                    }
+                    index = available.findIndex((node) => sameElement(node, nextChild));
+                    if (index !== -1) {
+                        const node = available.splice(index, 1)[0];
+                        patchChildren(node, nextChild);
+                        result.push(node);
+                        return;
+                    }
+                    result.push(document.importNode(nextChild, true));
+                });
+
+                result.forEach((node, i) => {
+                    if (current.childNodes[i] !== node) {
+                        current.insertBefore(node, current.childNodes[i] || null);
+                    }
+                });
+                while (current.childNodes.length > result.length) {
+                    current.removeChild(current.lastChild);
+                }
+            }
+
+            // sameElement returns true if the elements have the same tag and attributes, and children to patch
+            function sameElement(a, b) {
+                if (a.nodeType !== Node.ELEMENT_NODE || b.nodeType !== Node.ELEMENT_NODE) return false;
+                if (a.tagName !== b.tagName || a.tagName === 'SCRIPT' || b.children.length === 0) return false;
+                if (a.attributes.length !== b.attributes.length) return false;
+                return Array.from(b.attributes).every((attr) => a.getAttribute(attr.name) === attr.value);
+            }
+
+            // sameHead returns true if the page has the same title and styles, some scripts add elements to the head
+            function sameHead(next) {
+                if (next.title !== document.title) return false;
+                const styles = Array.from(document.head.querySelectorAll('style')).map((style) => style.textContent);
+                return Array.from(next.head.querySelectorAll('style')).every((style) => styles.includes(style.textContent));
+            }
+
+            function update(html) {
+                const next = new DOMParser().parseFromString(html, 'text/html');
+                if (!sameHead(next)) {
+                    location.reload();
+                    return;
+                }
+                patchChildren(document.body, next.body);
+            }
+
+            const events = new EventSource(%s + window.location.search);
+            events.addEventListener('update', (event) => {
+                try {
+                    update(JSON.parse(event.data));
+                } catch (error) {
+                    console.error('Error updating the iFrame:', error);
+                }
+            });
+            events.addEventListener('error', (event) => {
+                if (event.data) {
+                    console.error('Error getting updates from the API:', event.data);
+                }
+            });
+        })();
+    </script>
+`
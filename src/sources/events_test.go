@@ -0,0 +1,76 @@
+package sources
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+func TestEventsHandler(t *testing.T) {
+	if config.Current() == nil {
+		configs := &config.Configs{}
+		configs.IFrames.CacheRefreshInterval = time.Hour
+		config.Set(configs)
+	}
+
+	var version atomic.Value
+	version.Store("v1")
+	integration := &Integration{
+		Name: "events_test",
+		IFrame: func(c *gin.Context) {
+			c.Data(http.StatusOK, "text/html", []byte(version.Load().(string)+" "+c.Query("theme")))
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/events", EventsHandler(integration))
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/events?theme=dark")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if contentType := response.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("expected content type text/event-stream, got %s", contentType)
+	}
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(response.Body)
+		for scanner.Scan() {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data:"); ok {
+				lines <- data
+			}
+		}
+	}()
+	next := func() string {
+		select {
+		case line := <-lines:
+			return line
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for event")
+			return ""
+		}
+	}
+
+	if data := next(); data != `"v1 dark"` {
+		t.Fatalf("expected first update with the iFrame HTML, got %s", data)
+	}
+
+	version.Store("v2")
+	InvalidateCache(integration.Name)
+	if data := next(); data != `"v2 dark"` {
+		t.Fatalf("expected update after the cache changed, got %s", data)
+	}
+}
@@ -0,0 +1,182 @@
+package sources
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/metrics"
+)
+
+// NotifiersHTTPClient is the name of the HTTP clients of the notifiers, used with the notifier name as the
+// instance in HTTPClient. They use the global HTTP configs, and are created again when they change.
+const NotifiersHTTPClient = "notify"
+
+var (
+	httpClients   = map[string]*http.Client{}
+	httpClientsMu sync.Mutex
+)
+
+// HTTPClient returns the HTTP client that should be used to request a source instance.
+// It uses the global HTTP configs (HTTP_TIMEOUT, HTTP_CA_FILE, etc.) with the source overrides, like RADARR_TIMEOUT.
+// Sources without a config schema, like Cinemark, use only the global configs.
+func HTTPClient(name, instance string) *http.Client {
+	key := name + ":" + instance
+	httpClientsMu.Lock()
+	defer httpClientsMu.Unlock()
+
+	if client, ok := httpClients[key]; ok {
+		return client
+	}
+
+	client, err := NewHTTPClient(config.Current().SourceHTTP(name, instance))
+	if err != nil {
+		// The CA file was validated when the configs were loaded, so it's
+		// very unlikely to fail here. The request will fail with the error.
+		return &http.Client{Transport: errorTransport{err}}
+	}
+	client.Transport = &metricsTransport{next: client.Transport, source: name, instance: instance}
+	httpClients[key] = client
+
+	return client
+}
+
+// resetHTTPClients removes the cached HTTP clients of a source, so they are created again with the current configs
+func resetHTTPClients(name string) {
+	httpClientsMu.Lock()
+	defer httpClientsMu.Unlock()
+
+	for key := range httpClients {
+		if strings.HasPrefix(key, name+":") {
+			delete(httpClients, key)
+		}
+	}
+}
+
+// NewHTTPClient returns a new HTTP client using the HTTP configs
+func NewHTTPClient(httpConfigs config.HTTPConfigs) (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: httpConfigs.InsecureSkipVerify,
+	}
+	if httpConfigs.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := os.ReadFile(httpConfigs.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA file: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", httpConfigs.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	if httpConfigs.ProxyURL != nil {
+		transport.Proxy = http.ProxyURL(httpConfigs.ProxyURL)
+	}
+
+	return &http.Client{
+		Timeout: httpConfigs.Timeout,
+		Transport: &retryTransport{
+			next:    transport,
+			retries: httpConfigs.Retries,
+			backoff: httpConfigs.RetryBackoff,
+		},
+	}, nil
+}
+
+// noRetriesKey is the context key of the requests that shouldn't be retried, see withoutRetries
+type noRetriesKey struct{}
+
+// withoutRetries returns a context whose requests are sent only once by the HTTP clients,
+// like the pings that measure the latency of a single request
+func withoutRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetriesKey{}, true)
+}
+
+// retryTransport retries idempotent requests (GET and HEAD) after
+// network errors and 5xx/429 responses, with an exponential backoff.
+type retryTransport struct {
+	next    http.RoundTripper
+	retries int
+	backoff time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if (req.Method != http.MethodGet && req.Method != http.MethodHead) || req.Context().Value(noRetriesKey{}) != nil {
+		return t.next.RoundTrip(req)
+	}
+
+	backoff := t.backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= t.retries || !shouldRetry(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// metricsTransport records the request count, errors, and duration of the requests to a source instance
+type metricsTransport struct {
+	next     http.RoundTripper
+	source   string
+	instance string
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	metrics.SourceRequestDuration.WithLabelValues(t.source, t.instance).Observe(time.Since(start).Seconds())
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	metrics.SourceRequests.WithLabelValues(t.source, t.instance, code).Inc()
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		metrics.SourceRequestErrors.WithLabelValues(t.source, t.instance).Inc()
+	}
+	slog.Debug("source request", "source", t.source, "instance", t.instance, "method", req.Method,
+		"url", req.URL.String(), "status", code, "duration", time.Since(start))
+
+	return resp, err
+}
+
+type errorTransport struct {
+	err error
+}
+
+func (t errorTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
@@ -0,0 +1,136 @@
+package sources
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/metrics"
+)
+
+func TestHTTPClientRetries(t *testing.T) {
+	tests := []struct {
+		name             string
+		method           string
+		retries          int
+		failures         int
+		expectedStatus   int
+		expectedRequests int
+	}{
+		{
+			name:             "GET retried until success",
+			method:           http.MethodGet,
+			retries:          2,
+			failures:         2,
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 3,
+		},
+		{
+			name:             "GET retries exhausted",
+			method:           http.MethodGet,
+			retries:          1,
+			failures:         5,
+			expectedStatus:   http.StatusServiceUnavailable,
+			expectedRequests: 2,
+		},
+		{
+			name:             "POST not retried",
+			method:           http.MethodPost,
+			retries:          2,
+			failures:         1,
+			expectedStatus:   http.StatusServiceUnavailable,
+			expectedRequests: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests <= test.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, err := NewHTTPClient(config.HTTPConfigs{
+				Timeout:      5 * time.Second,
+				Retries:      test.retries,
+				RetryBackoff: time.Millisecond,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req, err := http.NewRequest(test.method, server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != test.expectedStatus {
+				t.Errorf("Expected status %d but got %d", test.expectedStatus, resp.StatusCode)
+			}
+			if requests != test.expectedRequests {
+				t.Errorf("Expected %d requests but got %d", test.expectedRequests, requests)
+			}
+		})
+	}
+}
+
+func TestHTTPClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client, err := NewHTTPClient(config.HTTPConfigs{Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Get(server.URL)
+	if err == nil {
+		t.Fatal("Expected a timeout error")
+	}
+}
+
+func TestHTTPClientMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config.Set(&config.Configs{})
+	defer config.Set(nil)
+	resetHTTPClients("metricstest")
+	client := HTTPClient("metricstest", "4k")
+
+	for _, path := range []string{"/", "/", "/missing"} {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	if count := testutil.ToFloat64(metrics.SourceRequests.WithLabelValues("metricstest", "4k", "200")); count != 2 {
+		t.Errorf("expected 2 successful requests, got %v", count)
+	}
+	if count := testutil.ToFloat64(metrics.SourceRequestErrors.WithLabelValues("metricstest", "4k")); count != 1 {
+		t.Errorf("expected 1 error, got %v", count)
+	}
+}
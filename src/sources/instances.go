@@ -0,0 +1,71 @@
+package sources
+
+import "sync"
+
+var (
+	// instancesBySource are the client caches created with NewInstances, removed when the configs of the source change
+	instancesBySource   = map[string][]resetter{}
+	instancesBySourceMu sync.Mutex
+)
+
+type resetter interface {
+	reset()
+}
+
+// Instances caches the clients of a source by instance name.
+// The default instance is "". The zero value is ready to use, but use
+// NewInstances so the clients are created again when the configs are reloaded.
+type Instances[T any] struct {
+	mu      sync.Mutex
+	clients map[string]T
+}
+
+// NewInstances returns a client cache of a source that is cleared when the configs of the source are reloaded
+func NewInstances[T any](name string) *Instances[T] {
+	instances := &Instances[T]{}
+
+	instancesBySourceMu.Lock()
+	defer instancesBySourceMu.Unlock()
+	instancesBySource[name] = append(instancesBySource[name], instances)
+
+	return instances
+}
+
+// Get returns the client of an instance, creating it with newClient if it isn't cached yet.
+// The client is only cached if newClient doesn't return an error, so a failed
+// initialization, like a login while the source is down, is tried again on the next call.
+func (i *Instances[T]) Get(instance string, newClient func(instance string) (T, error)) (T, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if client, ok := i.clients[instance]; ok {
+		return client, nil
+	}
+
+	client, err := newClient(instance)
+	if err != nil {
+		return client, err
+	}
+	if i.clients == nil {
+		i.clients = map[string]T{}
+	}
+	i.clients[instance] = client
+
+	return client, nil
+}
+
+// reset removes the cached clients, so they are created again with the current configs
+func (i *Instances[T]) reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.clients = nil
+}
+
+// resetInstances removes the cached clients of a source
+func resetInstances(name string) {
+	instancesBySourceMu.Lock()
+	defer instancesBySourceMu.Unlock()
+	for _, instances := range instancesBySource[name] {
+		instances.reset()
+	}
+}
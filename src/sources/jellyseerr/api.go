@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 	"github.com/diogovalentte/homarr-iframes/src/sources/overseerr"
 )
 
@@ -104,8 +105,27 @@ type getTvResponse struct {
 	ID           int    `json:"id"`
 }
 
+// GetVersion checks if the API key is valid and returns the Jellyseerr version
+func (j *Jellyseerr) GetVersion() (string, error) {
+	var user struct {
+		ID int `json:"id"`
+	}
+	if err := j.baseRequest(http.MethodGet, j.InternalAddress+"/api/v1/auth/me", nil, &user); err != nil {
+		return "", err
+	}
+
+	var status struct {
+		Version string `json:"version"`
+	}
+	if err := j.baseRequest(http.MethodGet, j.InternalAddress+"/api/v1/status", nil, &status); err != nil {
+		return "", err
+	}
+
+	return status.Version, nil
+}
+
 func (j *Jellyseerr) baseRequest(method, url string, body io.Reader, target any) error {
-	client := &http.Client{}
+	client := sources.HTTPClient("jellyseerr", "")
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
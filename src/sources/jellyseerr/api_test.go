@@ -67,6 +67,11 @@ func TestGetMedia(t *testing.T) {
 }
 
 func TestGetMovieTV(t *testing.T) {
+	j, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	t.Run("get movie", func(t *testing.T) {
 		media, err := j.GetMovie(929590) // civil war
 		if err != nil {
@@ -1,13 +1,36 @@
 package jellyseerr
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
-var j *Jellyseerr
+var instances = sources.NewInstances[*Jellyseerr]("jellyseerr")
+
+func init() {
+	sources.Register(sources.Integration{
+		Name:  "jellyseerr",
+		Title: "Jellyseerr",
+		Config: config.Schema{
+			Prefix: "JELLYSEERR",
+			Vars: []config.Var{
+				{Key: "ADDRESS", Required: true, Validate: config.ValidateURL},
+				{Key: "INTERNAL_ADDRESS", Validate: config.ValidateURL},
+				{Key: "API_KEY", Required: true, Secret: true},
+			},
+		},
+		Check: func(string) (string, error) {
+			j, err := New()
+			if err != nil {
+				return "", err
+			}
+
+			return j.GetVersion()
+		},
+	})
+}
 
 type Jellyseerr struct {
 	Address         string
@@ -16,30 +39,24 @@ type Jellyseerr struct {
 }
 
 func New() (*Jellyseerr, error) {
-	if j != nil {
-		return j, nil
-	}
-
-	address := config.GlobalConfigs.Jellyseerr.Address
-	internalAddress := config.GlobalConfigs.Jellyseerr.InternalAddress
-	APIKey := config.GlobalConfigs.Jellyseerr.APIKey
+	return instances.Get("", func(string) (*Jellyseerr, error) {
+		newJ := &Jellyseerr{}
+		err := newJ.Init()
+		if err != nil {
+			return nil, err
+		}
 
-	newJ := &Jellyseerr{}
-	err := newJ.Init(address, internalAddress, APIKey)
-	if err != nil {
-		return nil, err
-	}
-
-	j = newJ
-
-	return j, nil
+		return newJ, nil
+	})
 }
 
 // Init sets the jellyseerr properties from the configs
-func (j *Jellyseerr) Init(address, internalAddress, APIKey string) error {
-	if address == "" || APIKey == "" {
-		return fmt.Errorf("JELLYSEERR_ADDRESS and JELLYSEERR_API_KEY variables should be set")
+func (j *Jellyseerr) Init() error {
+	sourceConfigs, err := config.Current().LoadSource("jellyseerr")
+	if err != nil {
+		return err
 	}
+	address, internalAddress, APIKey := sourceConfigs.Get("ADDRESS"), sourceConfigs.Get("INTERNAL_ADDRESS"), sourceConfigs.Get("API_KEY")
 
 	j.Address = strings.TrimSuffix(address, "/")
 	if internalAddress == "" {
@@ -0,0 +1,37 @@
+package kaizoku
+
+import (
+	"fmt"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// GetIframeAlarms returns an alarm for each Kaizoku queue with failed jobs
+func (k *Kaizoku) GetIframeAlarms() ([]sources.Alarm, error) {
+	queues, err := k.GetQueues()
+	if err != nil {
+		return nil, err
+	}
+
+	var alarms []sources.Alarm
+	for _, queue := range queues {
+		if queue.Counts.Failed < 1 {
+			continue
+		}
+
+		summary := fmt.Sprintf("Queue %s has failed jobs", queue.Name)
+		url := k.Address + "/bull/queues/queue/" + queue.Name + "?status=failed"
+
+		alarms = append(alarms, sources.Alarm{
+			Source:          "Kaizoku",
+			BackgroundColor: "black",
+			Summary:         summary,
+			URL:             url,
+			Status:          "FAILED",
+			Value:           fmt.Sprintf("%d jobs", queue.Counts.Failed),
+			Property:        queue.Name,
+		})
+	}
+
+	return alarms, nil
+}
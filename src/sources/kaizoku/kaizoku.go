@@ -3,39 +3,77 @@ package kaizoku
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
-var k *Kaizoku
+var instances = sources.NewInstances[*Kaizoku]("kaizoku")
+
+func init() {
+	sources.Register(sources.Integration{
+		Name:  "kaizoku",
+		Title: "Kaizoku",
+		Config: config.Schema{
+			Prefix:        "KAIZOKU",
+			MultiInstance: true,
+			Vars: []config.Var{
+				{Key: "ADDRESS", Required: true, Validate: config.ValidateURL},
+				{Key: "INTERNAL_ADDRESS", Validate: config.ValidateURL},
+			},
+		},
+		Alarms: func(instance string, _ url.Values) ([]sources.Alarm, error) {
+			k, err := NewInstance(instance)
+			if err != nil {
+				return nil, err
+			}
+
+			return k.GetIframeAlarms()
+		},
+		Check: func(instance string) (string, error) {
+			k, err := NewInstance(instance)
+			if err != nil {
+				return "", err
+			}
+			_, err = k.GetQueues()
+
+			return "", err
+		},
+	})
+}
 
 type Kaizoku struct {
+	// Instance is the name of the instance, "" for the default instance
+	Instance        string
 	Address         string
 	InternalAddress string
 }
 
 func New() (*Kaizoku, error) {
-	if k != nil {
-		return k, nil
-	}
-
-	newR := &Kaizoku{}
-	err := newR.Init()
-	if err != nil {
-		return nil, err
-	}
+	return NewInstance("")
+}
 
-	k = newR
+// NewInstance returns the client of a named Kaizoku instance, configured with the KAIZOKU_<INSTANCE>_* variables
+func NewInstance(instance string) (*Kaizoku, error) {
+	return instances.Get(instance, func(instance string) (*Kaizoku, error) {
+		newClient := &Kaizoku{Instance: instance}
+		err := newClient.Init()
+		if err != nil {
+			return nil, err
+		}
 
-	return k, nil
+		return newClient, nil
+	})
 }
 
 func (k *Kaizoku) Init() error {
-	address, internalAddress := config.GlobalConfigs.Kaizoku.Address, config.GlobalConfigs.Kaizoku.InternalAddress
-	if address == "" {
-		return fmt.Errorf("KAIZOKU_ADDRESS variable should be set")
+	sourceConfigs, err := config.Current().LoadInstance("kaizoku", k.Instance)
+	if err != nil {
+		return err
 	}
+	address, internalAddress := sourceConfigs.Get("ADDRESS"), sourceConfigs.Get("INTERNAL_ADDRESS")
 
 	k.Address = strings.TrimSuffix(address, "/")
 	if internalAddress == "" {
@@ -50,7 +88,7 @@ func (k *Kaizoku) Init() error {
 func (k *Kaizoku) GetQueues() ([]*Queue, error) {
 	url := fmt.Sprintf("%s/bull/queues/api/queues", k.InternalAddress)
 	var queues getQueuesResponse
-	err := baseRequest(http.MethodGet, url, nil, &queues)
+	err := k.baseRequest(http.MethodGet, url, nil, &queues)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,37 @@
+package kavita
+
+import (
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// GetIframeAlarms returns the Kavita media errors as alarms
+func (k *Kavita) GetIframeAlarms() ([]sources.Alarm, error) {
+	errors, err := k.GetMediaErrors()
+	if err != nil {
+		return nil, err
+	}
+
+	var alarms []sources.Alarm
+	for _, error := range errors {
+		layout := "2006-01-02T15:04:05.9999999"
+		timestamp, err := time.Parse(layout, error.CreatedUTC)
+		if err != nil {
+			return nil, err
+		}
+		timestamp = timestamp.Local()
+		url := k.Address + "/settings#admin-media-issues"
+		alarms = append(alarms, sources.Alarm{
+			Source:            "Kavita",
+			BackgroundImgURL:  BackgroundImgURL,
+			BackgroundImgSize: 100,
+			Summary:           error.Comment,
+			Time:              timestamp,
+			URL:               url,
+			Status:            "ERROR",
+		})
+	}
+
+	return alarms, nil
+}
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
 func (k *Kavita) baseRequest(method, url string, body io.Reader, target any) error {
-	client := &http.Client{}
+	client := sources.HTTPClient("kavita", k.Instance)
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
@@ -61,7 +63,7 @@ func (k *Kavita) Login() error {
 	}
 	payload := bytes.NewReader(jsonData)
 
-	client := &http.Client{}
+	client := sources.HTTPClient("kavita", k.Instance)
 	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/account/login", k.InternalAddress), payload)
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
@@ -110,7 +112,7 @@ func (k *Kavita) RefreshCurrentToken() error {
 	}
 	payload := bytes.NewReader(jsonData)
 
-	client := &http.Client{}
+	client := sources.HTTPClient("kavita", k.Instance)
 	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/account/refresh-token", k.InternalAddress), payload)
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
@@ -2,17 +2,55 @@ package kavita
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
 var (
-	k                *Kavita
+	instances        = sources.NewInstances[*Kavita]("kavita")
 	BackgroundImgURL = "https://avatars.githubusercontent.com/u/75760308"
 )
 
+func init() {
+	sources.Register(sources.Integration{
+		Name:  "kavita",
+		Title: "Kavita",
+		Config: config.Schema{
+			Prefix:        "KAVITA",
+			MultiInstance: true,
+			Vars: []config.Var{
+				{Key: "ADDRESS", Required: true, Validate: config.ValidateURL},
+				{Key: "INTERNAL_ADDRESS", Validate: config.ValidateURL},
+				{Key: "USERNAME", Required: true},
+				{Key: "PASSWORD", Required: true, Secret: true},
+			},
+		},
+		Alarms: func(instance string, _ url.Values) ([]sources.Alarm, error) {
+			k, err := NewInstance(instance)
+			if err != nil {
+				return nil, err
+			}
+
+			return k.GetIframeAlarms()
+		},
+		Check: func(instance string) (string, error) {
+			k, err := NewInstance(instance)
+			if err != nil {
+				return "", err
+			}
+			_, err = k.GetMediaErrors()
+
+			return "", err
+		},
+	})
+}
+
 type Kavita struct {
+	// Instance is the name of the instance, "" for the default instance
+	Instance        string
 	Address         string
 	InternalAddress string
 	Username        string
@@ -22,26 +60,28 @@ type Kavita struct {
 }
 
 func New() (*Kavita, error) {
-	if k != nil {
-		return k, nil
-	}
-
-	newR := &Kavita{}
-	err := newR.Init()
-	if err != nil {
-		return nil, err
-	}
-
-	k = newR
+	return NewInstance("")
+}
 
-	return k, nil
+// NewInstance returns the client of a named Kavita instance, configured with the KAVITA_<INSTANCE>_* variables
+func NewInstance(instance string) (*Kavita, error) {
+	return instances.Get(instance, func(instance string) (*Kavita, error) {
+		newClient := &Kavita{Instance: instance}
+		err := newClient.Init()
+		if err != nil {
+			return nil, err
+		}
+
+		return newClient, nil
+	})
 }
 
 func (k *Kavita) Init() error {
-	address, internalAddress, username, password := config.GlobalConfigs.Kavita.Address, config.GlobalConfigs.Kavita.InternalAddress, config.GlobalConfigs.Kavita.Username, config.GlobalConfigs.Kavita.Password
-	if address == "" || username == "" || password == "" {
-		return fmt.Errorf("KAVITA_ADDRESS, KAVITA_USERNAME and KAVITA_PASSWORD variables should be set")
+	sourceConfigs, err := config.Current().LoadInstance("kavita", k.Instance)
+	if err != nil {
+		return err
 	}
+	address, internalAddress, username, password := sourceConfigs.Get("ADDRESS"), sourceConfigs.Get("INTERNAL_ADDRESS"), sourceConfigs.Get("USERNAME"), sourceConfigs.Get("PASSWORD")
 
 	k.Address = strings.TrimSuffix(address, "/")
 	if internalAddress == "" {
@@ -52,7 +92,7 @@ func (k *Kavita) Init() error {
 	k.Username = username
 	k.Password = password
 
-	err := k.Login()
+	err = k.Login()
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,35 @@
+package lidarr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// GetIframeAlarms returns the Lidarr health issues as alarms
+func (l *Lidarr) GetIframeAlarms() ([]sources.Alarm, error) {
+	healthEntries, err := l.GetHealth()
+	if err != nil {
+		return nil, err
+	}
+
+	var alarms []sources.Alarm
+	for _, alarm := range healthEntries {
+		url := fmt.Sprintf("%s/system/status", l.Address)
+		if alarm.WikiURL != "" {
+			url = alarm.WikiURL
+		}
+		alarms = append(alarms, sources.Alarm{
+			Source:            "Lidarr",
+			BackgroundImgURL:  BackgroundImageURL,
+			BackgroundImgSize: 120,
+			Summary:           alarm.Message,
+			URL:               url,
+			Status:            strings.ToUpper(alarm.Type),
+			Property:          alarm.Source,
+		})
+	}
+
+	return alarms, nil
+}
@@ -2,45 +2,83 @@ package lidarr
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 	"github.com/diogovalentte/homarr-iframes/src/sources/radarr"
 )
 
 var (
-	l                  *Lidarr
+	instances          = sources.NewInstances[*Lidarr]("lidarr")
 	BackgroundImageURL = "https://avatars.githubusercontent.com/u/28475832"
 )
 
+func init() {
+	sources.Register(sources.Integration{
+		Name:  "lidarr",
+		Title: "Lidarr",
+		Config: config.Schema{
+			Prefix:        "LIDARR",
+			MultiInstance: true,
+			Vars: []config.Var{
+				{Key: "ADDRESS", Required: true, Validate: config.ValidateURL},
+				{Key: "INTERNAL_ADDRESS", Validate: config.ValidateURL},
+				{Key: "API_KEY", Required: true, Secret: true},
+			},
+		},
+		Alarms: func(instance string, _ url.Values) ([]sources.Alarm, error) {
+			l, err := NewInstance(instance)
+			if err != nil {
+				return nil, err
+			}
+
+			return l.GetIframeAlarms()
+		},
+		Check: func(instance string) (string, error) {
+			l, err := NewInstance(instance)
+			if err != nil {
+				return "", err
+			}
+
+			return l.GetVersion()
+		},
+	})
+}
+
 type Lidarr struct {
+	// Instance is the name of the instance, "" for the default instance
+	Instance        string
 	Address         string
 	InternalAddress string
 	APIKey          string
 }
 
 func New() (*Lidarr, error) {
-	if l != nil {
-		return l, nil
-	}
-
-	newL := &Lidarr{}
-	err := newL.Init()
-	if err != nil {
-		return nil, err
-	}
-
-	l = newL
+	return NewInstance("")
+}
 
-	return l, nil
+// NewInstance returns the client of a named Lidarr instance, configured with the LIDARR_<INSTANCE>_* variables
+func NewInstance(instance string) (*Lidarr, error) {
+	return instances.Get(instance, func(instance string) (*Lidarr, error) {
+		newClient := &Lidarr{Instance: instance}
+		err := newClient.Init()
+		if err != nil {
+			return nil, err
+		}
+
+		return newClient, nil
+	})
 }
 
 func (l *Lidarr) Init() error {
-	address, internalAddress, APIKey := config.GlobalConfigs.Lidarr.Address, config.GlobalConfigs.Lidarr.InternalAddress, config.GlobalConfigs.Lidarr.APIKey
-	if address == "" || APIKey == "" {
-		return fmt.Errorf("LIDARR_ADDRESS and LIDARR_API_KEY variables should be set")
+	sourceConfigs, err := config.Current().LoadInstance("lidarr", l.Instance)
+	if err != nil {
+		return err
 	}
+	address, internalAddress, APIKey := sourceConfigs.Get("ADDRESS"), sourceConfigs.Get("INTERNAL_ADDRESS"), sourceConfigs.Get("API_KEY")
 
 	l.Address = strings.TrimSuffix(address, "/")
 	if internalAddress == "" {
@@ -62,7 +100,7 @@ func (l *Lidarr) GetCalendar(unmonitored bool, startDate, endDate time.Time) ([]
 	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, int(time.Second-time.Nanosecond), endDate.Location())
 
 	var entries []*GetLidarrCalendarEntryResponse
-	err := baseRequest("GET", fmt.Sprintf("%s/api/v1/calendar?start=%s&end=%s&unmonitored=%v&includeArtist=true", l.InternalAddress, startDate.Format("2006-01-02T15:04:05.000Z07:00"), endDate.Format("2006-01-02T15:04:05.000Z07:00"), unmonitored), nil, &entries)
+	err := l.baseRequest("GET", fmt.Sprintf("%s/api/v1/calendar?start=%s&end=%s&unmonitored=%v&includeArtist=true", l.InternalAddress, startDate.Format("2006-01-02T15:04:05.000Z07:00"), endDate.Format("2006-01-02T15:04:05.000Z07:00"), unmonitored), nil, &entries)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +127,7 @@ type GetLidarrCalendarEntryResponse struct {
 
 func (l *Lidarr) GetHealth() ([]*HealthEntry, error) {
 	var entries []*HealthEntry
-	err := baseRequest("GET", fmt.Sprintf("%s/api/v1/health", l.InternalAddress), nil, &entries)
+	err := l.baseRequest("GET", fmt.Sprintf("%s/api/v1/health", l.InternalAddress), nil, &entries)
 	if err != nil {
 		return nil, err
 	}
@@ -103,3 +141,18 @@ type HealthEntry struct {
 	Message string `json:"message"`
 	WikiURL string `json:"wikiUrl"`
 }
+
+// GetVersion returns the Lidarr version
+func (l *Lidarr) GetVersion() (string, error) {
+	var status SystemStatus
+	err := l.baseRequest("GET", fmt.Sprintf("%s/api/v1/system/status", l.InternalAddress), nil, &status)
+	if err != nil {
+		return "", err
+	}
+
+	return status.Version, nil
+}
+
+type SystemStatus struct {
+	Version string `json:"version"`
+}
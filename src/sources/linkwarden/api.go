@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
 func (l *Linkwarden) GetLinks(limit int, collectionID string) ([]*Link, error) {
@@ -45,7 +47,7 @@ func (l *Linkwarden) DeleteLink(linkId string) error {
 }
 
 func (l *Linkwarden) baseRequest(method, url string, body io.Reader, target any) error {
-	client := &http.Client{}
+	client := sources.HTTPClient("linkwarden", "")
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
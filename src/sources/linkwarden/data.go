@@ -0,0 +1,67 @@
+package linkwarden
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// Data is the response of the /v1/data/linkwarden route
+type Data struct {
+	Version int        `json:"version"`
+	Links   []LinkData `json:"links"`
+}
+
+// LinkData is a bookmark in the /v1/data/linkwarden route
+type LinkData struct {
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	URL         string          `json:"url"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Collection  *CollectionData `json:"collection,omitempty"`
+}
+
+// CollectionData is the collection of a bookmark in the /v1/data/linkwarden route
+type CollectionData struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+	URL   string `json:"url"`
+}
+
+// GetData returns the bookmarks as JSON
+func (l *Linkwarden) GetData(c *gin.Context) {
+	links, ok := l.getQueryData(c)
+	if !ok {
+		return
+	}
+
+	response := Data{Version: sources.DataVersion, Links: []LinkData{}}
+	for _, link := range links {
+		linkData := LinkData{
+			ID:        link.ID,
+			Name:      link.Name,
+			URL:       link.URL,
+			CreatedAt: link.CreatedAt,
+		}
+		if link.Description != nil {
+			linkData.Description = *link.Description
+		}
+		if link.CollectionID != nil && link.Collection != nil {
+			linkData.Collection = &CollectionData{
+				ID:    *link.CollectionID,
+				Name:  link.Collection.Name,
+				Color: link.Collection.Color,
+				URL:   l.Address + "/collections/" + strconv.Itoa(*link.CollectionID),
+			}
+		}
+		response.Links = append(response.Links, linkData)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
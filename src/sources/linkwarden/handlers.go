@@ -0,0 +1,95 @@
+package linkwarden
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// @Summary Linkwarden  bookmarks iFrame
+// @Description Returns an iFrame with Linkwarden bookmarks.
+// @Success 200 {string} string "HTML content"
+// @Produce html
+// @Param collectionId query int false "Get bookmarks only from this collection. You can get the collection ID by going to the collection page. The ID should be on the URL. The ID of the default collection **Unorganized** is 1 because the URL is https://domain.com/collections/1." Example(1)
+// @Param theme query string false "Homarr theme, defaults to light. If it's different from your Homarr theme, the background turns white" Example(light)
+// @Param limit query int false "Limits the number of items in the iFrame." Example(5)
+// @Param api_url query string true "API URL used by your browser. Used by the iFrames to receive updates, if there is an update, the iFrame updates the changed items without reloading. If not specified, the iFrames will never try to reload." Example(https://sub.domain.com)
+// @Param background_position query string false "Background position of each bookmark card. Use '%25' in place of '%', like '50%25 47.2%25' to get '50% 47.2%'. Defaults to 50% 47.2%." Example(top)
+// @Param background_size query string false "Background size of each bookmark card. Use '%25' in place of '%'. Defaults to cover." Example(cover)
+// @Param background_filter query string false "Background filter of each bookmark card. Use '%25' in place of '%'. Defaults to brightness(0.3)." Example(blur(5px))
+// @Param showDeleteButton query bool false "Wheter to show a button to delete the bookmarks or not. Defaults to 'false'" Example(true)
+// @Router /iframe/linkwarden [get]
+func iFrameHandler(c *gin.Context) {
+	l, err := New()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	l.GetiFrame(c)
+}
+
+// @Summary Get the hash of the Linkwarden bookmarks
+// @Description Get the hash of the Linkwarden bookmarks. Used by the iFrames to check updates and reload the iframe.
+// @Success 200 {object} sources.HashResponse
+// @Produce json
+// @Param collectionId query int false "Get bookmarks only from this collection. You can get the collection ID by going to the collection page. The ID should be on the URL. The ID of the default collection **Unorganized** is 1 because the URL is https://domain.com/collections/1." Example(1)
+// @Param limit query int false "Limits the number of items in the iFrame." Example(5)
+// @Router /hash/linkwarden [get]
+func hashHandler(c *gin.Context) {
+	l, err := New()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	l.GetHash(c)
+}
+
+// @Summary Get the Linkwarden bookmarks data
+// @Description Get the Linkwarden bookmarks as JSON, in the same order as the iFrame.
+// @Success 200 {object} Data
+// @Produce json
+// @Param collectionId query int false "Get bookmarks only from this collection." Example(1)
+// @Param limit query int false "Limits the number of items." Example(5)
+// @Router /data/linkwarden [get]
+func dataHandler(c *gin.Context) {
+	l, err := New()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	l.GetData(c)
+}
+
+// @Summary Linkwarden delete bookmark
+// @Description Deletes a Linkwarden bookmark. After deleting, the iFrame will reload if the `api_url` query parameter is provided. Requires the action token of the bookmark in the X-Action-Token header (embedded in the iFrame), the ACTIONS_SECRET as a bearer token, or the AUTH_TRUSTED_HEADER.
+// @Success 200 {object} sources.MessageResponse "Bookmark deleted"
+// @Failure 401 {object} sources.MessageResponse "Unauthorized"
+// @Failure 403 {object} sources.MessageResponse "Origin not allowed"
+// @Produce json
+// @Param linkId query int true "The bookmark ID to delete." Example(1)
+// @Param X-Action-Token header string false "Action token of the bookmark."
+// @Param Authorization header string false "Bearer ACTIONS_SECRET."
+// @Router /iframe/linkwarden/delete_link [delete]
+func deleteLinkHandler(c *gin.Context) {
+	linkID := c.Query("linkId")
+	if linkID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "linkId is required"})
+		return
+	}
+
+	l, err := New()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	err = l.DeleteLink(linkID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	sources.InvalidateCache("linkwarden")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Bookmark deleted"})
+}
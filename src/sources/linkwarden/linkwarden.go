@@ -19,9 +19,40 @@ import (
 
 var (
 	defaultBackgroundImgURL = "https://avatars.githubusercontent.com/u/135248736?s=280&v=4"
-	l                       *Linkwarden
+	instances               = sources.NewInstances[*Linkwarden]("linkwarden")
 )
 
+func init() {
+	sources.Register(sources.Integration{
+		Name:  "linkwarden",
+		Title: "Linkwarden",
+		Config: config.Schema{
+			Prefix: "LINKWARDEN",
+			Vars: []config.Var{
+				{Key: "ADDRESS", Required: true, Validate: config.ValidateURL},
+				{Key: "INTERNAL_ADDRESS", Validate: config.ValidateURL},
+				{Key: "TOKEN", Required: true, Secret: true},
+				{Key: "BACKGROUND_IMG_URL", Default: defaultBackgroundImgURL, Validate: config.ValidateURL},
+			},
+		},
+		IFrame: iFrameHandler,
+		Hash:   hashHandler,
+		Data:   dataHandler,
+		Check: func(string) (string, error) {
+			l, err := New()
+			if err != nil {
+				return "", err
+			}
+			_, err = l.GetLinks(1, "")
+
+			return "", err
+		},
+		Actions: []sources.Action{
+			{Method: http.MethodDelete, Path: "delete_link", Item: "linkId", Handler: deleteLinkHandler},
+		},
+	})
+}
+
 type Linkwarden struct {
 	Address          string
 	InternalAddress  string
@@ -30,33 +61,23 @@ type Linkwarden struct {
 }
 
 func New() (*Linkwarden, error) {
-	if l != nil {
-		return l, nil
-	}
-
-	address := config.GlobalConfigs.Linkwarden.Address
-	internalAddress := config.GlobalConfigs.Linkwarden.InternalAddress
-	token := config.GlobalConfigs.Linkwarden.Token
-	backgroundImgURL := config.GlobalConfigs.Vikunja.BackgroundImgURL
-	if backgroundImgURL == "" {
-		backgroundImgURL = defaultBackgroundImgURL
-	}
-
-	newL := &Linkwarden{}
-	err := newL.Init(address, internalAddress, token, backgroundImgURL)
-	if err != nil {
-		return nil, err
-	}
-
-	l = newL
+	return instances.Get("", func(string) (*Linkwarden, error) {
+		newL := &Linkwarden{}
+		err := newL.Init()
+		if err != nil {
+			return nil, err
+		}
 
-	return l, nil
+		return newL, nil
+	})
 }
 
-func (l *Linkwarden) Init(address, internalAddress, token, backgroundImgURL string) error {
-	if address == "" || token == "" {
-		return fmt.Errorf("LINKWARDEN_ADDRESS and LINKWARDEN_TOKEN variables should be set")
+func (l *Linkwarden) Init() error {
+	sourceConfigs, err := config.Current().LoadSource("linkwarden")
+	if err != nil {
+		return err
 	}
+	address, internalAddress, token := sourceConfigs.Get("ADDRESS"), sourceConfigs.Get("INTERNAL_ADDRESS"), sourceConfigs.Get("TOKEN")
 
 	l.Address = strings.TrimSuffix(address, "/")
 	if internalAddress == "" {
@@ -65,7 +86,7 @@ func (l *Linkwarden) Init(address, internalAddress, token, backgroundImgURL stri
 		l.InternalAddress = strings.TrimSuffix(internalAddress, "/")
 	}
 	l.Token = token
-	l.BackgroundImgURL = backgroundImgURL
+	l.BackgroundImgURL = sourceConfigs.Get("BACKGROUND_IMG_URL")
 
 	return nil
 }
@@ -121,7 +142,7 @@ func (l *Linkwarden) GetiFrame(c *gin.Context) {
 		}
 	}
 
-	links, err := l.GetLinks(limit, collectionID)
+	links, err := l.getLinks(c.Request.URL.Query(), limit, collectionID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Errorf("couldn't get links: %s", err.Error()).Error()})
 		return
@@ -129,13 +150,9 @@ func (l *Linkwarden) GetiFrame(c *gin.Context) {
 
 	var html []byte
 	if len(links) < 1 {
-		var apiURLPath string
-		if apiURL != "" {
-			apiURLPath = apiURL + "/v1/hash/linkwarden?limit=" + strconv.Itoa(limit) + "&collectionId=" + collectionID
-		}
-		html = sources.GetBaseNothingToShowiFrame(theme, l.BackgroundImgURL, "center", "cover", backgroundFilter, apiURLPath)
+		html = sources.GetBaseNothingToShowiFrame(theme, l.BackgroundImgURL, "center", "cover", backgroundFilter, sources.LiveUpdatesScript(apiURL, "linkwarden"))
 	} else {
-		html, err = l.getLinksiFrame(links, theme, l.BackgroundImgURL, backgroundPosition, backgroundSize, backgroundFilter, apiURL, collectionID, showDeleteButton, limit)
+		html, err = l.getLinksiFrame(links, sources.NewActionSession(c.Request), theme, l.BackgroundImgURL, backgroundPosition, backgroundSize, backgroundFilter, apiURL, showDeleteButton)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Errorf("couldn't create HTML code: %s", err.Error()).Error()})
 			return
@@ -145,7 +162,7 @@ func (l *Linkwarden) GetiFrame(c *gin.Context) {
 	c.Data(http.StatusOK, "text/html", []byte(html))
 }
 
-func (l *Linkwarden) getLinksiFrame(links []*Link, theme, backgroundImgURL, backgroundPosition, backgroundSize, backgroundFilter, apiURL, collectionID string, showDeleteButton bool, limit int) ([]byte, error) {
+func (l *Linkwarden) getLinksiFrame(links []*Link, actions sources.ActionSession, theme, backgroundImgURL, backgroundPosition, backgroundSize, backgroundFilter, apiURL string, showDeleteButton bool) ([]byte, error) {
 	html := `
 <!doctype html>
 <html lang="en">
@@ -279,12 +296,13 @@ func (l *Linkwarden) getLinksiFrame(links []*Link, theme, backgroundImgURL, back
     </style>
 
 	<script>
-      function deleteLink(linkID) {
+      function deleteLink(linkID, actionToken) {
         try {
             var xhr = new XMLHttpRequest();
             var url = '{{ .APIURL }}/v1/iframe/linkwarden/delete_link?linkId=' + linkID;
             xhr.open('DELETE', url, true);
             xhr.setRequestHeader('Content-Type', 'application/json');
+            xhr.setRequestHeader('X-Action-Token', actionToken);
 
             xhr.onload = function () {
               if (xhr.status >= 200 && xhr.status < 300) {
@@ -316,38 +334,12 @@ func (l *Linkwarden) getLinksiFrame(links []*Link, theme, backgroundImgURL, back
       }
 	</script>
 
+    {{ .LiveUpdates }}
+    {{ if .ReloadAfter }}
     <script>
-        let lastHash = null;
-
-        async function fetchData() {
-            try {
-                var url = '{{ .APIURL }}/v1/hash/linkwarden?limit={{ .APILimit }}&collectionId={{ .CollectionID }}';
-                const response = await fetch(url);
-                const data = await response.json();
-
-                if (lastHash === null) {
-                    lastHash = data.hash;
-                } else {
-                    if (data.hash !== lastHash) {
-                        lastHash = data.hash;
-                        location.reload();
-                    }
-                }
-            } catch (error) {
-                console.error('Error getting last update from the API:', error);
-            }
-        }
-
-        function fetchAndUpdate() {
-            fetchData();
-            setTimeout(fetchAndUpdate, 10000); // 10 seconds
-        }
-
-        {{ if .APIURL }}
-            fetchAndUpdate();
-        {{ end }}
-        
+        setTimeout(function () { location.reload(); }, {{ .ReloadAfter }});
     </script>
+    {{ end }}
 
 </head>
 <body>
@@ -381,7 +373,7 @@ func (l *Linkwarden) getLinksiFrame(links []*Link, theme, backgroundImgURL, back
         </div>
 		{{ with . }}{{ if $.ShowDeleteButton }}
 			<div class="delete-button-container">
-				<button id="link-{{ .ID }}" onclick="deleteLink('{{ .ID }}')" class="delete-link-button" onmouseenter="this.style.cursor='pointer';">Delete</button>
+				<button id="link-{{ .ID }}" onclick="deleteLink('{{ .ID }}', '{{ getActionToken .ID }}')" class="delete-link-button" onmouseenter="this.style.cursor='pointer';">Delete</button>
 			</div>
 		{{ end }}{{ end }}
     </div>
@@ -401,7 +393,7 @@ func (l *Linkwarden) getLinksiFrame(links []*Link, theme, backgroundImgURL, back
 		Links:                         links,
 		Theme:                         theme,
 		APIURL:                        apiURL,
-		APILimit:                      limit,
+		LiveUpdates:                   sources.LiveUpdatesScript(apiURL, "linkwarden"),
 		LinkwardenAddress:             l.Address,
 		BackgroundImageURL:            backgroundImgURL,
 		BackgroundPosition:            template.CSS(backgroundPosition),
@@ -409,11 +401,17 @@ func (l *Linkwarden) getLinksiFrame(links []*Link, theme, backgroundImgURL, back
 		BackgroundFilter:              template.CSS(backgroundFilter),
 		ScrollbarThumbBackgroundColor: scrollbarThumbBackgroundColor,
 		ScrollbarTrackBackgroundColor: scrollbarTrackBackgroundColor,
-		CollectionID:                  collectionID,
 		ShowDeleteButton:              showDeleteButton,
 	}
+	if showDeleteButton && apiURL == "" {
+		// Without the live updates, the page is reloaded to get new action tokens before they expire
+		templateData.ReloadAfter = (sources.ActionTokenTTL - time.Minute).Milliseconds()
+	}
 
 	templateFuncs := template.FuncMap{
+		"getActionToken": func(linkID int) string {
+			return actions.Token("linkwarden", "delete_link", strconv.Itoa(linkID))
+		},
 		// Convert uppercase letter to lowercase and add a dash before it,
 		// except for the first letter
 		"getCollectionIcon": func(icon string) string {
@@ -452,6 +450,7 @@ func (l *Linkwarden) getLinksiFrame(links []*Link, theme, backgroundImgURL, back
 type iframeTemplateData struct {
 	Theme                         string
 	APIURL                        string
+	LiveUpdates                   template.HTML
 	LinkwardenAddress             string
 	BackgroundImageURL            string
 	BackgroundPosition            template.CSS
@@ -459,14 +458,37 @@ type iframeTemplateData struct {
 	BackgroundFilter              template.CSS
 	ScrollbarThumbBackgroundColor string
 	ScrollbarTrackBackgroundColor string
-	CollectionID                  string
 	Links                         []*Link
-	APILimit                      int
 	ShowDeleteButton              bool
+	// ReloadAfter is the milliseconds after which the page is reloaded, 0 to not reload
+	ReloadAfter int64
 }
 
 // GetHash returns the hash of the bookmarks
 func (l *Linkwarden) GetHash(c *gin.Context) {
+	pLinks, ok := l.getQueryData(c)
+	if !ok {
+		return
+	}
+
+	var links []Link
+	for _, pLink := range pLinks {
+		// Copy the link, the cached links are also used by the iFrame
+		link := *pLink
+		link.Description = nil
+		link.CollectionID = nil
+		link.Collection = nil
+		links = append(links, link)
+	}
+
+	hash := sources.GetHash(links, time.Now().Format("2006-01-02"))
+
+	c.JSON(http.StatusOK, gin.H{"hash": fmt.Sprintf("%x", hash)})
+}
+
+// getQueryData parses the query parameters used by the hash and data routes and returns the links.
+// If it fails, it writes the error response and returns false.
+func (l *Linkwarden) getQueryData(c *gin.Context) ([]*Link, bool) {
 	queryLimit := c.Query("limit")
 	var limit int
 	var err error
@@ -476,27 +498,26 @@ func (l *Linkwarden) GetHash(c *gin.Context) {
 		limit, err = strconv.Atoi(queryLimit)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"message": "limit must be a number"})
-			return
+			return nil, false
 		}
 	}
 
 	collectionID := c.Query("collectionId")
 
-	pLinks, err := l.GetLinks(limit, collectionID)
+	pLinks, err := l.getLinks(c.Request.URL.Query(), limit, collectionID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Errorf("couldn't get links: %s", err.Error()).Error()})
-		return
-	}
-
-	var links []Link
-	for _, link := range pLinks {
-		link.Description = nil
-		link.CollectionID = nil
-		link.Collection = nil
-		links = append(links, *link)
+		return nil, false
 	}
 
-	hash := sources.GetHash(links, time.Now().Format("2006-01-02"))
+	return pLinks, true
+}
 
-	c.JSON(http.StatusOK, gin.H{"hash": fmt.Sprintf("%x", hash)})
+// getLinks returns the links used by the iFrame and hash routes.
+// The links are cached by the query parameters that change them.
+func (l *Linkwarden) getLinks(query url.Values, limit int, collectionID string) ([]*Link, error) {
+	key := sources.CacheKey("linkwarden", query, "limit", "collectionId")
+	return sources.Cached(key, func() ([]*Link, error) {
+		return l.GetLinks(limit, collectionID)
+	})
 }
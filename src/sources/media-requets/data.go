@@ -0,0 +1,88 @@
+package mediarequets
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// Data is the response of the /v1/data/media_requests route
+type Data struct {
+	Version  int           `json:"version"`
+	Requests []RequestData `json:"requests"`
+}
+
+// RequestData is a media request in the /v1/data/media_requests route
+type RequestData struct {
+	// Status is like "Pending", "Available", or "Processing"
+	Status  string           `json:"status"`
+	Media   RequestMediaData `json:"media"`
+	Request RequestUserData  `json:"request"`
+}
+
+// RequestMediaData is the requested media in the /v1/data/media_requests route
+type RequestMediaData struct {
+	Name string `json:"name"`
+	// Type is "movie" or "tv"
+	Type        string `json:"type"`
+	Year        string `json:"year"`
+	URL         string `json:"url"`
+	PosterURL   string `json:"poster_url"`
+	BackdropURL string `json:"backdrop_url"`
+	TMDBID      int    `json:"tmdb_id"`
+}
+
+// RequestUserData is the user who requested the media in the /v1/data/media_requests route.
+// It's empty when showMedia is true.
+type RequestUserData struct {
+	UserID         int    `json:"user_id,omitempty"`
+	Username       string `json:"username,omitempty"`
+	AvatarURL      string `json:"avatar_url,omitempty"`
+	UserProfileURL string `json:"user_profile_url,omitempty"`
+}
+
+// GetData returns the media requests as JSON
+//
+// @Summary Get the media requests data
+// @Description Get the Overseerr/Jellyseerr media requests as JSON, in the same order as the iFrame.
+// @Success 200 {object} Data
+// @Produce json
+// @Param limit query int false "Limits the number of items." Example(5)
+// @Param filter query string false "Filters for request status and media status. Available values: all, approved, available, pending, processing, unavailable, failed, deleted, completed, allavaliable (showMedia=true). Defaults to all" Example(all)
+// @Param sort query string false "Available values: added, modified, mediaAdded (showMedia=true). Defaults to added" Example(added)
+// @Param requestedByOverseerr query string false "If specified, only requests from that particular overseerr user ID will be returned." Example(1)
+// @Param requestedByJellyseerr query string false "If specified, only requests from that particular jellyseerr user ID will be returned." Example(1)
+// @Param showMedia query string false "If true, shows the requests' media data, not the requests and media data. Defaults to false." Example(true)
+// @Router /data/media_requests [get]
+func GetData(c *gin.Context) {
+	requests, ok := getQueryData(c)
+	if !ok {
+		return
+	}
+
+	response := Data{Version: sources.DataVersion, Requests: []RequestData{}}
+	for _, request := range requests {
+		response.Requests = append(response.Requests, RequestData{
+			Status: request.Status.Status,
+			Media: RequestMediaData{
+				Name:        request.Media.Name,
+				Type:        request.Media.Type,
+				Year:        request.Media.Year,
+				URL:         request.Media.URL,
+				PosterURL:   request.Media.PosterURL,
+				BackdropURL: request.Media.BackdropURL,
+				TMDBID:      request.Media.TMDBID,
+			},
+			Request: RequestUserData{
+				UserID:         request.Request.UserID,
+				Username:       request.Request.Username,
+				AvatarURL:      request.Request.AvatarURL,
+				UserProfileURL: request.Request.UserProfileURL,
+			},
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
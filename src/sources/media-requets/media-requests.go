@@ -2,22 +2,47 @@ package mediarequets
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"html/template"
 	"net/http"
 	"net/url"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/diogovalentte/homarr-iframes/src/config"
 	"github.com/diogovalentte/homarr-iframes/src/sources"
 	"github.com/diogovalentte/homarr-iframes/src/sources/jellyseerr"
 	"github.com/diogovalentte/homarr-iframes/src/sources/overseerr"
 )
 
+func init() {
+	sources.Register(sources.Integration{
+		Name:   "media_requests",
+		Title:  "Media Requests",
+		IFrame: GetiFrame,
+		Hash:   GetHash,
+		Data:   GetData,
+	})
+}
+
 // GetiFrame returns an HTML/CSS code to be used as an iFrame
+//
+// @Summary Overseerr and Jellyseerr Media Requests
+// @Description Returns an iFrame with Overseerr and Jellyseerr media requests list. Returns all requests if the user's API token has the ADMIN or MANAGE_REQUESTS permissions. Otherwise, only the logged-in user's requests are returned. Using the query argument `showMedia=true` will return the media data instead of the requests and media data. You can combine it with `filter=allavaliable` and `sort=mediaAdded` to show the downloaded media sorted by download date, like the first row in Overseerr/Jellyseerr UI "Recently Added".
+// @Success 200 {string} string "HTML content"
+// @Produce html
+// @Param theme query string false "Homarr theme, defaults to light. If it's different from your Homarr theme, the background turns white" Example(light)
+// @Param api_url query string true "API URL used by your browser. Used by the iFrames to receive updates, if there is an update, the iFrame updates the changed items without reloading. If not specified, the iFrames will never try to reload. Also used by the button to set the task done, if not provided, the button will not appear." Example(https://sub.domain.com)
+// @Param limit query int false "Limits the number of items in the iFrame." Example(5)
+// @Param filter query string false "Filters for request status and media status. Available values: all, approved, available, pending, processing, unavailable, failed, deleted, completed, allavaliable (showMedia=true). Defaults to all" Example(all)
+// @Param sort query string false "Available values: added, modified, mediaAdded (showMedia=true). Defaults to added" Example(added)
+// @Param requestedByOverseerr query string false "If specified, only requests from that particular overseerr user ID will be returned." Example(1)
+// @Param requestedByJellyseerr query string false "If specified, only requests from that particular jellyseerr user ID will be returned." Example(1)
+// @Param showMedia query string false "If true, shows the requests' media data, not the requests and media data. Defaults to false." Example(true)
+// @Router /iframe/media_requests [get]
 func GetiFrame(c *gin.Context) {
 	theme := c.Query("theme")
 	if theme == "" {
@@ -85,14 +110,14 @@ func GetiFrame(c *gin.Context) {
 		}
 	}
 
-	iframeRequestData, err := getIframeData(limit, filter, sort, requestedByOverseerr, requestedByJellyseerr, showMedia)
+	iframeRequestData, err := getCachedIframeData(c.Request.URL.Query(), limit, filter, sort, requestedByOverseerr, requestedByJellyseerr, showMedia)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
 
 	var html []byte
-	html, err = getRequestsiFrame(iframeRequestData, theme, apiURL, limit, filter, sort, requestedByOverseerr, requestedByJellyseerr, showMedia)
+	html, err = getRequestsiFrame(iframeRequestData, theme, apiURL)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, fmt.Errorf("couldn't create HTML code: %s", err.Error()).Error())
 		return
@@ -101,7 +126,7 @@ func GetiFrame(c *gin.Context) {
 	c.Data(http.StatusOK, "text/html", []byte(html))
 }
 
-func getRequestsiFrame(requests []overseerr.IframeRequestData, theme, apiURL string, limit int, filter, sort string, requestedByOverseerr, requestedByJellyseerr int, showMedia bool) ([]byte, error) {
+func getRequestsiFrame(requests []overseerr.IframeRequestData, theme, apiURL string) ([]byte, error) {
 	html := `
 <!doctype html>
 <html lang="en">
@@ -273,37 +298,7 @@ func getRequestsiFrame(requests []overseerr.IframeRequestData, theme, apiURL str
         }
     </style>
 
-    <script>
-        let lastHash = null;
-
-        async function fetchData() {
-            try {
-                var url = '{{ .APIURL }}/v1/hash/media_requests?limit={{ .APILimit }}&filter={{ .APIFilter }}&sort={{ .APISort }}&requestedByOverseerr={{ .APIRequestedByOverseerr }}&requestedByJellyseerr={{ .APIRequestedByJellyseerr }}&showMedia={{ .APIShowMedia }}';
-                const response = await fetch(url);
-                const data = await response.json();
-
-                if (lastHash === null) {
-                    lastHash = data.hash;
-                } else {
-                    if (data.hash !== lastHash) {
-                        lastHash = data.hash;
-                        location.reload();
-                    }
-                }
-            } catch (error) {
-                console.error('Error getting last update from the API:', error);
-            }
-        }
-
-        function fetchAndUpdate() {
-            fetchData();
-            setTimeout(fetchAndUpdate, 10000); // 10 seconds
-        }
-
-        {{ if .APIURL }}
-            fetchAndUpdate();
-        {{ end }}
-    </script>
+    {{ .LiveUpdates }}
 
 </head>
 <body>
@@ -353,13 +348,7 @@ func getRequestsiFrame(requests []overseerr.IframeRequestData, theme, apiURL str
 	templateData := iframeTemplateData{
 		Requests:                      requests,
 		Theme:                         theme,
-		APIURL:                        apiURL,
-		APILimit:                      limit,
-		APIFilter:                     filter,
-		APISort:                       sort,
-		APIRequestedByOverseerr:       requestedByOverseerr,
-		APIShowMedia:                  showMedia,
-		APIRequestedByJellyseerr:      requestedByJellyseerr,
+		LiveUpdates:                   sources.LiveUpdatesScript(apiURL, "media_requests"),
 		ScrollbarThumbBackgroundColor: scrollbarThumbBackgroundColor,
 		ScrollbarTrackBackgroundColor: scrollbarTrackBackgroundColor,
 	}
@@ -377,20 +366,39 @@ func getRequestsiFrame(requests []overseerr.IframeRequestData, theme, apiURL str
 
 type iframeTemplateData struct {
 	Theme                         string
-	APIURL                        string
-	APIFilter                     string
-	APISort                       string
+	LiveUpdates                   template.HTML
 	ScrollbarThumbBackgroundColor string
 	ScrollbarTrackBackgroundColor string
 	Requests                      []overseerr.IframeRequestData
-	APIRequestedByOverseerr       int
-	APIRequestedByJellyseerr      int
-	APILimit                      int
-	APIShowMedia                  bool
 }
 
 // GetHash returns the hash of the requests
+//
+// @Summary Get the hash of media requests
+// @Description Get the hash of the media requests. Used by the iFrames to check updates and reload the iframe.
+// @Success 200 {object} sources.HashResponse
+// @Produce json
+// @Param limit query int false "Limits the number of items in the iFrame." Example(5)
+// @Param filter query string false "Filters for request status and media status. Available values: all, approved, available, pending, processing, unavailable, failed, deleted, completed, allavaliable (showMedia=true). Defaults to all" Example(all)
+// @Param sort query string false "Available values: added, modified, mediaAdded (showMedia=true). Defaults to added" Example(added)
+// @Param requestedByOverseerr query string false "If specified, only requests from that particular overseerr user ID will be returned." Example(1)
+// @Param requestedByJellyseerr query string false "If specified, only requests from that particular jellyseerr user ID will be returned." Example(1)
+// @Param showMedia query string false "If true, shows the requests' media data, not the requests and media data. Defaults to false." Example(true)
+// @Router /hash/media_requests [get]
 func GetHash(c *gin.Context) {
+	iframeRequestData, ok := getQueryData(c)
+	if !ok {
+		return
+	}
+
+	hash := sources.GetHash(iframeRequestData, time.Now().Format("2006-01-02"))
+
+	c.JSON(http.StatusOK, gin.H{"hash": fmt.Sprintf("%x", hash)})
+}
+
+// getQueryData parses the query parameters used by the hash and data routes and returns the requests.
+// If it fails, it writes the error response and returns false.
+func getQueryData(c *gin.Context) ([]overseerr.IframeRequestData, bool) {
 	queryLimit := c.Query("limit")
 	var limit int
 	var err error
@@ -400,7 +408,7 @@ func GetHash(c *gin.Context) {
 		limit, err = strconv.Atoi(queryLimit)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"message": "limit must be a number"})
-			return
+			return nil, false
 		}
 	}
 
@@ -418,7 +426,7 @@ func GetHash(c *gin.Context) {
 		requestedByOverseerr, err = strconv.Atoi(requestedByOverseerrStr)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"message": "requestedByOverseerr must be a number"})
-			return
+			return nil, false
 		}
 	}
 
@@ -427,7 +435,7 @@ func GetHash(c *gin.Context) {
 		requestedByJellyseerr, err = strconv.Atoi(requestedByJeellyseerrStr)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"message": "requestedByJellyseerr must be a number"})
-			return
+			return nil, false
 		}
 	}
 
@@ -436,19 +444,26 @@ func GetHash(c *gin.Context) {
 		showMedia, err = strconv.ParseBool(showMediaStr)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"message": "getMedia must be a boolean"})
-			return
+			return nil, false
 		}
 	}
 
-	iframeRequestData, err := getIframeData(limit, filter, sort, requestedByOverseerr, requestedByJellyseerr, showMedia)
+	iframeRequestData, err := getCachedIframeData(c.Request.URL.Query(), limit, filter, sort, requestedByOverseerr, requestedByJellyseerr, showMedia)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-		return
+		return nil, false
 	}
 
-	hash := sources.GetHash(iframeRequestData, time.Now().Format("2006-01-02"))
+	return iframeRequestData, true
+}
 
-	c.JSON(http.StatusOK, gin.H{"hash": fmt.Sprintf("%x", hash)})
+// getCachedIframeData returns the data used by the iFrame and hash routes.
+// The data is cached by the query parameters that change it.
+func getCachedIframeData(query url.Values, limit int, filter, sort string, requestedByOverseerr, requestedByJellyseerr int, getMedia bool) ([]overseerr.IframeRequestData, error) {
+	key := sources.CacheKey("media_requests", query, "limit", "filter", "sort", "requestedByOverseerr", "requestedByJellyseerr", "showMedia")
+	return sources.Cached(key, func() ([]overseerr.IframeRequestData, error) {
+		return getIframeData(limit, filter, sort, requestedByOverseerr, requestedByJellyseerr, getMedia)
+	})
 }
 
 func getIframeData(limit int, filter, sort string, requestedByOverseerr, requestedByJellyseerr int, getMedia bool) ([]overseerr.IframeRequestData, error) {
@@ -456,7 +471,8 @@ func getIframeData(limit int, filter, sort string, requestedByOverseerr, request
 
 	o, err := overseerr.New()
 	if err != nil {
-		if !strings.Contains(err.Error(), "variables should be set") {
+		var missingVarsErr *config.MissingVarsError
+		if !errors.As(err, &missingVarsErr) {
 			return nil, err
 		}
 	} else {
@@ -468,7 +484,8 @@ func getIframeData(limit int, filter, sort string, requestedByOverseerr, request
 	}
 	j, err := jellyseerr.New()
 	if err != nil {
-		if !strings.Contains(err.Error(), "variables should be set") {
+		var missingVarsErr *config.MissingVarsError
+		if !errors.As(err, &missingVarsErr) {
 			return nil, err
 		}
 	} else {
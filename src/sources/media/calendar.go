@@ -2,6 +2,9 @@ package media
 
 import (
 	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
@@ -10,33 +13,45 @@ import (
 	"github.com/diogovalentte/homarr-iframes/src/sources/sonarr"
 )
 
-func getCalendar(unmonitored, inCinemas, physical, digital bool) (*Calendar, error) {
+// maxCalendarDays is the maximum number of days of the calendar, so the requests to the sources aren't too big
+const maxCalendarDays = 92
+
+// getCalendar returns the media releases from the start date to the end date, both inclusive,
+// sorted by release day. The releases of the same day keep the order of the sources.
+func getCalendar(startDate, endDate time.Time, unmonitored, inCinemas, physical, digital bool) (*Calendar, error) {
 	var isAnySourceValid bool
 	calendar := &Calendar{}
-	startDate := time.Now()
-	endDate := startDate
 
-	if config.GlobalConfigs.Radarr.Address != "" && config.GlobalConfigs.Radarr.APIKey != "" {
+	for _, instance := range config.Current().Instances("radarr") {
+		if _, err := config.Current().LoadInstance("radarr", instance); err != nil {
+			continue
+		}
 		isAnySourceValid = true
-		radarrCalendar, err := getRadarrCalendar(unmonitored, startDate, endDate, inCinemas, physical, digital)
+		radarrCalendar, err := getRadarrCalendar(instance, unmonitored, startDate, endDate, inCinemas, physical, digital)
 		if err != nil {
 			return nil, fmt.Errorf("couldn't create Radarr calendar: %s", err.Error())
 		}
 		calendar.Releases = append(calendar.Releases, radarrCalendar.Releases...)
 	}
 
-	if config.GlobalConfigs.Lidarr.Address != "" && config.GlobalConfigs.Lidarr.APIKey != "" {
+	for _, instance := range config.Current().Instances("lidarr") {
+		if _, err := config.Current().LoadInstance("lidarr", instance); err != nil {
+			continue
+		}
 		isAnySourceValid = true
-		lidarrCalendar, err := getLidarrCalendar(unmonitored, startDate, endDate)
+		lidarrCalendar, err := getLidarrCalendar(instance, unmonitored, startDate, endDate)
 		if err != nil {
 			return nil, fmt.Errorf("couldn't create Lidarr calendar: %s", err.Error())
 		}
 		calendar.Releases = append(calendar.Releases, lidarrCalendar.Releases...)
 	}
 
-	if config.GlobalConfigs.Sonarr.Address != "" && config.GlobalConfigs.Sonarr.APIKey != "" {
+	for _, instance := range config.Current().Instances("sonarr") {
+		if _, err := config.Current().LoadInstance("sonarr", instance); err != nil {
+			continue
+		}
 		isAnySourceValid = true
-		sonarrCalendar, err := getSonarrCalendar(unmonitored, startDate, endDate)
+		sonarrCalendar, err := getSonarrCalendar(instance, unmonitored, startDate, endDate)
 		if err != nil {
 			return nil, fmt.Errorf("couldn't create Sonarr calendar: %s", err.Error())
 		}
@@ -47,11 +62,82 @@ func getCalendar(unmonitored, inCinemas, physical, digital bool) (*Calendar, err
 		return nil, fmt.Errorf("no valid source found. Please check the docs for what environment variables should be set")
 	}
 
+	sort.SliceStable(calendar.Releases, func(i, j int) bool {
+		return startOfDay(calendar.Releases[i].ReleaseDate).Before(startOfDay(calendar.Releases[j].ReleaseDate))
+	})
+
 	return calendar, nil
 }
 
-func getRadarrCalendar(unmonitored bool, startDate, endDate time.Time, inCinemas, physical, digital bool) (*Calendar, error) {
-	radarrInstance, err := radarr.New()
+// parseDateRange parses the days_before and days_after, or the start and end query parameters,
+// and returns the first and last days of the calendar at midnight. Defaults to today.
+func parseDateRange(query url.Values, now time.Time) (time.Time, time.Time, error) {
+	today := startOfDay(now)
+	daysBefore, daysAfter := query.Get("days_before"), query.Get("days_after")
+	start, end := query.Get("start"), query.Get("end")
+
+	var startDate, endDate time.Time
+	switch {
+	case start != "" || end != "":
+		if daysBefore != "" || daysAfter != "" {
+			return startDate, endDate, fmt.Errorf("start and end can't be used with days_before and days_after")
+		}
+		if start == "" || end == "" {
+			return startDate, endDate, fmt.Errorf("start and end must be set together")
+		}
+		var err error
+		if startDate, err = time.ParseInLocation(time.DateOnly, start, time.Local); err != nil {
+			return startDate, endDate, fmt.Errorf("start must be a date like '2026-10-01'")
+		}
+		if endDate, err = time.ParseInLocation(time.DateOnly, end, time.Local); err != nil {
+			return startDate, endDate, fmt.Errorf("end must be a date like '2026-10-31'")
+		}
+		if endDate.Before(startDate) {
+			return startDate, endDate, fmt.Errorf("end must not be before start")
+		}
+	default:
+		before, err := parseDays(daysBefore, "days_before")
+		if err != nil {
+			return startDate, endDate, err
+		}
+		after, err := parseDays(daysAfter, "days_after")
+		if err != nil {
+			return startDate, endDate, err
+		}
+		startDate, endDate = today.AddDate(0, 0, -before), today.AddDate(0, 0, after)
+	}
+
+	if days := calendarDays(startDate, endDate); days > maxCalendarDays {
+		return startDate, endDate, fmt.Errorf("the calendar can have at most %d days, got %d", maxCalendarDays, days)
+	}
+
+	return startDate, endDate, nil
+}
+
+func parseDays(value, name string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+
+	return days, nil
+}
+
+// calendarDays returns the number of days from the start date to the end date, both inclusive
+func calendarDays(startDate, endDate time.Time) int {
+	// Rounds the hours, as a day can have 23 or 25 hours when the daylight saving time changes
+	return int(endDate.Sub(startDate).Round(24*time.Hour).Hours()/24) + 1
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func getRadarrCalendar(instance string, unmonitored bool, startDate, endDate time.Time, inCinemas, physical, digital bool) (*Calendar, error) {
+	radarrInstance, err := radarr.NewInstance(instance)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't create Radarr client: %s", err.Error())
 	}
@@ -68,6 +154,7 @@ func getRadarrCalendar(unmonitored bool, startDate, endDate time.Time, inCinemas
 	for _, entry := range entries {
 		var shouldBeDownloaded, found bool
 		var releaseDate time.Time
+		var releaseType string
 
 		if inCinemas && entry.InCinemas != "" {
 			releaseDate, err = time.Parse(time.RFC3339, entry.InCinemas)
@@ -76,17 +163,17 @@ func getRadarrCalendar(unmonitored bool, startDate, endDate time.Time, inCinemas
 			}
 			releaseDate = releaseDate.In(time.Local)
 			if IsReleaseDateWithinDateRange(releaseDate, startDate, endDate) {
-				found = true
+				found, releaseType = true, "inCinemas"
 			}
 		}
 		if !found && digital && entry.DigitalRelease != "" {
-			releaseDate, err := time.Parse(time.RFC3339, entry.DigitalRelease)
+			releaseDate, err = time.Parse(time.RFC3339, entry.DigitalRelease)
 			if err != nil {
 				return nil, fmt.Errorf("error parsing movie '%#v' digital release date: %w", entry, err)
 			}
 			releaseDate = releaseDate.In(time.Local)
 			if IsReleaseDateWithinDateRange(releaseDate, startDate, endDate) {
-				found = true
+				found, releaseType = true, "digital"
 			}
 		}
 		if !found && physical && entry.PhysicalRelease != "" {
@@ -96,7 +183,7 @@ func getRadarrCalendar(unmonitored bool, startDate, endDate time.Time, inCinemas
 			}
 			releaseDate = releaseDate.In(time.Local)
 			if IsReleaseDateWithinDateRange(releaseDate, startDate, endDate) {
-				found = true
+				found, releaseType = true, "physical"
 			}
 		}
 		if !found {
@@ -123,20 +210,23 @@ func getRadarrCalendar(unmonitored bool, startDate, endDate time.Time, inCinemas
 		calendar.Releases = append(calendar.Releases, MediaRelease{
 			Title:              entry.OriginalTitle,
 			Source:             "Radarr",
+			Instance:           instance,
+			Address:            radarrInstance.Address,
 			ReleaseDate:        releaseDate,
 			Slug:               entry.TitleSlug,
 			CoverImageURL:      coverImageURL,
 			PosterImageURL:     posterImageURL,
 			IsDownloaded:       entry.HasFile,
 			ShouldBeDownloaded: shouldBeDownloaded,
+			ReleaseType:        releaseType,
 		})
 	}
 
 	return calendar, nil
 }
 
-func getSonarrCalendar(unmonitored bool, startDate, endDate time.Time) (*Calendar, error) {
-	sonarrInstance, err := sonarr.New()
+func getSonarrCalendar(instance string, unmonitored bool, startDate, endDate time.Time) (*Calendar, error) {
+	sonarrInstance, err := sonarr.NewInstance(instance)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't create Sonarr client: %s", err.Error())
 	}
@@ -167,6 +257,8 @@ func getSonarrCalendar(unmonitored bool, startDate, endDate time.Time) (*Calenda
 		calendar.Releases = append(calendar.Releases, MediaRelease{
 			Title:              entry.Series.Title,
 			Source:             "Sonarr",
+			Instance:           instance,
+			Address:            sonarrInstance.Address,
 			ReleaseDate:        airDate,
 			Slug:               entry.Series.TitleSlug,
 			CoverImageURL:      coverImageURL,
@@ -177,10 +269,12 @@ func getSonarrCalendar(unmonitored bool, startDate, endDate time.Time) (*Calenda
 				EpisodeName   string
 				SeasonNumber  int
 				EpisodeNumber int
+				Runtime       int
 			}{
 				SeasonNumber:  entry.SeasonNumber,
 				EpisodeNumber: entry.EpisodeNumber,
 				EpisodeName:   entry.EpisodeTitle,
+				Runtime:       entry.Series.Runtime,
 			},
 		})
 	}
@@ -188,8 +282,8 @@ func getSonarrCalendar(unmonitored bool, startDate, endDate time.Time) (*Calenda
 	return calendar, nil
 }
 
-func getLidarrCalendar(unmonitored bool, startDate, endDate time.Time) (*Calendar, error) {
-	lidarrInstance, err := lidarr.New()
+func getLidarrCalendar(instance string, unmonitored bool, startDate, endDate time.Time) (*Calendar, error) {
+	lidarrInstance, err := lidarr.NewInstance(instance)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't create Lidarr client: %s", err.Error())
 	}
@@ -221,6 +315,8 @@ func getLidarrCalendar(unmonitored bool, startDate, endDate time.Time) (*Calenda
 		calendar.Releases = append(calendar.Releases, MediaRelease{
 			Title:          entry.Title,
 			Source:         "Lidarr",
+			Instance:       instance,
+			Address:        lidarrInstance.Address,
 			ReleaseDate:    airDate,
 			Slug:           entry.ForeignAlbumID,
 			CoverImageURL:  coverImageURL,
@@ -2,8 +2,10 @@ package media
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
 )
@@ -29,8 +31,45 @@ func TestMain(m *testing.M) {
 }
 
 func TestGetCalendar(t *testing.T) {
-	_, err := getCalendar(false, true, true, true)
+	_, err := getCalendar(time.Now(), time.Now(), false, true, true, true)
 	if err != nil {
 		t.Fatalf("error getting calendar: %v", err)
 	}
 }
+
+func TestParseDateRange(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 30, 0, 0, time.Local)
+	tests := []struct {
+		query      url.Values
+		start, end string
+	}{
+		{url.Values{}, "2026-10-18", "2026-10-18"},
+		{url.Values{"days_after": {"6"}}, "2026-10-18", "2026-10-24"},
+		{url.Values{"days_before": {"1"}, "days_after": {"13"}}, "2026-10-17", "2026-10-31"},
+		{url.Values{"start": {"2026-10-01"}, "end": {"2026-10-31"}}, "2026-10-01", "2026-10-31"},
+	}
+	for _, test := range tests {
+		start, end, err := parseDateRange(test.query, now)
+		if err != nil {
+			t.Errorf("parseDateRange(%v): %s", test.query, err)
+			continue
+		}
+		if start.Format(time.DateOnly) != test.start || end.Format(time.DateOnly) != test.end || start.Hour() != 0 {
+			t.Errorf("parseDateRange(%v): expected %s to %s, got %s to %s", test.query, test.start, test.end, start, end)
+		}
+	}
+
+	for _, query := range []url.Values{
+		{"days_after": {"-1"}},
+		{"days_before": {"one"}},
+		{"days_after": {"92"}},
+		{"start": {"2026-10-01"}},
+		{"start": {"2026-10-31"}, "end": {"2026-10-01"}},
+		{"start": {"01/10/2026"}, "end": {"2026-10-31"}},
+		{"start": {"2026-10-01"}, "end": {"2026-10-31"}, "days_after": {"6"}},
+	} {
+		if _, _, err := parseDateRange(query, now); err == nil {
+			t.Errorf("parseDateRange(%v): expected an error", query)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package media
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// Data is the response of the /v1/data/media_releases route
+type Data struct {
+	Version  int           `json:"version"`
+	Releases []ReleaseData `json:"releases"`
+}
+
+// ReleaseData is a media release in the /v1/data/media_releases route
+type ReleaseData struct {
+	ReleaseDate time.Time `json:"release_date"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	// Source is "Radarr", "Sonarr", or "Lidarr"
+	Source             string `json:"source"`
+	Instance           string `json:"instance,omitempty"`
+	PosterImageURL     string `json:"poster_image_url"`
+	CoverImageURL      string `json:"cover_image_url"`
+	IsDownloaded       bool   `json:"is_downloaded"`
+	ShouldBeDownloaded bool   `json:"should_be_downloaded"`
+	// Episode is only set for Sonarr releases
+	Episode *EpisodeData `json:"episode,omitempty"`
+	// Album is only set for Lidarr releases
+	Album *AlbumData `json:"album,omitempty"`
+}
+
+// EpisodeData is the episode of a Sonarr release in the /v1/data/media_releases route
+type EpisodeData struct {
+	Name          string `json:"name"`
+	SeasonNumber  int    `json:"season_number"`
+	EpisodeNumber int    `json:"episode_number"`
+}
+
+// AlbumData is the album of a Lidarr release in the /v1/data/media_releases route
+type AlbumData struct {
+	Type            string `json:"type"`
+	ArtistName      string `json:"artist_name"`
+	ArtistURL       string `json:"artist_url"`
+	TrackFileCount  int    `json:"track_file_count"`
+	TotalTrackCount int    `json:"total_track_count"`
+}
+
+// GetData returns the media releases as JSON
+//
+// @Summary Get the media releases data
+// @Description Get the media releases from Radarr/Sonarr/Lidarr as JSON, in the same order as the iFrame. Defaults to the releases of today.
+// @Success 200 {object} Data
+// @Produce json
+// @Param radarrReleaseType query string false "Filter movies get from Radarr. Can be 'inCinemas', 'physical', 'digital', or multiple separated by comma. Defaults to 'inCinemas,physical,digital'" Example(inCinemas,digital)
+// @Param showUnmonitored query bool false "Specify if show unmonitored media. Defaults to false." Example(true)
+// @Param days_before query int false "Number of days before today to show. Defaults to 0." Example(1)
+// @Param days_after query int false "Number of days after today to show. Defaults to 0." Example(6)
+// @Param start query string false "First day to show, like '2026-10-01'. Must be set with end, and can't be used with days_before and days_after." Example(2026-10-01)
+// @Param end query string false "Last day to show, like '2026-10-31'. The calendar can have at most 92 days." Example(2026-10-31)
+// @Router /data/media_releases [get]
+func GetData(c *gin.Context) {
+	calendar, ok := getQueryData(c)
+	if !ok {
+		return
+	}
+
+	response := Data{Version: sources.DataVersion, Releases: []ReleaseData{}}
+	for _, release := range calendar.Releases {
+		releaseData := ReleaseData{
+			ReleaseDate:        release.ReleaseDate,
+			Title:              release.Title,
+			URL:                release.URL(),
+			Source:             release.Source,
+			Instance:           release.Instance,
+			PosterImageURL:     release.PosterImageURL,
+			CoverImageURL:      release.CoverImageURL,
+			IsDownloaded:       release.IsDownloaded,
+			ShouldBeDownloaded: release.ShouldBeDownloaded,
+		}
+		switch release.Source {
+		case "Sonarr":
+			releaseData.Episode = &EpisodeData{
+				Name:          release.EpisodeDetails.EpisodeName,
+				SeasonNumber:  release.EpisodeDetails.SeasonNumber,
+				EpisodeNumber: release.EpisodeDetails.EpisodeNumber,
+			}
+		case "Lidarr":
+			releaseData.Album = &AlbumData{
+				Type:            release.AlbumType,
+				ArtistName:      release.ArtistDetails.ArtistName,
+				ArtistURL:       release.Address + "/artist/" + release.ArtistDetails.Slug,
+				TrackFileCount:  release.TrackFileCount,
+				TotalTrackCount: release.TotalTrackCount,
+			}
+		}
+		response.Releases = append(response.Releases, releaseData)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
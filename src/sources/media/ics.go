@@ -0,0 +1,192 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// icsDaysBefore and icsDaysAfter are the default date range of the iCalendar feed,
+	// so the calendar apps subscribing to it show the recent and upcoming releases
+	icsDaysBefore = 14
+	icsDaysAfter  = 60
+	// icsEpisodeRuntime is the duration of the episodes whose runtime is unknown
+	icsEpisodeRuntime = 30 * time.Minute
+	icsDateFormat     = "20060102"
+	icsTimeFormat     = "20060102T150405Z"
+	// icsLineLength is the maximum length in octets of an iCalendar line, without the line break
+	icsLineLength = 75
+)
+
+var radarrReleaseTypeNames = map[string]string{
+	"inCinemas": "In Cinemas",
+	"digital":   "Digital Release",
+	"physical":  "Physical Release",
+}
+
+// GetICS returns the media releases as an iCalendar feed
+//
+// @Summary Media releases iCalendar feed
+// @Description Returns the media releases from Radarr/Sonarr/Lidarr as an iCalendar (ICS) feed, to be subscribed by calendar apps like Google Calendar and Nextcloud. Movies and albums are all-day events, and episodes are events at their air time. Defaults to the releases from 14 days before today to 60 days after today.
+// @Success 200 {string} string "iCalendar feed"
+// @Produce text/calendar
+// @Param radarrReleaseType query string false "Filter movies get from Radarr. Can be 'inCinemas', 'physical', 'digital', or multiple separated by comma. Defaults to 'inCinemas,physical,digital'" Example(inCinemas,digital)
+// @Param showUnmonitored query bool false "Specify if show unmonitored media. Defaults to false." Example(true)
+// @Param days_before query int false "Number of days before today to show. Defaults to 14." Example(7)
+// @Param days_after query int false "Number of days after today to show. Defaults to 60." Example(30)
+// @Param start query string false "First day to show, like '2026-10-01'. Must be set with end, and can't be used with days_before and days_after." Example(2026-10-01)
+// @Param end query string false "Last day to show, like '2026-10-31'. The calendar can have at most 92 days." Example(2026-10-31)
+// @Router /calendar/media.ics [get]
+func GetICS(c *gin.Context) {
+	query := c.Request.URL.Query()
+	if query.Get("days_before") == "" && query.Get("days_after") == "" && query.Get("start") == "" && query.Get("end") == "" {
+		query.Set("days_before", strconv.Itoa(icsDaysBefore))
+		query.Set("days_after", strconv.Itoa(icsDaysAfter))
+		c.Request.URL.RawQuery = query.Encode()
+	}
+
+	calendar, ok := getQueryData(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="media.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", renderICS(calendar, time.Now()))
+}
+
+// renderICS returns the calendar as an iCalendar file (RFC 5545) with an event per release
+func renderICS(calendar *Calendar, now time.Time) []byte {
+	var buf bytes.Buffer
+	writeICSLine(&buf, "BEGIN:VCALENDAR")
+	writeICSLine(&buf, "VERSION:2.0")
+	writeICSLine(&buf, "PRODID:-//homarr-iframes//Media Releases//EN")
+	writeICSLine(&buf, "CALSCALE:GREGORIAN")
+	writeICSLine(&buf, "METHOD:PUBLISH")
+	writeICSLine(&buf, "X-WR-CALNAME:Media Releases")
+	writeICSLine(&buf, "REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	writeICSLine(&buf, "X-PUBLISHED-TTL:PT1H")
+
+	stamp := now.UTC().Format(icsTimeFormat)
+	for _, release := range calendar.Releases {
+		writeICSLine(&buf, "BEGIN:VEVENT")
+		writeICSLine(&buf, "UID:"+escapeICSText(icsUID(release)))
+		writeICSLine(&buf, "DTSTAMP:"+stamp)
+		if release.Source == "Sonarr" {
+			runtime := time.Duration(release.EpisodeDetails.Runtime) * time.Minute
+			if runtime <= 0 {
+				runtime = icsEpisodeRuntime
+			}
+			writeICSLine(&buf, "DTSTART:"+release.ReleaseDate.UTC().Format(icsTimeFormat))
+			writeICSLine(&buf, "DTEND:"+release.ReleaseDate.Add(runtime).UTC().Format(icsTimeFormat))
+		} else {
+			// Radarr and Lidarr release dates are days, sent as midnight UTC, so the day is taken in UTC
+			day := release.ReleaseDate.UTC()
+			writeICSLine(&buf, "DTSTART;VALUE=DATE:"+day.Format(icsDateFormat))
+			writeICSLine(&buf, "DTEND;VALUE=DATE:"+day.AddDate(0, 0, 1).Format(icsDateFormat))
+		}
+		writeICSLine(&buf, "SUMMARY:"+escapeICSText(icsSummary(release)))
+		writeICSLine(&buf, "DESCRIPTION:"+escapeICSText(icsDescription(release)))
+		if url := release.URL(); url != "" {
+			writeICSLine(&buf, "URL:"+url)
+		}
+		writeICSLine(&buf, "CATEGORIES:"+escapeICSText(release.Source))
+		writeICSLine(&buf, "TRANSP:TRANSPARENT")
+		writeICSLine(&buf, "END:VEVENT")
+	}
+	writeICSLine(&buf, "END:VCALENDAR")
+
+	return buf.Bytes()
+}
+
+// icsUID returns a unique identifier of a release that doesn't change between requests,
+// so the calendar apps update the events instead of duplicating them
+func icsUID(release MediaRelease) string {
+	instance := release.Instance
+	if instance == "" {
+		instance = "default"
+	}
+	uid := strings.ToLower(release.Source) + "-" + instance + "-" + release.Slug
+	switch release.Source {
+	case "Sonarr":
+		uid += fmt.Sprintf("-s%de%d", release.EpisodeDetails.SeasonNumber, release.EpisodeDetails.EpisodeNumber)
+	case "Radarr":
+		uid += "-" + strings.ToLower(release.ReleaseType)
+	}
+
+	return uid + "@homarr-iframes"
+}
+
+// icsSummary returns the event title of a release, like "Severance S2E1 - Hello, Ms. Cobel" or "Dune (Digital Release)"
+func icsSummary(release MediaRelease) string {
+	switch release.Source {
+	case "Sonarr":
+		summary := fmt.Sprintf("%s S%dE%d", release.Title, release.EpisodeDetails.SeasonNumber, release.EpisodeDetails.EpisodeNumber)
+		if release.EpisodeDetails.EpisodeName != "" {
+			summary += " - " + release.EpisodeDetails.EpisodeName
+		}
+		return summary
+	case "Radarr":
+		if name, ok := radarrReleaseTypeNames[release.ReleaseType]; ok {
+			return release.Title + " (" + name + ")"
+		}
+		return release.Title
+	case "Lidarr":
+		if release.ArtistDetails.ArtistName != "" {
+			return release.ArtistDetails.ArtistName + " - " + release.Title
+		}
+		return release.Title
+	default:
+		return release.Title
+	}
+}
+
+// icsDescription returns the event description of a release, with its source and download status
+func icsDescription(release MediaRelease) string {
+	source := release.Source
+	if release.Instance != "" {
+		source += " (" + release.Instance + ")"
+	}
+
+	var status string
+	switch {
+	case release.Source == "Lidarr":
+		status = fmt.Sprintf("%d/%d tracks downloaded", release.TrackFileCount, release.TotalTrackCount)
+	case release.IsDownloaded:
+		status = "Downloaded"
+	default:
+		status = "Not downloaded"
+	}
+
+	return source + "\n" + status
+}
+
+// escapeICSText escapes a TEXT value of an iCalendar property
+func escapeICSText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(text)
+}
+
+// writeICSLine writes an iCalendar content line, folding it in lines of at most 75 octets
+// without splitting UTF-8 characters. The continuation lines start with a space.
+func writeICSLine(buf *bytes.Buffer, line string) {
+	limit := icsLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// The space at the start of the continuation lines counts in the limit
+		limit = icsLineLength - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
@@ -0,0 +1,42 @@
+package media
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderICS(t *testing.T) {
+	movie := MediaRelease{Title: "Dune", Source: "Radarr", Instance: "4k", Address: "http://radarr", Slug: "dune", ReleaseType: "digital", IsDownloaded: true,
+		ReleaseDate: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC).In(time.Local)}
+	episode := MediaRelease{Title: "Severance", Source: "Sonarr", Address: "http://sonarr", Slug: "severance",
+		ReleaseDate: time.Date(2026, 10, 20, 21, 0, 0, 0, time.FixedZone("BRT", -3*60*60))}
+	episode.EpisodeDetails.SeasonNumber, episode.EpisodeDetails.EpisodeNumber = 2, 1
+	episode.EpisodeDetails.EpisodeName = "Hello, Ms. Cobel; " + strings.Repeat("ação ", 20)
+	album := MediaRelease{Title: "Album", Source: "Lidarr", Address: "http://lidarr", Slug: "abc", TrackFileCount: 3, TotalTrackCount: 10,
+		ReleaseDate: time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC)}
+	album.ArtistDetails.ArtistName = "Artist"
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	ics := string(renderICS(&Calendar{Releases: []MediaRelease{movie, episode, album}}, now))
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		if len(line) > icsLineLength {
+			t.Errorf("line longer than %d octets: %q", icsLineLength, line)
+		}
+	}
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	for _, expected := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"UID:radarr-4k-dune-digital@homarr-iframes\r\nDTSTAMP:20261018T120000Z\r\nDTSTART;VALUE=DATE:20261020\r\nDTEND;VALUE=DATE:20261021\r\nSUMMARY:Dune (Digital Release)\r\nDESCRIPTION:Radarr (4k)\\nDownloaded\r\nURL:http://radarr/movie/dune\r\n",
+		"UID:sonarr-default-severance-s2e1@homarr-iframes\r\nDTSTAMP:20261018T120000Z\r\nDTSTART:20261021T000000Z\r\nDTEND:20261021T003000Z\r\nSUMMARY:Severance S2E1 - Hello\\, Ms. Cobel\\; ação",
+		"DTSTART;VALUE=DATE:20261023\r\nDTEND;VALUE=DATE:20261024\r\nSUMMARY:Artist - Album\r\nDESCRIPTION:Lidarr\\n3/10 tracks downloaded\r\n",
+		"END:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(unfolded, expected) {
+			t.Errorf("expected the calendar to contain %q, got:\n%s", expected, unfolded)
+		}
+	}
+	if strings.Count(ics, "BEGIN:VEVENT") != 3 {
+		t.Errorf("expected 3 events, got:\n%s", ics)
+	}
+}
@@ -11,11 +11,36 @@ import (
 
 	"github.com/gin-gonic/gin"
 
-	"github.com/diogovalentte/homarr-iframes/src/config"
 	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
+func init() {
+	sources.Register(sources.Integration{
+		Name:   "media_releases",
+		Title:  "Media Releases",
+		IFrame: GetiFrame,
+		Hash:   GetHash,
+		Data:   GetData,
+	})
+}
+
 // GetiFrame returns an HTML/CSS code to be used as an iFrame
+//
+// @Summary Media Releases
+// @Description Returns an iFrame with the media releases of today, or of a range of days. The media releases are from Radarr/Sonarr/Lidarr.
+// @Success 200 {string} string "HTML content"
+// @Produce html
+// @Param theme query string false "Homarr theme, defaults to light. If it's different from your Homarr theme, the background turns white" Example(light)
+// @Param api_url query string true "API URL used by your browser. Used by the iFrames to receive updates, if there is an update, the iFrame updates the changed items without reloading. If not specified, the iFrames will never try to reload. Also used by the button to set the task done, if not provided, the button will not appear." Example(https://sub.domain.com)
+// @Param radarrReleaseType query string false "Filter movies get from Radarr. Can be 'inCinemas', 'physical', 'digital', or multiple separated by comma. Defaults to 'inCinemas,physical,digital'" Example(inCinemas,digital)
+// @Param showUnmonitored query bool false "Specify if show unmonitored media. Defaults to false." Example(true)
+// @Param showEpisodesHour query bool false "Specify if show the episodes' (Sonarr) release hour and minute. Defaults to true." Example(false)
+// @Param days_before query int false "Number of days before today to show. Defaults to 0." Example(1)
+// @Param days_after query int false "Number of days after today to show. Defaults to 0." Example(6)
+// @Param start query string false "First day to show, like '2026-10-01'. Must be set with end, and can't be used with days_before and days_after." Example(2026-10-01)
+// @Param end query string false "Last day to show, like '2026-10-31'. The calendar can have at most 92 days." Example(2026-10-31)
+// @Param layout query string false "How to show the releases. Can be 'list' (a card per release), 'agenda' (the cards grouped by day), or 'grid' (a grid of days with the releases' posters, 7 days per row). Defaults to 'list'." Example(grid)
+// @Router /iframe/media_releases [get]
 func GetiFrame(c *gin.Context) {
 	var err error
 	theme := c.Query("theme")
@@ -80,14 +105,30 @@ func GetiFrame(c *gin.Context) {
 		return
 	}
 
-	iframeRequestData, err := getCalendar(showUnmonitored, inCinemas, physical, digital)
+	layout := c.Query("layout")
+	switch layout {
+	case "":
+		layout = "list"
+	case "list", "agenda", "grid":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"message": "layout must be 'list', 'agenda', or 'grid'"})
+		return
+	}
+
+	startDate, endDate, err := parseDateRange(c.Request.URL.Query(), time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	iframeRequestData, err := getCachedCalendar(c.Request.URL.Query(), startDate, endDate, showUnmonitored, inCinemas, physical, digital)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
 
 	var html []byte
-	html, err = getMediaReleasesiFrame(iframeRequestData, theme, apiURL, showUnmonitored, showEpisodeHours, inCinemas, physical, digital)
+	html, err = getMediaReleasesiFrame(iframeRequestData, startDate, endDate, layout, theme, apiURL, showEpisodeHours)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, fmt.Errorf("couldn't create iFrame: %s", err.Error()).Error())
 		return
@@ -96,7 +137,7 @@ func GetiFrame(c *gin.Context) {
 	c.Data(http.StatusOK, "text/html", []byte(html))
 }
 
-func getMediaReleasesiFrame(calendar *Calendar, theme, apiURL string, showUnmonitored, showEpisodeHours, radarrInCinemas, radarrPhysical, radarrDigital bool) ([]byte, error) {
+func getMediaReleasesiFrame(calendar *Calendar, startDate, endDate time.Time, layout, theme, apiURL string, showEpisodeHours bool) ([]byte, error) {
 	html := `
 <!doctype html>
 <html lang="en">
@@ -264,43 +305,92 @@ func getMediaReleasesiFrame(calendar *Calendar, theme, apiURL string, showUnmoni
             border-radius: 1rem;
             margin: 0;
         }
-    </style>
 
-    <script>
-        let lastHash = null;
-
-        async function fetchData() {
-            try {
-                var url = '{{ .APIURL }}/v1/hash/media_releases?showUnmonitored={{ .APIShowUnmonitored }}&radarrReleaseType={{ .APIRadarrReleaseType }}';
-                const response = await fetch(url);
-                const data = await response.json();
-
-                if (lastHash === null) {
-                    lastHash = data.hash;
-                } else {
-                    if (data.hash !== lastHash) {
-                        lastHash = data.hash;
-                        location.reload();
-                    }
-                }
-            } catch (error) {
-                console.error('Error getting last update from the API:', error);
-            }
+        .day-title {
+            font-family: ui-sans-serif, system-ui, -apple-system, BtaskMacSystemFont,
+              Segoe UI, Roboto, Helvetica Neue, Arial, Noto Sans, sans-serif, Apple Color Emoji,
+              Segoe UI Emoji, Segoe UI Symbol, Noto Color Emoji;
+            font-weight: 600;
+            font-size: 0.875rem;
+            line-height: 1.5rem;
+            color: {{ .SecondaryTextColor }};
+            margin: 0;
         }
 
-        function fetchAndUpdate() {
-            fetchData();
-            setTimeout(fetchAndUpdate, 10000); // 10 seconds
+        .day-title.today {
+            color: {{ .TextColor }};
         }
 
-        {{ if .APIURL }}
-            fetchAndUpdate();
-        {{ end }}
-    </script>
+        .agenda-day-title {
+            margin: 12px 8.50px 0 10px;
+        }
+
+        .calendar-grid {
+            display: grid;
+            grid-template-columns: repeat(7, minmax(0, 1fr));
+            gap: 5px;
+            margin: 8.50px;
+        }
+
+        .day-cell {
+            min-height: 100px;
+            padding: 4px;
+            border-radius: 10px;
+            border: 1px solid rgba(56, 58, 64, 1);
+            overflow: hidden;
+        }
+
+        .day-cell.today {
+            border-color: #1c7ed6;
+        }
+
+        .day-posters {
+            display: flex;
+            flex-wrap: wrap;
+            gap: 4px;
+        }
+
+        .day-poster {
+            display: block;
+            width: 36px;
+            height: 54px;
+            object-fit: cover;
+            border-radius: 2px;
+            border: 2px solid;
+        }
+    </style>
+
+    {{ .LiveUpdates }}
 
 </head>
 <body>
-{{ range .Calendar.Releases }}
+{{ if eq .Layout "grid" }}
+    <div class="calendar-grid">
+    {{ range .Days }}
+        <div class="day-cell{{ if .Today }} today{{ end }}">
+            <p class="day-title">{{ .Date.Format "Mon 2" }}</p>
+            <div class="day-posters">
+            {{ range .Releases }}
+                <a href="{{ .URL }}" target="_blank" title="{{ releaseTitle . }}">
+                    <img class="day-poster" src="{{ .PosterImageURL }}" alt="Media Release Poster" style="border-color: {{ getStatusColor . }};" />
+                </a>
+            {{ end }}
+            </div>
+        </div>
+    {{ end }}
+    </div>
+{{ else if eq .Layout "agenda" }}
+    {{ range .Days }}{{ if .Releases }}
+        <p class="day-title agenda-day-title{{ if .Today }} today{{ end }}">{{ .Label }}</p>
+        {{ range .Releases }}{{ template "release" card . }}{{ end }}
+    {{ end }}{{ end }}
+{{ else }}
+    {{ range .Calendar.Releases }}{{ template "release" card . }}{{ end }}
+{{ end }}
+</body>
+</html>
+
+{{ define "release" }}{{ with .Release }}
     <div class="releases-container">
 
         <div class="background-image" style="background-image: url('{{ .CoverImageURL }}');"></div>
@@ -312,26 +402,36 @@ func getMediaReleasesiFrame(calendar *Calendar, theme, apiURL string, showUnmoni
 
         <div class="text-wrap">
             {{ if eq .Source "Sonarr" }}
-                <a href="{{ with . }}{{ $.SonarrAddress }}{{ end }}/series/{{ .Slug }}" target="_blank" class="release-title" title="{{ .Title }}">{{ .Title }}</a>
+                <a href="{{ .URL }}" target="_blank" class="release-title" title="{{ .Title }}">{{ .Title }}</a>
                 <div class="more-info-container">
-                    {{ with . }}{{ if $.ShowEpisodeHours }}
-                        <span class="info-label" style="display: inline-block; min-width: 63.25px;"><i class="fa-solid fa-calendar-days"></i> {{ .ReleaseDate.Format "15h04" }}</span>
-                    {{ end }}{{ end }}
+                    {{ if $.ShowEpisodeHours }}
+                        <span class="info-label" style="display: inline-block; min-width: 63.25px;"><i class="fa-solid fa-calendar-days"></i> {{ if $.ShowDate }}{{ .ReleaseDate.Format "Jan 2" }} {{ end }}{{ .ReleaseDate.Format "15h04" }}</span>
+                    {{ else if $.ShowDate }}
+                        <span class="info-label"><i class="fa-solid fa-calendar-days"></i> {{ .ReleaseDate.Format "Jan 2" }}</span>
+                    {{ end }}
                     <span class="info-label" title="S{{ .EpisodeDetails.SeasonNumber }}E{{ .EpisodeDetails.EpisodeNumber}} - {{ .EpisodeDetails.EpisodeName }}"><i class="fas fa-tv fa-xm"></i> S{{ .EpisodeDetails.SeasonNumber }}E{{ .EpisodeDetails.EpisodeNumber}} - {{ .EpisodeDetails.EpisodeName }}</span>
                 </div>
             {{ else if eq .Source "Radarr" }}
-                <a href="{{ with . }}{{ $.RadarrAddress }}{{ end }}/movie/{{ .Slug }}" target="_blank" class="release-title" title="{{ .Title }}">{{ .Title }}</a>
+                <a href="{{ .URL }}" target="_blank" class="release-title" title="{{ .Title }}">{{ .Title }}</a>
+                {{ if $.ShowDate }}
+                    <div class="more-info-container">
+                        <span class="info-label"><i class="fa-solid fa-calendar-days"></i> {{ .ReleaseDate.Format "Jan 2" }}</span>
+                    </div>
+                {{ end }}
             {{ else if eq .Source "Lidarr" }}
-                <a href="{{ with . }}{{ $.LidarrAddress }}{{ end }}/album/{{ .Slug }}" target="_blank" class="release-title" title="{{ .Title }}">{{ .Title }}</a>
+                <a href="{{ .URL }}" target="_blank" class="release-title" title="{{ .Title }}">{{ .Title }}</a>
                 <div class="more-info-container">
+                    {{ if $.ShowDate }}
+                        <span class="info-label"><i class="fa-solid fa-calendar-days"></i> {{ .ReleaseDate.Format "Jan 2" }}</span>
+                    {{ end }}
                     <span class="info-label"><i class="fa-solid fa-compact-disc"></i> {{ .AlbumType }}</span>
-                    <span class="info-label" title="{{ .ArtistDetails.ArtistName }}"><i class="fa-solid fa-user"></i> <a href="{{ with . }}{{ $.LidarrAddress }}{{ end }}/artist/{{ .ArtistDetails.Slug }}" target="_blank" class="info-label">{{ .ArtistDetails.ArtistName }}</a></span>
+                    <span class="info-label" title="{{ .ArtistDetails.ArtistName }}"><i class="fa-solid fa-user"></i> <a href="{{ .Address }}/artist/{{ .ArtistDetails.Slug }}" target="_blank" class="info-label">{{ .ArtistDetails.ArtistName }}</a></span>
                 </div>
             {{ end }}
         </div>
 
         <div class="source-info-container">
-            <p class="source-label" style="color: {{ getSourceColor .Source }};">{{ .Source }}</p>
+            <p class="source-label" style="color: {{ getSourceColor .Source }};">{{ .Source }}{{ if .Instance }} ({{ .Instance }}){{ end }}</p>
             {{ if ne .Source "Lidarr" }}
                 <div>
                     {{ if .IsDownloaded }}
@@ -351,44 +451,32 @@ func getMediaReleasesiFrame(calendar *Calendar, theme, apiURL string, showUnmoni
             {{ end }}
         </div>
     </div>
-{{ end }}
-</body>
-</html>
+{{ end }}{{ end }}
 	`
 	// Homarr theme
 	scrollbarThumbBackgroundColor := "#d1dbe3"
 	scrollbarTrackBackgroundColor := "#ffffff"
+	textColor, secondaryTextColor := "#000000", "#5b6762"
 	if theme == "dark" {
 		scrollbarThumbBackgroundColor = "#484d64"
 		scrollbarTrackBackgroundColor = "rgba(37, 40, 53, 1)"
+		textColor, secondaryTextColor = "white", "#99b6bb"
 	}
 
-	radarrReleaseTypes := []string{}
-	if radarrInCinemas {
-		radarrReleaseTypes = append(radarrReleaseTypes, "inCinemas")
-	}
-	if radarrPhysical {
-		radarrReleaseTypes = append(radarrReleaseTypes, "physical")
-	}
-	if radarrDigital {
-		radarrReleaseTypes = append(radarrReleaseTypes, "digital")
-	}
-	radarrReleaseTypeStr := strings.Join(radarrReleaseTypes, ",")
-
 	templateData := iframeTemplateData{
 		Calendar:                      calendar,
+		Days:                          groupReleasesByDay(calendar.Releases, startDate, endDate, time.Now()),
+		Layout:                        layout,
 		Theme:                         theme,
-		APIURL:                        apiURL,
-		APIShowUnmonitored:            showUnmonitored,
-		APIRadarrReleaseType:          radarrReleaseTypeStr,
-		ShowEpisodeHours:              showEpisodeHours,
-		SonarrAddress:                 strings.TrimSuffix(config.GlobalConfigs.Sonarr.Address, "/"),
-		RadarrAddress:                 strings.TrimSuffix(config.GlobalConfigs.Radarr.Address, "/"),
-		LidarrAddress:                 strings.TrimSuffix(config.GlobalConfigs.Lidarr.Address, "/"),
+		LiveUpdates:                   sources.LiveUpdatesScript(apiURL, "media_releases"),
 		ScrollbarThumbBackgroundColor: scrollbarThumbBackgroundColor,
 		ScrollbarTrackBackgroundColor: scrollbarTrackBackgroundColor,
+		TextColor:                     textColor,
+		SecondaryTextColor:            secondaryTextColor,
 	}
 
+	// The list layout shows the release dates if it has many days, as the agenda and grid layouts group the releases by day
+	showDate := layout == "list" && calendarDays(startDate, endDate) > 1
 	templateFuncs := template.FuncMap{
 		"getSourceColor": func(source string) string {
 			switch source {
@@ -402,6 +490,11 @@ func getMediaReleasesiFrame(calendar *Calendar, theme, apiURL string, showUnmoni
 				return "#99b6bb"
 			}
 		},
+		"getStatusColor": getStatusColor,
+		"releaseTitle":   releaseTitle,
+		"card": func(release MediaRelease) releaseCard {
+			return releaseCard{Release: release, ShowEpisodeHours: showEpisodeHours, ShowDate: showDate}
+		},
 	}
 
 	tmpl, err := template.New("releases").Funcs(templateFuncs).Parse(html)
@@ -419,21 +512,121 @@ func getMediaReleasesiFrame(calendar *Calendar, theme, apiURL string, showUnmoni
 }
 
 type iframeTemplateData struct {
-	Calendar                      *Calendar
+	Calendar *Calendar
+	// Days are the days of the calendar with their releases, used by the agenda and grid layouts
+	Days                          []calendarDay
+	Layout                        string
 	Theme                         string
-	APIURL                        string
-	APIRadarrReleaseType          string
-	SonarrAddress                 string
-	RadarrAddress                 string
-	LidarrAddress                 string
+	LiveUpdates                   template.HTML
 	ScrollbarThumbBackgroundColor string
 	ScrollbarTrackBackgroundColor string
-	ShowEpisodeHours              bool
-	APIShowUnmonitored            bool
+	TextColor                     string
+	SecondaryTextColor            string
+}
+
+// releaseCard is the data of a release card in the list and agenda layouts
+type releaseCard struct {
+	Release          MediaRelease
+	ShowEpisodeHours bool
+	ShowDate         bool
+}
+
+// calendarDay is a day of the calendar with its releases
+type calendarDay struct {
+	Date time.Time
+	// Label is "Today", "Tomorrow", "Yesterday", or the date, like "Tuesday, October 20"
+	Label    string
+	Today    bool
+	Releases []MediaRelease
+}
+
+// groupReleasesByDay returns every day from the start date to the end date with its releases.
+// The releases must be sorted by release day.
+func groupReleasesByDay(releases []MediaRelease, startDate, endDate, now time.Time) []calendarDay {
+	today := startOfDay(now)
+	days := make([]calendarDay, 0, calendarDays(startDate, endDate))
+	for date := startOfDay(startDate); !date.After(endDate); date = date.AddDate(0, 0, 1) {
+		day := calendarDay{Date: date, Label: date.Format("Monday, January 2"), Today: date.Equal(today)}
+		switch {
+		case day.Today:
+			day.Label = "Today"
+		case date.Equal(today.AddDate(0, 0, 1)):
+			day.Label = "Tomorrow"
+		case date.Equal(today.AddDate(0, 0, -1)):
+			day.Label = "Yesterday"
+		}
+		days = append(days, day)
+	}
+
+	i := 0
+	for _, release := range releases {
+		releaseDay := startOfDay(release.ReleaseDate)
+		for i < len(days) && days[i].Date.Before(releaseDay) {
+			i++
+		}
+		if i < len(days) && days[i].Date.Equal(releaseDay) {
+			days[i].Releases = append(days[i].Releases, release)
+		}
+	}
+
+	return days
+}
+
+// getStatusColor returns the color of the download status of a release
+func getStatusColor(release MediaRelease) string {
+	switch {
+	case release.Source == "Lidarr" && release.TrackFileCount == release.TotalTrackCount:
+		return "green"
+	case release.Source == "Lidarr":
+		return "red"
+	case release.IsDownloaded:
+		return "green"
+	case release.ShouldBeDownloaded:
+		return "red"
+	default:
+		return "#99b6bb"
+	}
+}
+
+// releaseTitle returns the title of a release with its episode or artist, like "Severance - S2E1"
+func releaseTitle(release MediaRelease) string {
+	switch release.Source {
+	case "Sonarr":
+		return fmt.Sprintf("%s - S%dE%d - %s", release.Title, release.EpisodeDetails.SeasonNumber, release.EpisodeDetails.EpisodeNumber, release.EpisodeDetails.EpisodeName)
+	case "Lidarr":
+		return release.Title + " - " + release.ArtistDetails.ArtistName
+	default:
+		return release.Title
+	}
 }
 
 // GetHash returns the hash of the media releases
+//
+// @Summary Get the hash of media releases
+// @Description Get the hash of the media releases. Used by the iFrames to check updates and reload the iframe.
+// @Success 200 {object} sources.HashResponse
+// @Produce json
+// @Param radarrReleaseType query string false "Filter movies get from Radarr. Can be 'inCinemas', 'physical', or 'digital'. Defaults to 'inCinemas'" Example(physical)
+// @Param showUnmonitored query bool false "Specify if show unmonitored media. Defaults to false." Example(true)
+// @Param days_before query int false "Number of days before today to show. Defaults to 0." Example(1)
+// @Param days_after query int false "Number of days after today to show. Defaults to 0." Example(6)
+// @Param start query string false "First day to show, like '2026-10-01'. Must be set with end, and can't be used with days_before and days_after." Example(2026-10-01)
+// @Param end query string false "Last day to show, like '2026-10-31'. The calendar can have at most 92 days." Example(2026-10-31)
+// @Router /hash/media_releases [get]
 func GetHash(c *gin.Context) {
+	releases, ok := getQueryData(c)
+	if !ok {
+		return
+	}
+
+	hash := sources.GetHash(*releases, time.Now().Format("2006-01-02"))
+
+	c.JSON(http.StatusOK, gin.H{"hash": fmt.Sprintf("%x", hash)})
+}
+
+// getQueryData parses the query parameters used by the hash and data routes and returns the calendar.
+// If it fails, it writes the error response and returns false.
+func getQueryData(c *gin.Context) (*Calendar, bool) {
 	var inCinemas, physical, digital bool
 	radarrReleaseTypeStr := c.Query("radarrReleaseType")
 	if radarrReleaseTypeStr != "" {
@@ -448,7 +641,7 @@ func GetHash(c *gin.Context) {
 				digital = true
 			default:
 				c.JSON(http.StatusBadRequest, gin.H{"message": "radarrReleaseType must be 'inCinemas', 'physical', 'digital', or a combination of them separated by commas, like 'inCinemas,physical'"})
-				return
+				return nil, false
 			}
 		}
 	} else {
@@ -464,15 +657,30 @@ func GetHash(c *gin.Context) {
 	case "":
 	default:
 		c.JSON(http.StatusBadRequest, gin.H{"message": "showUnmonitored must be empty, 'true', or 'false'"})
-		return
+		return nil, false
+	}
+
+	startDate, endDate, err := parseDateRange(c.Request.URL.Query(), time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return nil, false
 	}
 
-	releases, err := getCalendar(showUnmonitored, inCinemas, physical, digital)
+	releases, err := getCachedCalendar(c.Request.URL.Query(), startDate, endDate, showUnmonitored, inCinemas, physical, digital)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-		return
+		return nil, false
 	}
-	hash := sources.GetHash(*releases, time.Now().Format("2006-01-02"))
 
-	c.JSON(http.StatusOK, gin.H{"hash": fmt.Sprintf("%x", hash)})
+	return releases, true
+}
+
+// getCachedCalendar returns the calendar used by the iFrame and hash routes.
+// The calendar is cached by the query parameters and the days that change it.
+func getCachedCalendar(query url.Values, startDate, endDate time.Time, showUnmonitored, inCinemas, physical, digital bool) (*Calendar, error) {
+	key := sources.CacheKey("media_releases", query, "radarrReleaseType", "showUnmonitored")
+	key += "#" + startDate.Format(time.DateOnly) + "/" + endDate.Format(time.DateOnly)
+	return sources.Cached(key, func() (*Calendar, error) {
+		return getCalendar(startDate, endDate, showUnmonitored, inCinemas, physical, digital)
+	})
 }
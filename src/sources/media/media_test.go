@@ -0,0 +1,50 @@
+package media
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGroupReleasesByDay(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 30, 0, 0, time.Local)
+	releases := []MediaRelease{
+		{Title: "Yesterday", ReleaseDate: time.Date(2026, 10, 17, 22, 0, 0, 0, time.Local)},
+		{Title: "Today 1", ReleaseDate: time.Date(2026, 10, 18, 21, 0, 0, 0, time.Local)},
+		{Title: "Today 2", ReleaseDate: time.Date(2026, 10, 18, 9, 0, 0, 0, time.Local)},
+		{Title: "Next week", ReleaseDate: time.Date(2026, 10, 25, 9, 0, 0, 0, time.Local)},
+	}
+	days := groupReleasesByDay(releases, time.Date(2026, 10, 17, 0, 0, 0, 0, time.Local), time.Date(2026, 10, 23, 0, 0, 0, 0, time.Local), now)
+	if len(days) != 7 {
+		t.Fatalf("expected 7 days, got %d", len(days))
+	}
+	if days[0].Label != "Yesterday" || days[1].Label != "Today" || !days[1].Today || days[2].Label != "Tomorrow" || days[3].Label != "Tuesday, October 20" {
+		t.Errorf("unexpected labels: %s, %s, %s, %s", days[0].Label, days[1].Label, days[2].Label, days[3].Label)
+	}
+	if len(days[0].Releases) != 1 || len(days[1].Releases) != 2 || days[1].Releases[0].Title != "Today 1" || len(days[6].Releases) != 0 {
+		t.Errorf("unexpected releases: %+v", days)
+	}
+}
+
+func TestMediaReleasesLayouts(t *testing.T) {
+	releaseDate := time.Now()
+	calendar := &Calendar{Releases: []MediaRelease{
+		{Title: "Dune", Source: "Radarr", Address: "http://radarr", Slug: "dune", PosterImageURL: "http://radarr/dune.jpg", ReleaseDate: releaseDate, IsDownloaded: true},
+	}}
+	startDate := startOfDay(releaseDate)
+	for layout, expected := range map[string][]string{
+		"list":   {`class="releases-container"`, `href="http://radarr/movie/dune"`, releaseDate.Format("Jan 2")},
+		"agenda": {`class="releases-container"`, `>Today</p>`},
+		"grid":   {`class="calendar-grid"`, `class="day-cell today"`, `src="http://radarr/dune.jpg"`, "border-color: green;"},
+	} {
+		html, err := getMediaReleasesiFrame(calendar, startDate, startDate.AddDate(0, 0, 6), layout, "dark", "", true)
+		if err != nil {
+			t.Fatalf("%s: %s", layout, err)
+		}
+		for _, text := range expected {
+			if !strings.Contains(string(html), text) {
+				t.Errorf("%s: expected the iFrame to contain %s", layout, text)
+			}
+		}
+	}
+}
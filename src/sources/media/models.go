@@ -19,17 +19,25 @@ type MediaRelease struct {
 	// - Radarr
 	// - Sonarr
 	// - Lidarr
-	Source         string
+	Source string
+	// Instance is the name of the source instance, like "4k". Empty for the default instance.
+	Instance string
+	// Address is the public address of the source instance, used to generate the URL of the media
+	Address        string
 	PosterImageURL string
 	CoverImageURL  string
 	IsDownloaded   bool
 	// A media should be downloaded when the its release date is after now.
 	ShouldBeDownloaded bool
+	// Radarr specific. ReleaseType is "inCinemas", "digital", or "physical".
+	ReleaseType string
 	// Sonnar specific
 	EpisodeDetails struct {
 		EpisodeName   string
 		SeasonNumber  int
 		EpisodeNumber int
+		// Runtime is the episode runtime in minutes, 0 if unknown
+		Runtime int
 	}
 	// Lidarr specific
 	ArtistDetails struct {
@@ -40,3 +48,17 @@ type MediaRelease struct {
 	TrackFileCount  int
 	TotalTrackCount int
 }
+
+// URL returns the URL of the media in the source
+func (r MediaRelease) URL() string {
+	switch r.Source {
+	case "Sonarr":
+		return r.Address + "/series/" + r.Slug
+	case "Radarr":
+		return r.Address + "/movie/" + r.Slug
+	case "Lidarr":
+		return r.Address + "/album/" + r.Slug
+	default:
+		return r.Address
+	}
+}
@@ -0,0 +1,35 @@
+package netdata
+
+import "github.com/diogovalentte/homarr-iframes/src/sources"
+
+// GetIframeAlarms returns the Netdata alarms in the alarms iFrame format
+func (n *Netdata) GetIframeAlarms() ([]sources.Alarm, error) {
+	netdataAlarms, err := n.GetAlarms(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	var alarms []sources.Alarm
+	for _, alarm := range netdataAlarms {
+		summary := alarm.Summary
+		if summary == "" {
+			summary = alarm.Name
+		}
+		if summary == "" {
+			summary = "Unknown"
+		}
+		alarms = append(alarms, sources.Alarm{
+			Source:            "Netdata",
+			BackgroundImgURL:  BackgroundImageURL,
+			BackgroundImgSize: 80,
+			Summary:           summary,
+			URL:               n.Address,
+			Status:            alarm.Status,
+			Value:             alarm.ValueString,
+			Property:          alarm.Component + " / " + alarm.Type,
+			Time:              alarm.LastStatusChange,
+		})
+	}
+
+	return alarms, nil
+}
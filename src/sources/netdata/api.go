@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"sort"
 	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
 func (n *Netdata) GetAlarms(limit int) ([]Alarm, error) {
@@ -51,7 +53,7 @@ type getAlarmsResponse struct {
 }
 
 func (n *Netdata) baseRequest(method, url string, body io.Reader, target any) error {
-	client := &http.Client{}
+	client := sources.HTTPClient("netdata", n.Instance)
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
@@ -1,45 +1,82 @@
 package netdata
 
 import (
-	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
 var (
-	n                  *Netdata
+	instances          = sources.NewInstances[*Netdata]("netdata")
 	BackgroundImageURL = "https://avatars.githubusercontent.com/u/43390781"
 )
 
+func init() {
+	sources.Register(sources.Integration{
+		Name:  "netdata",
+		Title: "Netdata",
+		Config: config.Schema{
+			Prefix:        "NETDATA",
+			MultiInstance: true,
+			Vars: []config.Var{
+				{Key: "ADDRESS", Required: true, Validate: config.ValidateURL},
+				{Key: "INTERNAL_ADDRESS", Validate: config.ValidateURL},
+				{Key: "TOKEN", Required: true, Secret: true},
+			},
+		},
+		Alarms: func(instance string, _ url.Values) ([]sources.Alarm, error) {
+			n, err := NewInstance(instance)
+			if err != nil {
+				return nil, err
+			}
+
+			return n.GetIframeAlarms()
+		},
+		Check: func(instance string) (string, error) {
+			n, err := NewInstance(instance)
+			if err != nil {
+				return "", err
+			}
+
+			return n.GetVersion()
+		},
+	})
+}
+
 type Netdata struct {
+	// Instance is the name of the instance, "" for the default instance
+	Instance        string
 	Address         string
 	InternalAddress string
 	Token           string
 }
 
 func New() (*Netdata, error) {
-	if n != nil {
-		return n, nil
-	}
-
-	newN := &Netdata{}
-	err := newN.Init()
-	if err != nil {
-		return nil, err
-	}
+	return NewInstance("")
+}
 
-	n = newN
+// NewInstance returns the client of a named Netdata instance, configured with the NETDATA_<INSTANCE>_* variables
+func NewInstance(instance string) (*Netdata, error) {
+	return instances.Get(instance, func(instance string) (*Netdata, error) {
+		newClient := &Netdata{Instance: instance}
+		err := newClient.Init()
+		if err != nil {
+			return nil, err
+		}
 
-	return n, nil
+		return newClient, nil
+	})
 }
 
 // Init sets the Netdata properties from the configs
 func (n *Netdata) Init() error {
-	address, internalAddress, token := config.GlobalConfigs.NetdataConfigs.Address, config.GlobalConfigs.NetdataConfigs.InternalAddress, config.GlobalConfigs.NetdataConfigs.Token
-	if address == "" || token == "" {
-		return fmt.Errorf("NETDATA_ADDRESS and NETDATA_TOKEN variables should be set")
+	sourceConfigs, err := config.Current().LoadInstance("netdata", n.Instance)
+	if err != nil {
+		return err
 	}
+	address, internalAddress, token := sourceConfigs.Get("ADDRESS"), sourceConfigs.Get("INTERNAL_ADDRESS"), sourceConfigs.Get("TOKEN")
 	n.Address = strings.TrimSuffix(address, "/")
 	if internalAddress == "" {
 		n.InternalAddress = n.Address
@@ -50,3 +87,18 @@ func (n *Netdata) Init() error {
 
 	return nil
 }
+
+// GetVersion returns the Netdata agent version
+func (n *Netdata) GetVersion() (string, error) {
+	var info Info
+	err := n.baseRequest("GET", n.InternalAddress+"/api/v1/info", nil, &info)
+	if err != nil {
+		return "", err
+	}
+
+	return info.Version, nil
+}
+
+type Info struct {
+	Version string `json:"version"`
+}
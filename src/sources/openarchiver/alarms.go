@@ -0,0 +1,49 @@
+package openarchiver
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// GetIframeAlarms returns the ingestion sources with errors as alarms
+func (o *OpenArchiver) GetIframeAlarms() ([]sources.Alarm, error) {
+	ingestionSources, err := o.GetIngestionSources(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	alarms := []sources.Alarm{}
+	for _, source := range ingestionSources {
+		if source.Status == "error" {
+			message := source.LastSyncStatusMessage
+			slices := strings.Split(message, "\n")
+			if len(slices) > 1 {
+				message = slices[1]
+			}
+			slices = strings.Split(message, ": ")
+			if len(slices) > 1 {
+				message = slices[1]
+			}
+
+			lastSyncFinishedAt, err := time.Parse(time.RFC3339, source.LastSyncFinishedAt)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse LastSyncFinishedAt for source %s: %w", source.Name, err)
+			}
+
+			alarms = append(alarms, sources.Alarm{
+				Source:          "OpenArchiver",
+				Summary:         message,
+				URL:             o.Address + "/dashboard/ingestions",
+				Status:          "ERROR",
+				Property:        source.Name,
+				Time:            lastSyncFinishedAt,
+				BackgroundColor: "black",
+			})
+		}
+	}
+
+	return alarms, nil
+}
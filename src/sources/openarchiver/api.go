@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
 func (o *OpenArchiver) GetIngestionSources(limit int) ([]IngestionSource, error) {
@@ -22,7 +24,7 @@ func (o *OpenArchiver) GetIngestionSources(limit int) ([]IngestionSource, error)
 }
 
 func (o *OpenArchiver) baseRequest(method, url string, body io.Reader, target any) error {
-	client := &http.Client{}
+	client := sources.HTTPClient("openarchiver", o.Instance)
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
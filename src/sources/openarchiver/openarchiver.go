@@ -2,17 +2,54 @@ package openarchiver
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
 var (
 	BackgroundImgURL = "https://openarchiver.com/logo/logo-sq.svg"
-	o                *OpenArchiver
+	instances        = sources.NewInstances[*OpenArchiver]("openarchiver")
 )
 
+func init() {
+	sources.Register(sources.Integration{
+		Name:  "openarchiver",
+		Title: "OpenArchiver",
+		Config: config.Schema{
+			Prefix:        "OPENARCHIVER",
+			MultiInstance: true,
+			Vars: []config.Var{
+				{Key: "ADDRESS", Required: true, Validate: config.ValidateURL},
+				{Key: "INTERNAL_ADDRESS", Validate: config.ValidateURL},
+				{Key: "SUPER_API_KEY", Required: true, Secret: true},
+			},
+		},
+		Alarms: func(instance string, _ url.Values) ([]sources.Alarm, error) {
+			o, err := NewInstance(instance)
+			if err != nil {
+				return nil, err
+			}
+
+			return o.GetIframeAlarms()
+		},
+		Check: func(instance string) (string, error) {
+			o, err := NewInstance(instance)
+			if err != nil {
+				return "", err
+			}
+			_, err = o.GetIngestionSources(1)
+
+			return "", err
+		},
+	})
+}
+
 type OpenArchiver struct {
+	// Instance is the name of the instance, "" for the default instance
+	Instance         string
 	Address          string
 	InternalAddress  string
 	SuperAPIKey      string
@@ -20,28 +57,33 @@ type OpenArchiver struct {
 }
 
 func New() (*OpenArchiver, error) {
-	if o != nil {
-		return o, nil
-	}
-
-	address := config.GlobalConfigs.OpenArchiver.Address
-	internalAddress := config.GlobalConfigs.OpenArchiver.InternalAddress
-	superAPIKey := config.GlobalConfigs.OpenArchiver.SuperAPIKey
+	return NewInstance("")
+}
 
-	newO := &OpenArchiver{}
-	err := newO.Init(address, internalAddress, superAPIKey)
-	if err != nil {
-		return nil, err
-	}
+// NewInstance returns the client of a named OpenArchiver instance, configured with the OPENARCHIVER_<INSTANCE>_* variables
+func NewInstance(instance string) (*OpenArchiver, error) {
+	return instances.Get(instance, func(instance string) (*OpenArchiver, error) {
+		sourceConfigs, err := config.Current().LoadInstance("openarchiver", instance)
+		if err != nil {
+			return nil, err
+		}
+		address := sourceConfigs.Get("ADDRESS")
+		internalAddress := sourceConfigs.Get("INTERNAL_ADDRESS")
+		superAPIKey := sourceConfigs.Get("SUPER_API_KEY")
 
-	o = newO
+		newO := &OpenArchiver{Instance: instance}
+		err = newO.Init(address, internalAddress, superAPIKey)
+		if err != nil {
+			return nil, err
+		}
 
-	return o, nil
+		return newO, nil
+	})
 }
 
 func (l *OpenArchiver) Init(address, internalAddress, superAPIKey string) error {
 	if address == "" || superAPIKey == "" {
-		return fmt.Errorf("OPENARCHIVER_ADDRESS and OPENARCHIVER_TOKEN variables should be set")
+		return fmt.Errorf("OPENARCHIVER_ADDRESS and OPENARCHIVER_SUPER_API_KEY variables should be set")
 	}
 
 	l.Address = strings.TrimSuffix(address, "/")
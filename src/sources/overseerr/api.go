@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
 func (o *Overseerr) GetRequests(limit int, filter, sort string, requestedBy int) ([]Request, error) {
@@ -111,8 +112,27 @@ type getTvResponse struct {
 	ID           int    `json:"id"`
 }
 
+// GetVersion checks if the API key is valid and returns the Overseerr version
+func (o *Overseerr) GetVersion() (string, error) {
+	var user struct {
+		ID int `json:"id"`
+	}
+	if err := o.baseRequest(http.MethodGet, o.InternalAddress+"/api/v1/auth/me", nil, &user); err != nil {
+		return "", err
+	}
+
+	var status struct {
+		Version string `json:"version"`
+	}
+	if err := o.baseRequest(http.MethodGet, o.InternalAddress+"/api/v1/status", nil, &status); err != nil {
+		return "", err
+	}
+
+	return status.Version, nil
+}
+
 func (o *Overseerr) baseRequest(method, url string, body io.Reader, target any) error {
-	client := &http.Client{}
+	client := sources.HTTPClient("overseerr", "")
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
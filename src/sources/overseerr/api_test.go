@@ -67,6 +67,11 @@ func TestGetMedia(t *testing.T) {
 }
 
 func TestGetMovieTV(t *testing.T) {
+	o, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	t.Run("get movie", func(t *testing.T) {
 		media, err := o.GetMovie(929590) // civil war
 		if err != nil {
@@ -1,18 +1,41 @@
 package overseerr
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
 var (
-	o                         *Overseerr
+	instances                 = sources.NewInstances[*Overseerr]("overseerr")
 	TMDBPosterImageBasePath   = "https://image.tmdb.org/t/p/w600_and_h900_bestv2/"
 	TMDBBackdropImageBasePath = "https://image.tmdb.org/t/p/original/"
 )
 
+func init() {
+	sources.Register(sources.Integration{
+		Name:  "overseerr",
+		Title: "Overseerr",
+		Config: config.Schema{
+			Prefix: "OVERSEERR",
+			Vars: []config.Var{
+				{Key: "ADDRESS", Required: true, Validate: config.ValidateURL},
+				{Key: "INTERNAL_ADDRESS", Validate: config.ValidateURL},
+				{Key: "API_KEY", Required: true, Secret: true},
+			},
+		},
+		Check: func(string) (string, error) {
+			o, err := New()
+			if err != nil {
+				return "", err
+			}
+
+			return o.GetVersion()
+		},
+	})
+}
+
 type Overseerr struct {
 	Address         string
 	InternalAddress string
@@ -20,30 +43,24 @@ type Overseerr struct {
 }
 
 func New() (*Overseerr, error) {
-	if o != nil {
-		return o, nil
-	}
-
-	address := config.GlobalConfigs.Overseerr.Address
-	internalAddress := config.GlobalConfigs.Overseerr.InternalAddress
-	APIKey := config.GlobalConfigs.Overseerr.APIKey
+	return instances.Get("", func(string) (*Overseerr, error) {
+		newO := &Overseerr{}
+		err := newO.Init()
+		if err != nil {
+			return nil, err
+		}
 
-	newO := &Overseerr{}
-	err := newO.Init(address, internalAddress, APIKey)
-	if err != nil {
-		return nil, err
-	}
-
-	o = newO
-
-	return o, nil
+		return newO, nil
+	})
 }
 
 // Init sets the Overseerr properties from the configs
-func (o *Overseerr) Init(address, internalAddress, APIKey string) error {
-	if address == "" || APIKey == "" {
-		return fmt.Errorf("OVERSEERR_ADDRESS and OVERSEERR_API_KEY variables should be set")
+func (o *Overseerr) Init() error {
+	sourceConfigs, err := config.Current().LoadSource("overseerr")
+	if err != nil {
+		return err
 	}
+	address, internalAddress, APIKey := sourceConfigs.Get("ADDRESS"), sourceConfigs.Get("INTERNAL_ADDRESS"), sourceConfigs.Get("API_KEY")
 
 	o.Address = strings.TrimSuffix(address, "/")
 	if internalAddress == "" {
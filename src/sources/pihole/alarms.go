@@ -0,0 +1,38 @@
+package pihole
+
+import (
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// GetIframeAlarms returns the messages of the "Pi-hole diagnostic" page as alarms
+func (p *Pihole) GetIframeAlarms() ([]sources.Alarm, error) {
+	messages, err := p.GetMessages()
+	if err != nil {
+		return nil, err
+	}
+
+	var alarms []sources.Alarm
+	for _, message := range messages.Messages {
+		timestamp := time.Unix(message.Timestamp, 0)
+		var url string
+		if p.Token != "" {
+			url = p.Address + "/admin/messages.php"
+		} else {
+			url = p.Address + "/admin/messages"
+		}
+		alarms = append(alarms, sources.Alarm{
+			Source:            "Pi-hole",
+			BackgroundImgURL:  BackgroundImgURL,
+			BackgroundImgSize: 80,
+			Summary:           message.Plain,
+			Property:          message.Type,
+			Time:              timestamp,
+			URL:               url,
+			Status:            "WARNING",
+		})
+	}
+
+	return alarms, nil
+}
@@ -8,10 +8,12 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
 func (p *Pihole) baseRequest(method, url string, body io.Reader, target any, unauthorizedRetries int) error {
-	client := &http.Client{}
+	client := sources.HTTPClient("pihole", p.Instance)
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
@@ -82,7 +84,7 @@ func (p *Pihole) Login() error {
 	}
 	payload := bytes.NewReader(jsonData)
 
-	client := &http.Client{}
+	client := sources.HTTPClient("pihole", p.Instance)
 	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/auth", p.InternalAddress), payload)
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
@@ -125,7 +127,7 @@ func (p *Pihole) Login() error {
 }
 
 func (p *Pihole) Logout() error {
-	client := &http.Client{}
+	client := sources.HTTPClient("pihole", p.Instance)
 	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/auth", p.InternalAddress), nil)
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
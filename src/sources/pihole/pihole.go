@@ -2,18 +2,56 @@ package pihole
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
 var (
-	p                *Pihole
+	instances        = sources.NewInstances[*Pihole]("pihole")
 	BackgroundImgURL = "https://miro.medium.com/v2/resize:fit:657/0*7RBpclLFdUJdwNAK.png"
 )
 
+func init() {
+	sources.Register(sources.Integration{
+		Name:  "pihole",
+		Title: "Pi-hole",
+		Config: config.Schema{
+			Prefix:        "PIHOLE",
+			MultiInstance: true,
+			Vars: []config.Var{
+				{Key: "ADDRESS", Validate: config.ValidateURL},
+				{Key: "INTERNAL_ADDRESS", Validate: config.ValidateURL},
+				{Key: "TOKEN", Secret: true},
+				{Key: "PASSWORD", Secret: true},
+			},
+		},
+		Alarms: func(instance string, _ url.Values) ([]sources.Alarm, error) {
+			p, err := NewInstance(instance)
+			if err != nil {
+				return nil, err
+			}
+
+			return p.GetIframeAlarms()
+		},
+		Check: func(instance string) (string, error) {
+			p, err := NewInstance(instance)
+			if err != nil {
+				return "", err
+			}
+			_, err = p.GetMessages()
+
+			return "", err
+		},
+	})
+}
+
 type Pihole struct {
+	// Instance is the name of the instance, "" for the default instance
+	Instance        string
 	Address         string
 	InternalAddress string
 	Token           string // <v6.0
@@ -23,25 +61,29 @@ type Pihole struct {
 }
 
 func New() (*Pihole, error) {
-	if p != nil {
-		return p, nil
-	}
-
-	newR := &Pihole{}
-	err := newR.Init()
-	if err != nil {
-		return nil, err
-	}
+	return NewInstance("")
+}
 
-	p = newR
+// NewInstance returns the client of a named Pi-hole instance, configured with the PIHOLE_<INSTANCE>_* variables
+func NewInstance(instance string) (*Pihole, error) {
+	return instances.Get(instance, func(instance string) (*Pihole, error) {
+		newClient := &Pihole{Instance: instance}
+		err := newClient.Init()
+		if err != nil {
+			return nil, err
+		}
 
-	return p, nil
+		return newClient, nil
+	})
 }
 
 func (p *Pihole) Init() error {
-	address, internalAddress, APIToken, APIPassword := config.GlobalConfigs.Pihole.Address, config.GlobalConfigs.Pihole.InternalAddress, config.GlobalConfigs.Pihole.Token, config.GlobalConfigs.Pihole.Password
+	sourceConfigs := config.Current().Instance("pihole", p.Instance)
+	address, internalAddress, APIToken, APIPassword := sourceConfigs.Get("ADDRESS"), sourceConfigs.Get("INTERNAL_ADDRESS"), sourceConfigs.Get("TOKEN"), sourceConfigs.Get("PASSWORD")
 	if address == "" || (APIToken == "" && APIPassword == "") {
-		return fmt.Errorf("PIHOLE_ADDRESS and PIHOLE_TOKEN or PIHOLE_PASSWORD variables should be set")
+		schema, _ := config.GetSchema("pihole")
+		schema = schema.ForInstance(p.Instance)
+		return fmt.Errorf("%s and %s or %s variables should be set", schema.EnvName("ADDRESS"), schema.EnvName("TOKEN"), schema.EnvName("PASSWORD"))
 	}
 
 	p.Address = strings.TrimSuffix(address, "/")
@@ -0,0 +1,35 @@
+package prowlarr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// GetIframeAlarms returns the Prowlarr health issues as alarms
+func (p *Prowlarr) GetIframeAlarms() ([]sources.Alarm, error) {
+	healthEntries, err := p.GetHealth()
+	if err != nil {
+		return nil, err
+	}
+
+	var alarms []sources.Alarm
+	for _, alarm := range healthEntries {
+		url := fmt.Sprintf("%s/system/status", p.Address)
+		if alarm.WikiURL != "" {
+			url = alarm.WikiURL
+		}
+		alarms = append(alarms, sources.Alarm{
+			Source:            "Prowlarr",
+			BackgroundImgURL:  BackgroundImageURL,
+			BackgroundImgSize: 100,
+			Summary:           alarm.Message,
+			URL:               url,
+			Status:            strings.ToUpper(alarm.Type),
+			Property:          alarm.Source,
+		})
+	}
+
+	return alarms, nil
+}
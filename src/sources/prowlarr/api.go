@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
-func baseRequest(method, url string, body io.Reader, target any) error {
-	client := &http.Client{}
+func (p *Prowlarr) baseRequest(method, url string, body io.Reader, target any) error {
+	client := sources.HTTPClient("prowlarr", p.Instance)
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
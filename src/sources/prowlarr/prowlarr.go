@@ -2,43 +2,81 @@ package prowlarr
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
 var (
-	p                  *Prowlarr
+	instances          = sources.NewInstances[*Prowlarr]("prowlarr")
 	BackgroundImageURL = "https://avatars.githubusercontent.com/u/73049443"
 )
 
+func init() {
+	sources.Register(sources.Integration{
+		Name:  "prowlarr",
+		Title: "Prowlarr",
+		Config: config.Schema{
+			Prefix:        "PROWLARR",
+			MultiInstance: true,
+			Vars: []config.Var{
+				{Key: "ADDRESS", Required: true, Validate: config.ValidateURL},
+				{Key: "INTERNAL_ADDRESS", Validate: config.ValidateURL},
+				{Key: "API_KEY", Required: true, Secret: true},
+			},
+		},
+		Alarms: func(instance string, _ url.Values) ([]sources.Alarm, error) {
+			p, err := NewInstance(instance)
+			if err != nil {
+				return nil, err
+			}
+
+			return p.GetIframeAlarms()
+		},
+		Check: func(instance string) (string, error) {
+			p, err := NewInstance(instance)
+			if err != nil {
+				return "", err
+			}
+
+			return p.GetVersion()
+		},
+	})
+}
+
 type Prowlarr struct {
+	// Instance is the name of the instance, "" for the default instance
+	Instance        string
 	Address         string
 	InternalAddress string
 	APIKey          string
 }
 
 func New() (*Prowlarr, error) {
-	if p != nil {
-		return p, nil
-	}
-
-	newR := &Prowlarr{}
-	err := newR.Init()
-	if err != nil {
-		return nil, err
-	}
+	return NewInstance("")
+}
 
-	p = newR
+// NewInstance returns the client of a named Prowlarr instance, configured with the PROWLARR_<INSTANCE>_* variables
+func NewInstance(instance string) (*Prowlarr, error) {
+	return instances.Get(instance, func(instance string) (*Prowlarr, error) {
+		newClient := &Prowlarr{Instance: instance}
+		err := newClient.Init()
+		if err != nil {
+			return nil, err
+		}
 
-	return p, nil
+		return newClient, nil
+	})
 }
 
 func (p *Prowlarr) Init() error {
-	address, internalAddress, APIKey := config.GlobalConfigs.Prowlarr.Address, config.GlobalConfigs.Prowlarr.InternalAddress, config.GlobalConfigs.Prowlarr.APIKey
-	if address == "" || APIKey == "" {
-		return fmt.Errorf("PROWLARR_ADDRESS and PROWLARR_API_KEY variables should be set")
+	sourceConfigs, err := config.Current().LoadInstance("prowlarr", p.Instance)
+	if err != nil {
+		return err
 	}
+	address, internalAddress, APIKey := sourceConfigs.Get("ADDRESS"), sourceConfigs.Get("INTERNAL_ADDRESS"), sourceConfigs.Get("API_KEY")
 
 	p.Address = strings.TrimSuffix(address, "/")
 	if internalAddress == "" {
@@ -53,7 +91,7 @@ func (p *Prowlarr) Init() error {
 
 func (p *Prowlarr) GetHealth() ([]*HealthEntry, error) {
 	var entries []*HealthEntry
-	err := baseRequest("GET", fmt.Sprintf("%s/api/v1/health", p.InternalAddress), nil, &entries)
+	err := p.baseRequest("GET", fmt.Sprintf("%s/api/v1/health", p.InternalAddress), nil, &entries)
 	if err != nil {
 		return nil, err
 	}
@@ -67,3 +105,18 @@ type HealthEntry struct {
 	Message string `json:"message"`
 	WikiURL string `json:"wikiUrl"`
 }
+
+// GetVersion returns the Prowlarr version
+func (p *Prowlarr) GetVersion() (string, error) {
+	var status SystemStatus
+	err := p.baseRequest("GET", fmt.Sprintf("%s/api/v1/system/status", p.InternalAddress), nil, &status)
+	if err != nil {
+		return "", err
+	}
+
+	return status.Version, nil
+}
+
+type SystemStatus struct {
+	Version string `json:"version"`
+}
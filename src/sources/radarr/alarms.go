@@ -0,0 +1,35 @@
+package radarr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// GetIframeAlarms returns the Radarr health issues as alarms
+func (r *Radarr) GetIframeAlarms() ([]sources.Alarm, error) {
+	healthEntries, err := r.GetHealth()
+	if err != nil {
+		return nil, err
+	}
+
+	var alarms []sources.Alarm
+	for _, alarm := range healthEntries {
+		url := fmt.Sprintf("%s/system/status", r.Address)
+		if alarm.WikiURL != "" {
+			url = alarm.WikiURL
+		}
+		alarms = append(alarms, sources.Alarm{
+			Source:            "Radarr",
+			BackgroundImgURL:  BackgroundImageURL,
+			BackgroundImgSize: 120,
+			Summary:           alarm.Message,
+			URL:               url,
+			Status:            strings.ToUpper(alarm.Type),
+			Property:          alarm.Source,
+		})
+	}
+
+	return alarms, nil
+}
@@ -2,44 +2,82 @@ package radarr
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
 var (
-	r                  *Radarr
+	instances          = sources.NewInstances[*Radarr]("radarr")
 	BackgroundImageURL = "https://avatars.githubusercontent.com/u/25025331"
 )
 
+func init() {
+	sources.Register(sources.Integration{
+		Name:  "radarr",
+		Title: "Radarr",
+		Config: config.Schema{
+			Prefix:        "RADARR",
+			MultiInstance: true,
+			Vars: []config.Var{
+				{Key: "ADDRESS", Required: true, Validate: config.ValidateURL},
+				{Key: "INTERNAL_ADDRESS", Validate: config.ValidateURL},
+				{Key: "API_KEY", Required: true, Secret: true},
+			},
+		},
+		Alarms: func(instance string, _ url.Values) ([]sources.Alarm, error) {
+			r, err := NewInstance(instance)
+			if err != nil {
+				return nil, err
+			}
+
+			return r.GetIframeAlarms()
+		},
+		Check: func(instance string) (string, error) {
+			r, err := NewInstance(instance)
+			if err != nil {
+				return "", err
+			}
+
+			return r.GetVersion()
+		},
+	})
+}
+
 type Radarr struct {
+	// Instance is the name of the instance, "" for the default instance
+	Instance        string
 	Address         string
 	InternalAddress string
 	APIKey          string
 }
 
 func New() (*Radarr, error) {
-	if r != nil {
-		return r, nil
-	}
-
-	newR := &Radarr{}
-	err := newR.Init()
-	if err != nil {
-		return nil, err
-	}
-
-	r = newR
+	return NewInstance("")
+}
 
-	return r, nil
+// NewInstance returns the client of a named Radarr instance, configured with the RADARR_<INSTANCE>_* variables
+func NewInstance(instance string) (*Radarr, error) {
+	return instances.Get(instance, func(instance string) (*Radarr, error) {
+		newClient := &Radarr{Instance: instance}
+		err := newClient.Init()
+		if err != nil {
+			return nil, err
+		}
+
+		return newClient, nil
+	})
 }
 
 func (r *Radarr) Init() error {
-	address, internalAddress, APIKey := config.GlobalConfigs.Radarr.Address, config.GlobalConfigs.Radarr.InternalAddress, config.GlobalConfigs.Radarr.APIKey
-	if address == "" || APIKey == "" {
-		return fmt.Errorf("RADARR_ADDRESS and RADARR_API_KEY variables should be set")
+	sourceConfigs, err := config.Current().LoadInstance("radarr", r.Instance)
+	if err != nil {
+		return err
 	}
+	address, internalAddress, APIKey := sourceConfigs.Get("ADDRESS"), sourceConfigs.Get("INTERNAL_ADDRESS"), sourceConfigs.Get("API_KEY")
 
 	r.Address = strings.TrimSuffix(address, "/")
 	if internalAddress == "" {
@@ -61,7 +99,7 @@ func (r *Radarr) GetCalendar(unmonitored bool, startDate, endDate time.Time) ([]
 	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, int(time.Second-time.Nanosecond), endDate.Location())
 
 	var entries []*GetRadarrCalendarEntryResponse
-	err := baseRequest("GET", fmt.Sprintf("%s/api/v3/calendar?start=%s&end=%s&unmonitored=%v&includeSeries=true", r.InternalAddress, startDate.Format("2006-01-02T15:04:05.000Z07:00"), endDate.Format("2006-01-02T15:04:05.000Z07:00"), unmonitored), nil, &entries)
+	err := r.baseRequest("GET", fmt.Sprintf("%s/api/v3/calendar?start=%s&end=%s&unmonitored=%v&includeSeries=true", r.InternalAddress, startDate.Format("2006-01-02T15:04:05.000Z07:00"), endDate.Format("2006-01-02T15:04:05.000Z07:00"), unmonitored), nil, &entries)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +125,7 @@ type DefaultReleaseImagesResponse struct {
 
 func (r *Radarr) GetHealth() ([]*HealthEntry, error) {
 	var entries []*HealthEntry
-	err := baseRequest("GET", fmt.Sprintf("%s/api/v3/health", r.InternalAddress), nil, &entries)
+	err := r.baseRequest("GET", fmt.Sprintf("%s/api/v3/health", r.InternalAddress), nil, &entries)
 	if err != nil {
 		return nil, err
 	}
@@ -101,3 +139,18 @@ type HealthEntry struct {
 	Message string `json:"message"`
 	WikiURL string `json:"wikiUrl"`
 }
+
+// GetVersion returns the Radarr version
+func (r *Radarr) GetVersion() (string, error) {
+	var status SystemStatus
+	err := r.baseRequest("GET", fmt.Sprintf("%s/api/v3/system/status", r.InternalAddress), nil, &status)
+	if err != nil {
+		return "", err
+	}
+
+	return status.Version, nil
+}
+
+type SystemStatus struct {
+	Version string `json:"version"`
+}
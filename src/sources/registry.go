@@ -0,0 +1,108 @@
+package sources
+
+import (
+	"net/url"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+var registry = map[string]*Integration{}
+
+// AlarmsFilterKey is the key of the variable added to the integrations with alarms to filter
+// the alarms of each instance, like SONARR_ALARMS_FILTER
+const AlarmsFilterKey = "ALARMS_FILTER"
+
+// Integration is a source registered in the API.
+// Every field besides Name and Title is optional, the routes and
+// the alarms iFrame only use the capabilities the integration has.
+type Integration struct {
+	// Name is used in the routes and query parameters, like "radarr"
+	Name string
+	// Title is the human-readable name, like "Radarr"
+	Title string
+	// Config is the configuration schema of the integration
+	Config config.Schema
+	// Alarms returns the alarms of an instance of the integration, "" being the default instance.
+	// params are the query parameters of the alarms iFrame request.
+	Alarms func(instance string, params url.Values) ([]Alarm, error)
+	// IFrame handles the /v1/iframe/<name> route
+	IFrame gin.HandlerFunc
+	// Hash handles the /v1/hash/<name> route
+	Hash gin.HandlerFunc
+	// Data handles the /v1/data/<name> route, returning the iFrame data as JSON
+	Data gin.HandlerFunc
+	// Check does an authenticated request to an instance of the integration and returns the version
+	// of the source, or "" if it can't be got. It's used by the /v1/status route and the doctor command.
+	Check func(instance string) (version string, err error)
+	// Actions are routes under /v1/iframe/<name>/ used by the iFrame buttons
+	Actions []Action
+}
+
+// Action is a route used by an iFrame to change something in the source,
+// like setting a task as done. The action routes require authentication, see ActionToken.
+type Action struct {
+	// Method is the HTTP method, like http.MethodPatch
+	Method string
+	// Path is appended to /v1/iframe/<name>/, like "set_task_done"
+	Path string
+	// Item is the query parameter with the ID of the item changed by the action, like "taskId".
+	// The action tokens embedded in the iFrame are only valid for this item.
+	Item    string
+	Handler gin.HandlerFunc
+}
+
+// Register registers an integration. It should be called in the
+// init function of the integration package, and panics if an
+// integration with the same name is already registered.
+func Register(integration Integration) {
+	if integration.Name == "" {
+		panic("sources: integration name is empty")
+	}
+	if _, exists := registry[integration.Name]; exists {
+		panic("sources: integration already registered: " + integration.Name)
+	}
+	if integration.Config.Prefix != "" {
+		if integration.Alarms != nil {
+			integration.Config.Vars = append(integration.Config.Vars, config.Var{Key: AlarmsFilterKey, Validate: config.ValidateFilter})
+		}
+		config.RegisterSchema(integration.Name, integration.Config)
+	}
+	if integration.IFrame != nil {
+		config.RegisterWidget(integration.Name)
+	}
+	registry[integration.Name] = &integration
+}
+
+// Get returns a registered integration by name
+func Get(name string) (*Integration, bool) {
+	integration, ok := registry[name]
+	return integration, ok
+}
+
+// All returns all registered integrations sorted by name
+func All() []*Integration {
+	integrations := make([]*Integration, 0, len(registry))
+	for _, integration := range registry {
+		integrations = append(integrations, integration)
+	}
+	sort.Slice(integrations, func(i, j int) bool {
+		return integrations[i].Name < integrations[j].Name
+	})
+
+	return integrations
+}
+
+// AlarmProviders returns the names of the integrations that have alarms, sorted by name
+func AlarmProviders() []string {
+	var names []string
+	for _, integration := range All() {
+		if integration.Alarms != nil {
+			names = append(names, integration.Name)
+		}
+	}
+
+	return names
+}
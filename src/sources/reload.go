@@ -0,0 +1,112 @@
+package sources
+
+import (
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/logging"
+)
+
+// configWatchInterval is how often the config file is checked for changes
+var configWatchInterval = 5 * time.Second
+
+// Reload reloads the configs and removes the clients of the integrations whose configs changed,
+// so they are created again with the new configs when used. If the new configs are invalid,
+// the current ones are kept. It returns the names of the changed integrations, sorted.
+func Reload() ([]string, error) {
+	oldConfigs, newConfigs, err := config.Reload()
+	if err != nil {
+		return nil, err
+	}
+	logging.Setup(newConfigs.Log)
+	getDefaultCache().SetInterval(newConfigs.IFrames.CacheRefreshInterval)
+
+	// The HTTP and iFrames configs are used by many integrations, like the alarms iFrame
+	changedAll := oldConfigs == nil ||
+		!reflect.DeepEqual(oldConfigs.HTTP, newConfigs.HTTP) ||
+		!reflect.DeepEqual(oldConfigs.IFrames, newConfigs.IFrames)
+
+	var changed []string
+	for _, integration := range All() {
+		if changedAll || !reflect.DeepEqual(oldConfigs.Sources[integration.Name], newConfigs.Sources[integration.Name]) {
+			changed = append(changed, integration.Name)
+			resetInstances(integration.Name)
+			resetHTTPClients(integration.Name)
+		}
+	}
+	if changedAll {
+		resetHTTPClients(NotifiersHTTPClient)
+	}
+
+	// iFrames like the alarms and media releases use the data of other integrations,
+	// so the cached data of every iFrame is cleared when any config changes
+	if len(changed) > 0 || !reflect.DeepEqual(oldConfigs.Widgets, newConfigs.Widgets) {
+		for _, integration := range All() {
+			InvalidateCache(integration.Name)
+		}
+	}
+	// The cached alarms have the iFrames filters and the maintenance windows applied
+	if changedAll || !reflect.DeepEqual(oldConfigs.Maintenance, newConfigs.Maintenance) ||
+		!reflect.DeepEqual(oldConfigs.Notifiers, newConfigs.Notifiers) {
+		InvalidateCache("alarms")
+	}
+
+	return changed, nil
+}
+
+// WatchConfigs reloads the configs when the API receives a SIGHUP signal or the config
+// file set in CONFIG_FILE changes. It blocks, so it should be called in a goroutine.
+func WatchConfigs() {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	ticker := time.NewTicker(configWatchInterval)
+	defer ticker.Stop()
+
+	lastState := configFileState()
+	for {
+		select {
+		case <-hangup:
+			reloadAndLog("SIGHUP received")
+		case <-ticker.C:
+			state := configFileState()
+			if state == lastState {
+				continue
+			}
+			lastState = state
+			reloadAndLog("config file changed")
+		}
+	}
+}
+
+func reloadAndLog(reason string) {
+	changed, err := Reload()
+	if err != nil {
+		slog.Error("error reloading configs, keeping the current ones", "reason", reason, "error", err)
+		return
+	}
+	slog.Info("configs reloaded", "reason", reason, "changed", changed)
+}
+
+// fileState is used to check if a file changed
+type fileState struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+func configFileState() fileState {
+	state := fileState{path: config.FilePath()}
+	if state.path == "" {
+		return state
+	}
+	if info, err := os.Stat(state.path); err == nil {
+		state.modTime, state.size = info.ModTime(), info.Size()
+	}
+
+	return state
+}
@@ -0,0 +1,125 @@
+package sources
+
+import (
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+func TestReload(t *testing.T) {
+	if _, ok := Get("reloadtest"); !ok {
+		Register(Integration{
+			Name: "reloadtest",
+			Config: config.Schema{
+				Prefix: "RELOADTEST",
+				Vars:   []config.Var{{Key: "ADDRESS"}},
+			},
+		})
+	}
+
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("RELOADTEST_ADDRESS", "http://first")
+	if err := config.SetConfigs(""); err != nil {
+		t.Fatal(err)
+	}
+
+	instances := NewInstances[string]("reloadtest")
+	newClient := func(string) (string, error) {
+		return config.Current().Source("reloadtest").Get("ADDRESS"), nil
+	}
+	if client, _ := instances.Get("", newClient); client != "http://first" {
+		t.Fatalf("expected http://first, got %s", client)
+	}
+
+	changed, err := Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(changed, "reloadtest") {
+		t.Fatalf("the integration should not change, got %v", changed)
+	}
+
+	t.Setenv("RELOADTEST_ADDRESS", "http://second")
+	changed, err = Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(changed, "reloadtest") {
+		t.Fatalf("expected the integration to change, got %v", changed)
+	}
+	if client, _ := instances.Get("", newClient); client != "http://second" {
+		t.Fatalf("expected the client to be created again, got %s", client)
+	}
+}
+
+func TestReloadCacheInterval(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("CACHE_REFRESH_INTERVAL", "1m")
+	if err := config.SetConfigs(""); err != nil {
+		t.Fatal(err)
+	}
+	cache := getDefaultCache()
+	cache.SetInterval(config.Current().IFrames.CacheRefreshInterval)
+
+	t.Setenv("CACHE_REFRESH_INTERVAL", "2m")
+	if _, err := Reload(); err != nil {
+		t.Fatal(err)
+	}
+	cache.mu.Lock()
+	interval := cache.interval
+	cache.mu.Unlock()
+	if interval != 2*time.Minute {
+		t.Fatalf("expected the reloaded interval of 2m, got %s", interval)
+	}
+}
+
+func TestReloadInvalidatesAlarms(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("CACHE_REFRESH_INTERVAL", "1m")
+	t.Setenv("NOTIFIER_PHONE_TYPE", "ntfy")
+	t.Setenv("NOTIFIER_PHONE_URL", "https://ntfy.sh/homelab")
+	t.Setenv("ALARMS_MAINTENANCE_BACKUPS_SCHEDULE", "mon-fri 02:00-03:00")
+	if err := config.SetConfigs(""); err != nil {
+		t.Fatal(err)
+	}
+	getDefaultCache().SetInterval(config.Current().IFrames.CacheRefreshInterval)
+	defer config.Set(nil)
+
+	var fetches int
+	fetch := func() (int, error) {
+		fetches++
+		return fetches, nil
+	}
+	key := CacheKey("alarms", url.Values{})
+	if _, err := Cached(key, fetch); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, key, value string
+		invalidated      bool
+	}{
+		{"unchanged configs", "", "", false},
+		{"maintenance window", "ALARMS_MAINTENANCE_BACKUPS_SCHEDULE", "sat 02:00-03:00", true},
+		{"notifier", "NOTIFIER_PHONE_URL", "https://ntfy.sh/other", true},
+		{"alarms filter", "ALARMS_FILTER", "NOT source=Pi-hole", true},
+	}
+	for _, test := range tests {
+		if test.key != "" {
+			t.Setenv(test.key, test.value)
+		}
+		if _, err := Reload(); err != nil {
+			t.Fatal(err)
+		}
+		before := fetches
+		if _, err := Cached(key, fetch); err != nil {
+			t.Fatal(err)
+		}
+		if invalidated := fetches > before; invalidated != test.invalidated {
+			t.Errorf("%s: expected the cached alarms to be invalidated: %v, got %v", test.name, test.invalidated, invalidated)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package sonarr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// GetIframeAlarms returns the Sonarr health issues as alarms
+func (s *Sonarr) GetIframeAlarms() ([]sources.Alarm, error) {
+	healthEntries, err := s.GetHealth()
+	if err != nil {
+		return nil, err
+	}
+
+	var alarms []sources.Alarm
+	for _, alarm := range healthEntries {
+		url := fmt.Sprintf("%s/system/status", s.Address)
+		if alarm.WikiURL != "" {
+			url = alarm.WikiURL
+		}
+		alarms = append(alarms, sources.Alarm{
+			Source:            "Sonarr",
+			BackgroundImgURL:  BackgroundImageURL,
+			BackgroundImgSize: 120,
+			Summary:           alarm.Message,
+			URL:               url,
+			Status:            strings.ToUpper(alarm.Type),
+			Property:          alarm.Source,
+		})
+	}
+
+	return alarms, nil
+}
@@ -2,45 +2,83 @@ package sonarr
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 	"github.com/diogovalentte/homarr-iframes/src/sources/radarr"
 )
 
 var (
-	s                  *Sonarr
+	instances          = sources.NewInstances[*Sonarr]("sonarr")
 	BackgroundImageURL = "https://avatars.githubusercontent.com/u/1082903"
 )
 
+func init() {
+	sources.Register(sources.Integration{
+		Name:  "sonarr",
+		Title: "Sonarr",
+		Config: config.Schema{
+			Prefix:        "SONARR",
+			MultiInstance: true,
+			Vars: []config.Var{
+				{Key: "ADDRESS", Required: true, Validate: config.ValidateURL},
+				{Key: "INTERNAL_ADDRESS", Validate: config.ValidateURL},
+				{Key: "API_KEY", Required: true, Secret: true},
+			},
+		},
+		Alarms: func(instance string, _ url.Values) ([]sources.Alarm, error) {
+			s, err := NewInstance(instance)
+			if err != nil {
+				return nil, err
+			}
+
+			return s.GetIframeAlarms()
+		},
+		Check: func(instance string) (string, error) {
+			s, err := NewInstance(instance)
+			if err != nil {
+				return "", err
+			}
+
+			return s.GetVersion()
+		},
+	})
+}
+
 type Sonarr struct {
+	// Instance is the name of the instance, "" for the default instance
+	Instance        string
 	Address         string
 	InternalAddress string
 	APIKey          string
 }
 
 func New() (*Sonarr, error) {
-	if s != nil {
-		return s, nil
-	}
-
-	newS := &Sonarr{}
-	err := newS.Init()
-	if err != nil {
-		return nil, err
-	}
-
-	s = newS
+	return NewInstance("")
+}
 
-	return s, nil
+// NewInstance returns the client of a named Sonarr instance, configured with the SONARR_<INSTANCE>_* variables
+func NewInstance(instance string) (*Sonarr, error) {
+	return instances.Get(instance, func(instance string) (*Sonarr, error) {
+		newClient := &Sonarr{Instance: instance}
+		err := newClient.Init()
+		if err != nil {
+			return nil, err
+		}
+
+		return newClient, nil
+	})
 }
 
 func (s *Sonarr) Init() error {
-	address, internalAddress, APIKey := config.GlobalConfigs.Sonarr.Address, config.GlobalConfigs.Sonarr.InternalAddress, config.GlobalConfigs.Sonarr.APIKey
-	if address == "" || APIKey == "" {
-		return fmt.Errorf("SONARR_ADDRESS and SONARR_API_KEY variables should be set")
+	sourceConfigs, err := config.Current().LoadInstance("sonarr", s.Instance)
+	if err != nil {
+		return err
 	}
+	address, internalAddress, APIKey := sourceConfigs.Get("ADDRESS"), sourceConfigs.Get("INTERNAL_ADDRESS"), sourceConfigs.Get("API_KEY")
 
 	s.Address = strings.TrimSuffix(address, "/")
 	if internalAddress == "" {
@@ -62,7 +100,7 @@ func (s *Sonarr) GetCalendar(unmonitored bool, startDate, endDate time.Time) ([]
 	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, int(time.Second-time.Nanosecond), endDate.Location())
 
 	var entries []*getSonarrCalendarEntryResponse
-	err := baseRequest("GET", fmt.Sprintf("%s/api/v3/calendar?start=%s&end=%s&unmonitored=%v&includeSeries=true", s.InternalAddress, startDate.Format("2006-01-02T15:04:05.000Z07:00"), endDate.Format("2006-01-02T15:04:05.000Z07:00"), unmonitored), nil, &entries)
+	err := s.baseRequest("GET", fmt.Sprintf("%s/api/v3/calendar?start=%s&end=%s&unmonitored=%v&includeSeries=true", s.InternalAddress, startDate.Format("2006-01-02T15:04:05.000Z07:00"), endDate.Format("2006-01-02T15:04:05.000Z07:00"), unmonitored), nil, &entries)
 	if err != nil {
 		return nil, err
 	}
@@ -77,6 +115,8 @@ type getSonarrCalendarEntryResponse struct {
 		Title     string                                `json:"title"`
 		TitleSlug string                                `json:"titleSlug"`
 		Images    []radarr.DefaultReleaseImagesResponse `json:"images"`
+		// Runtime is the episodes' runtime in minutes
+		Runtime int `json:"runtime"`
 	} `json:"series"`
 	HasFile       bool `json:"hasFile"`
 	SeasonNumber  int  `json:"seasonNumber"`
@@ -85,7 +125,7 @@ type getSonarrCalendarEntryResponse struct {
 
 func (s *Sonarr) GetHealth() ([]*HealthEntry, error) {
 	var entries []*HealthEntry
-	err := baseRequest("GET", fmt.Sprintf("%s/api/v3/health", s.InternalAddress), nil, &entries)
+	err := s.baseRequest("GET", fmt.Sprintf("%s/api/v3/health", s.InternalAddress), nil, &entries)
 	if err != nil {
 		return nil, err
 	}
@@ -99,3 +139,18 @@ type HealthEntry struct {
 	Message string `json:"message"`
 	WikiURL string `json:"wikiUrl"`
 }
+
+// GetVersion returns the Sonarr version
+func (s *Sonarr) GetVersion() (string, error) {
+	var status SystemStatus
+	err := s.baseRequest("GET", fmt.Sprintf("%s/api/v3/system/status", s.InternalAddress), nil, &status)
+	if err != nil {
+		return "", err
+	}
+
+	return status.Version, nil
+}
+
+type SystemStatus struct {
+	Version string `json:"version"`
+}
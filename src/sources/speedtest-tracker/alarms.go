@@ -0,0 +1,96 @@
+package speedtesttracker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// GetIframeAlarms returns an alarm if the latest speedtest failed or breached a threshold
+func (s *SpeedTestTracker) GetIframeAlarms() ([]sources.Alarm, error) {
+	test, err := s.GetLatestTest()
+	if err != nil {
+		return nil, err
+	}
+
+	// test failed
+	if test.Status == "failed" {
+		layout := "2006-01-02 15:04:05"
+		updatedAt, err := time.Parse(layout, test.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		updatedAt = updatedAt.In(time.Local)
+
+		url := s.Address + "/admin/results"
+
+		alarms := []sources.Alarm{{
+			Time:            updatedAt,
+			Summary:         "Last Speedtest Failed",
+			URL:             url,
+			Status:          strings.ToUpper(test.Data.Level),
+			Value:           test.Service,
+			Property:        test.Data.Message,
+			Source:          "SpeedTest",
+			BackgroundColor: "black",
+		}}
+
+		return alarms, nil
+	} else if test.Status == "running" {
+		return []sources.Alarm{}, nil
+	}
+
+	// threshold breached
+	alarms := []sources.Alarm{}
+	if !test.Healthy && test.Status == "completed" {
+		layout := "2006-01-02 15:04:05"
+		updatedAt, err := time.Parse(layout, test.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		updatedAt = updatedAt.In(time.Local)
+
+		url := s.Address + "/admin/results"
+
+		if !test.Benchmarks.Download.Passed {
+			alarms = append(alarms, sources.Alarm{
+				Time:            updatedAt,
+				Summary:         "Speedtest Threshold Breached",
+				URL:             url,
+				Status:          "DOWNLOAD",
+				Value:           test.DownloadBitsHuman,
+				Property:        test.Data.ISP,
+				Source:          "SpeedTest",
+				BackgroundColor: "black",
+			})
+		}
+		if !test.Benchmarks.Upload.Passed {
+			alarms = append(alarms, sources.Alarm{
+				Time:            updatedAt,
+				Summary:         "Speedtest Threshold Breached",
+				URL:             url,
+				Status:          "UPLOAD",
+				Value:           test.UploadBitsHuman,
+				Property:        test.Data.ISP,
+				Source:          "SpeedTest",
+				BackgroundColor: "black",
+			})
+		}
+		if !test.Benchmarks.Ping.Passed {
+			alarms = append(alarms, sources.Alarm{
+				Time:            updatedAt,
+				Summary:         "Speedtest Threshold Breached",
+				URL:             url,
+				Status:          "PING",
+				Value:           fmt.Sprintf("%.2f ms", test.Ping),
+				Property:        test.Data.ISP,
+				Source:          "SpeedTest",
+				BackgroundColor: "black",
+			})
+		}
+	}
+
+	return alarms, nil
+}
@@ -2,40 +2,79 @@ package speedtesttracker
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/diogovalentte/homarr-iframes/src/config"
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
-var s *SpeedTestTracker
+var instances = sources.NewInstances[*SpeedTestTracker]("speedtest-tracker")
+
+func init() {
+	sources.Register(sources.Integration{
+		Name:  "speedtest-tracker",
+		Title: "SpeedTest Tracker",
+		Config: config.Schema{
+			Prefix:        "SPEEDTEST_TRACKER",
+			MultiInstance: true,
+			Vars: []config.Var{
+				{Key: "ADDRESS", Required: true, Validate: config.ValidateURL},
+				{Key: "INTERNAL_ADDRESS", Validate: config.ValidateURL},
+				{Key: "TOKEN", Required: true, Secret: true},
+			},
+		},
+		Alarms: func(instance string, _ url.Values) ([]sources.Alarm, error) {
+			s, err := NewInstance(instance)
+			if err != nil {
+				return nil, err
+			}
+
+			return s.GetIframeAlarms()
+		},
+		Check: func(instance string) (string, error) {
+			s, err := NewInstance(instance)
+			if err != nil {
+				return "", err
+			}
+			_, err = s.GetLatestTest()
+
+			return "", err
+		},
+	})
+}
 
 type SpeedTestTracker struct {
+	// Instance is the name of the instance, "" for the default instance
+	Instance        string
 	Address         string
 	InternalAddress string
 	token           string
 }
 
 func New() (*SpeedTestTracker, error) {
-	if s != nil {
-		return s, nil
-	}
-
-	newR := &SpeedTestTracker{}
-	err := newR.Init()
-	if err != nil {
-		return nil, err
-	}
-
-	s = newR
+	return NewInstance("")
+}
 
-	return s, nil
+// NewInstance returns the client of a named SpeedTest Tracker instance, configured with the SPEEDTEST_TRACKER_<INSTANCE>_* variables
+func NewInstance(instance string) (*SpeedTestTracker, error) {
+	return instances.Get(instance, func(instance string) (*SpeedTestTracker, error) {
+		newClient := &SpeedTestTracker{Instance: instance}
+		err := newClient.Init()
+		if err != nil {
+			return nil, err
+		}
+
+		return newClient, nil
+	})
 }
 
 func (r *SpeedTestTracker) Init() error {
-	address, internalAddress, token := config.GlobalConfigs.SpeedTestTrackerConfigs.Address, config.GlobalConfigs.SpeedTestTrackerConfigs.InternalAddress, config.GlobalConfigs.SpeedTestTrackerConfigs.Token
-	if address == "" || token == "" {
-		return fmt.Errorf("SPEEDTEST_TRACKER_ADDRESS and SPEEDTEST_TRACKER_TOKEN variables should be set")
+	sourceConfigs, err := config.Current().LoadInstance("speedtest-tracker", r.Instance)
+	if err != nil {
+		return err
 	}
+	address, internalAddress, token := sourceConfigs.Get("ADDRESS"), sourceConfigs.Get("INTERNAL_ADDRESS"), sourceConfigs.Get("TOKEN")
 
 	r.Address = strings.TrimSuffix(address, "/")
 	if internalAddress == "" {
@@ -0,0 +1,189 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+// Status is the status of a configured instance of an integration,
+// used to find out why an iFrame doesn't work
+type Status struct {
+	Integration string `json:"integration"`
+	// Instance is the instance name, "" for the default instance
+	Instance string `json:"instance"`
+	// Configured is true if the required variables of the instance are set
+	Configured bool `json:"configured"`
+	// Reachable is true if the address of the instance responded to a request
+	Reachable bool `json:"reachable"`
+	// Authenticated is true if an authenticated request worked, and nil if it wasn't checked,
+	// because the integration doesn't have a check or the instance isn't reachable
+	Authenticated *bool `json:"authenticated"`
+	// Version is the version of the source, if the integration can get it
+	Version string `json:"version,omitempty"`
+	// LatencyMS is the round-trip time of the reachability request in milliseconds
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// OK returns true if the instance is configured, reachable, and authenticated (if checked)
+func (s Status) OK() bool {
+	return s.Configured && s.Reachable && (s.Authenticated == nil || *s.Authenticated)
+}
+
+// Statuses returns the status of every configured instance of the integrations with a config schema,
+// sorted by integration and instance. An instance is configured if any of its variables is set.
+// The instances are checked concurrently.
+func Statuses() []Status {
+	var statuses []Status
+	for _, integration := range All() {
+		for _, instance := range ConfiguredInstances(integration.Name) {
+			statuses = append(statuses, Status{Integration: integration.Name, Instance: instance})
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := range statuses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			integration, _ := Get(statuses[i].Integration)
+			checkStatus(integration, &statuses[i])
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Integration != statuses[j].Integration {
+			return statuses[i].Integration < statuses[j].Integration
+		}
+		return statuses[i].Instance < statuses[j].Instance
+	})
+
+	return statuses
+}
+
+// ConfiguredInstances returns the instances of an integration with any variable set, sorted.
+// Integrations without a config schema have no configured instances.
+func ConfiguredInstances(name string) []string {
+	integration, ok := Get(name)
+	if !ok || integration.Config.Prefix == "" {
+		return nil
+	}
+
+	configs := config.Current()
+	var instances []string
+	for _, instance := range configs.Instances(name) {
+		if instanceConfigured(integration.Config, configs.Instance(name, instance)) {
+			instances = append(instances, instance)
+		}
+	}
+
+	return instances
+}
+
+// instanceConfigured returns true if any variable of the instance is set to a non-default value
+func instanceConfigured(schema config.Schema, sourceConfigs config.SourceConfigs) bool {
+	for _, v := range schema.Vars {
+		if value := sourceConfigs.Get(v.Key); value != "" && value != v.Default {
+			return true
+		}
+	}
+
+	return false
+}
+
+func checkStatus(integration *Integration, status *Status) {
+	sourceConfigs, err := config.Current().LoadInstance(status.Integration, status.Instance)
+	if err != nil {
+		status.Error = config.Redact(err.Error())
+		return
+	}
+	status.Configured = true
+
+	address := sourceConfigs.Get("INTERNAL_ADDRESS")
+	if address == "" {
+		address = sourceConfigs.Get("ADDRESS")
+	}
+	if address != "" {
+		latency, err := ping(HTTPClient(status.Integration, status.Instance), address)
+		status.LatencyMS = latency.Milliseconds()
+		if err != nil {
+			status.Error = config.Redact(err.Error())
+			return
+		}
+	}
+	status.Reachable = true
+
+	if integration.Check == nil {
+		return
+	}
+	version, err := integration.Check(status.Instance)
+	authenticated := err == nil
+	status.Authenticated = &authenticated
+	status.Version = version
+	if err != nil {
+		status.Error = config.Redact(err.Error())
+	}
+}
+
+// ping requests an address and returns the round-trip time. Any response means the address is reachable.
+// The request isn't retried, so the latency is the one of a single request, without the retries backoff.
+func ping(client *http.Client, address string) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(withoutRetries(context.Background()), http.MethodGet, address, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error creating request: %w", err)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return latency, fmt.Errorf("error sending request: %w", err)
+	}
+	resp.Body.Close()
+
+	return latency, nil
+}
+
+// WriteStatusTable writes the statuses as a table
+func WriteStatusTable(w io.Writer, statuses []Status) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "INTEGRATION\tINSTANCE\tCONFIGURED\tREACHABLE\tAUTHENTICATED\tVERSION\tLATENCY\tERROR")
+	for _, status := range statuses {
+		instance, authenticated, version, latency := status.Instance, "-", status.Version, "-"
+		if instance == "" {
+			instance = "default"
+		}
+		if status.Authenticated != nil {
+			authenticated = yesNo(*status.Authenticated)
+		}
+		if version == "" {
+			version = "-"
+		}
+		if status.Configured {
+			latency = fmt.Sprintf("%dms", status.LatencyMS)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			status.Integration, instance, yesNo(status.Configured), yesNo(status.Reachable),
+			authenticated, version, latency, strings.ReplaceAll(status.Error, "\n", " "))
+	}
+
+	return tw.Flush()
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+
+	return "no"
+}
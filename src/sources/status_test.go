@@ -0,0 +1,101 @@
+package sources
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/diogovalentte/homarr-iframes/src/config"
+)
+
+func TestStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	if _, ok := Get("statustest"); !ok {
+		Register(Integration{
+			Name: "statustest",
+			Config: config.Schema{
+				Prefix:        "STATUSTEST",
+				MultiInstance: true,
+				Vars: []config.Var{
+					{Key: "ADDRESS", Required: true},
+					{Key: "API_KEY", Required: true},
+				},
+			},
+			Check: func(instance string) (string, error) {
+				if config.Current().Instance("statustest", instance).Get("API_KEY") != "valid" {
+					return "", errors.New("invalid API key")
+				}
+				return "1.2.3", nil
+			},
+		})
+	}
+
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("STATUSTEST_ADDRESS", server.URL)
+	t.Setenv("STATUSTEST_API_KEY", "valid")
+	t.Setenv("STATUSTEST_BADKEY_ADDRESS", server.URL)
+	t.Setenv("STATUSTEST_BADKEY_API_KEY", "invalid")
+	t.Setenv("STATUSTEST_NOKEY_ADDRESS", server.URL)
+	t.Setenv("STATUSTEST_DOWN_ADDRESS", "http://127.0.0.1:1")
+	t.Setenv("STATUSTEST_DOWN_API_KEY", "valid")
+	t.Setenv("HTTP_RETRIES", "0")
+	if err := config.SetConfigs(""); err != nil {
+		t.Fatal(err)
+	}
+	defer config.Set(nil)
+
+	statuses := map[string]Status{}
+	for _, status := range Statuses() {
+		if status.Integration == "statustest" {
+			statuses[status.Instance] = status
+		}
+	}
+
+	if status := statuses[""]; !status.OK() || status.Version != "1.2.3" {
+		t.Errorf("expected the default instance to be OK with version 1.2.3, got %+v", status)
+	}
+	if status := statuses["badkey"]; status.OK() || !status.Reachable || status.Authenticated == nil || *status.Authenticated {
+		t.Errorf("expected the badkey instance to be reachable but not authenticated, got %+v", status)
+	}
+	if status := statuses["nokey"]; status.Configured || !strings.Contains(status.Error, "STATUSTEST_NOKEY_API_KEY") {
+		t.Errorf("expected the nokey instance to not be configured, got %+v", status)
+	}
+	if status := statuses["down"]; status.Reachable || status.Authenticated != nil {
+		t.Errorf("expected the down instance to not be reachable, got %+v", status)
+	}
+
+	var table strings.Builder
+	if err := WriteStatusTable(&table, []Status{statuses[""]}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(table.String(), "statustest") || !strings.Contains(table.String(), "1.2.3") {
+		t.Errorf("unexpected table:\n%s", table.String())
+	}
+}
+
+func TestPingWithoutRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := NewHTTPClient(config.HTTPConfigs{Timeout: 10 * time.Second, Retries: 3, RetryBackoff: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	latency, err := ping(client, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := requests.Load(); n != 1 || latency >= time.Second {
+		t.Errorf("expected a single request without the retries backoff, got %d requests in %s", n, latency)
+	}
+}
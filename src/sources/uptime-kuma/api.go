@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
 // GetStatusPageLastUpDownCount returns the number of up and down sites for the last heartbeat of a status page
@@ -36,7 +38,7 @@ func (u *UptimeKuma) GetStatusPageLastUpDownCount(slug string) (*UpDownSites, er
 }
 
 func (u *UptimeKuma) baseRequest(url string, target any) error {
-	client := &http.Client{}
+	client := sources.HTTPClient("uptimekuma", "")
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
@@ -0,0 +1,28 @@
+package uptimekuma
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// Data is the response of the /v1/data/uptimekuma route
+type Data struct {
+	Version int `json:"version"`
+	// Up is the number of sites up in the last heartbeat of the status page
+	Up int `json:"up"`
+	// Down is the number of sites down in the last heartbeat of the status page
+	Down int `json:"down"`
+}
+
+// GetData returns the number of up and down sites as JSON
+func (u *UptimeKuma) GetData(c *gin.Context) {
+	upDownSites, ok := u.getQueryData(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, Data{Version: sources.DataVersion, Up: upDownSites.Up, Down: upDownSites.Down})
+}
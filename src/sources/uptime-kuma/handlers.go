@@ -0,0 +1,56 @@
+package uptimekuma
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Uptime Kuma iFrame
+// @Description Returns an iFrame with Uptime Kuma sites overview.
+// @Success 200 {string} string "HTML content"
+// @Produce html
+// @Param slug query string true "You need to create a status page in Uptime Kuma and select which sites/services this status page will show. While creating the status page, it'll request **you** to create a slug, after creating the status page, provide this slug here. This iFrame will show data only of the sites/services of this specific status page!" Example(uptime-kuma-slug)
+// @Param theme query string false "Homarr theme, defaults to light. If it's different from your Homarr theme, the background turns white" Example(light)
+// @Param api_url query string true "API URL used by your browser. Used by the iFrames to receive updates, if there is an update, the iFrame updates the changed items without reloading. If not specified, the iFrames will never try to reload." Example(https://sub.domain.com)
+// @Param showTitle query bool false "Show the title 'Uptime Kuma' on the iFrame." Example(true)
+// @Param orientation query string false "Orientation of the containers, defaults to horizontal." Example(vertical)
+// @Router /iframe/uptimekuma [get]
+func iFrameHandler(c *gin.Context) {
+	u, err := New()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	u.GetiFrame(c)
+}
+
+// @Summary Get the hash of the Uptime Kuma sites status
+// @Description Get the hash of the Uptime Kuma sites status. Used by the iFrames to check updates and reload the iframe.
+// @Success 200 {object} sources.HashResponse
+// @Produce json
+// @Param slug query string true "You need to create a status page in Uptime Kuma and select which sites/services this status page will show. While creating the status page, it'll request **you** to create a slug, after creating the status page, provide this slug here. This iFrame will show data only of the sites/services of this specific status page!" Example(uptime-kuma-slug)
+// @Router /hash/uptimekuma [get]
+func hashHandler(c *gin.Context) {
+	u, err := New()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	u.GetHash(c)
+}
+
+// @Summary Get the Uptime Kuma sites status data
+// @Description Get the number of up and down sites of an Uptime Kuma status page as JSON.
+// @Success 200 {object} Data
+// @Produce json
+// @Param slug query string true "Slug of the Uptime Kuma status page." Example(uptime-kuma-slug)
+// @Router /data/uptimekuma [get]
+func dataHandler(c *gin.Context) {
+	u, err := New()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	u.GetData(c)
+}
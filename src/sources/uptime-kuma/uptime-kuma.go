@@ -12,10 +12,28 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/diogovalentte/homarr-iframes/src/config"
 	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
-var u *UptimeKuma
+var instances = sources.NewInstances[*UptimeKuma]("uptimekuma")
+
+func init() {
+	sources.Register(sources.Integration{
+		Name:  "uptimekuma",
+		Title: "Uptime Kuma",
+		Config: config.Schema{
+			Prefix: "UPTIMEKUMA",
+			Vars: []config.Var{
+				{Key: "ADDRESS", Required: true, Validate: config.ValidateURL},
+				{Key: "INTERNAL_ADDRESS", Validate: config.ValidateURL},
+			},
+		},
+		IFrame: iFrameHandler,
+		Hash:   hashHandler,
+		Data:   dataHandler,
+	})
+}
 
 // UptimeKuma is the UptimeKuma source
 type UptimeKuma struct {
@@ -23,27 +41,25 @@ type UptimeKuma struct {
 	InternalAddress string
 }
 
-func New(address, internalAddress string) (*UptimeKuma, error) {
-	if u != nil {
-		return u, nil
-	}
-
-	newU := &UptimeKuma{}
-	err := newU.Init(address, internalAddress)
-	if err != nil {
-		return nil, err
-	}
-
-	u = newU
+func New() (*UptimeKuma, error) {
+	return instances.Get("", func(string) (*UptimeKuma, error) {
+		newU := &UptimeKuma{}
+		err := newU.Init()
+		if err != nil {
+			return nil, err
+		}
 
-	return u, nil
+		return newU, nil
+	})
 }
 
 // Init sets the UptimeKuma properties from the configs
-func (u *UptimeKuma) Init(address, internalAddress string) error {
-	if address == "" {
-		return fmt.Errorf("UPTIMEKUMA_ADDRESS variable should be set")
+func (u *UptimeKuma) Init() error {
+	sourceConfigs, err := config.Current().LoadSource("uptimekuma")
+	if err != nil {
+		return err
 	}
+	address, internalAddress := sourceConfigs.Get("ADDRESS"), sourceConfigs.Get("INTERNAL_ADDRESS")
 
 	u.Address = strings.TrimSuffix(address, "/")
 	if internalAddress == "" {
@@ -99,13 +115,13 @@ func (u *UptimeKuma) GetiFrame(c *gin.Context) {
 		return
 	}
 
-	upDownSites, err := u.GetStatusPageLastUpDownCount(slug)
+	upDownSites, err := u.getUpDownSites(c.Request.URL.Query(), slug)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
 
-	html, err := getUpDownSitesiFrame(upDownSites, theme, apiURL, slug, containersDisplay, showTitle)
+	html, err := getUpDownSitesiFrame(upDownSites, theme, apiURL, containersDisplay, showTitle)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -114,7 +130,7 @@ func (u *UptimeKuma) GetiFrame(c *gin.Context) {
 	c.Data(http.StatusOK, "text/html", html)
 }
 
-func getUpDownSitesiFrame(upDownSites *UpDownSites, theme, apiURL, slug, containersDisplay string, showTitle bool) ([]byte, error) {
+func getUpDownSitesiFrame(upDownSites *UpDownSites, theme, apiURL, containersDisplay string, showTitle bool) ([]byte, error) {
 	html := `
 <!doctype html>
 <html lang="en">
@@ -150,37 +166,7 @@ func getUpDownSitesiFrame(upDownSites *UpDownSites, theme, apiURL, slug, contain
         }
     </style>
 
-    <script>
-        let lastHash = null;
-
-        async function fetchData() {
-            try {
-                var url = '{{ .APIURL }}/v1/hash/uptimekuma?slug={{ .Slug }}';
-                const response = await fetch(url);
-                const data = await response.json();
-
-                if (lastHash === null) {
-                    lastHash = data.hash;
-                } else {
-                    if (data.hash !== lastHash) {
-                        lastHash = data.hash;
-                        location.reload();
-                    }
-                }
-            } catch (error) {
-                console.error('Error getting last update from the API:', error);
-            }
-        }
-
-        function fetchAndUpdate() {
-            fetchData();
-            setTimeout(fetchAndUpdate, 5000); // 5 seconds
-        }
-
-        {{ if .APIURL }}
-            fetchAndUpdate();
-        {{ end }}
-    </script>
+    {{ .LiveUpdates }}
 
 </head>
 <body>
@@ -466,8 +452,7 @@ func getUpDownSitesiFrame(upDownSites *UpDownSites, theme, apiURL, slug, contain
 
 	templateData := iframeTemplateData{
 		Theme:                         theme,
-		APIURL:                        apiURL,
-		Slug:                          slug,
+		LiveUpdates:                   sources.LiveUpdatesScript(apiURL, "uptimekuma"),
 		ScrollbarThumbBackgroundColor: scrollbarThumbBackgroundColor,
 		ScrollbarTrackBackgroundColor: scrollbarTrackBackgroundColor,
 		CSSCode:                       template.CSS(CSSCode),
@@ -496,8 +481,7 @@ func getUpDownSitesiFrame(upDownSites *UpDownSites, theme, apiURL, slug, contain
 
 type iframeTemplateData struct {
 	Theme                         string
-	APIURL                        string
-	Slug                          string
+	LiveUpdates                   template.HTML
 	ScrollbarThumbBackgroundColor string
 	ScrollbarTrackBackgroundColor string
 	CSSCode                       template.CSS
@@ -511,19 +495,39 @@ type iframeTemplateData struct {
 
 // GetHash returns the hash of the up/down sites
 func (u *UptimeKuma) GetHash(c *gin.Context) {
+	upDownSites, ok := u.getQueryData(c)
+	if !ok {
+		return
+	}
+
+	hash := sources.GetHash(upDownSites, time.Now().Format("2006-01-02"))
+
+	c.JSON(http.StatusOK, gin.H{"hash": fmt.Sprintf("%x", hash)})
+}
+
+// getQueryData parses the query parameters used by the hash and data routes and returns the up/down sites.
+// If it fails, it writes the error response and returns false.
+func (u *UptimeKuma) getQueryData(c *gin.Context) (*UpDownSites, bool) {
 	slug := c.Query("slug")
 	if slug == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"message": "slug must be provided"})
-		return
+		return nil, false
 	}
 
-	upDownSites, err := u.GetStatusPageLastUpDownCount(slug)
+	upDownSites, err := u.getUpDownSites(c.Request.URL.Query(), slug)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-		return
+		return nil, false
 	}
 
-	hash := sources.GetHash(upDownSites, time.Now().Format("2006-01-02"))
+	return upDownSites, true
+}
 
-	c.JSON(http.StatusOK, gin.H{"hash": fmt.Sprintf("%x", hash)})
+// getUpDownSites returns the up/down sites used by the iFrame and hash routes.
+// They are cached by the query parameters that change them.
+func (u *UptimeKuma) getUpDownSites(query url.Values, slug string) (*UpDownSites, error) {
+	key := sources.CacheKey("uptimekuma", query, "slug")
+	return sources.Cached(key, func() (*UpDownSites, error) {
+		return u.GetStatusPageLastUpDownCount(slug)
+	})
 }
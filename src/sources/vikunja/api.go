@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
 )
 
 // GetTasks get not done tasks with using a custom ordering.
@@ -173,7 +175,7 @@ func (v *Vikunja) GetProject(projectID int) (*Project, error) {
 }
 
 func (v *Vikunja) baseRequest(method, url string, body io.Reader, target any) error {
-	client := &http.Client{}
+	client := sources.HTTPClient("vikunja", "")
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
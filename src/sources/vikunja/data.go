@@ -0,0 +1,88 @@
+package vikunja
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// Data is the response of the /v1/data/vikunja route
+type Data struct {
+	Version int        `json:"version"`
+	Tasks   []TaskData `json:"tasks"`
+}
+
+// TaskData is a task in the /v1/data/vikunja route
+type TaskData struct {
+	ID          int          `json:"id"`
+	Title       string       `json:"title"`
+	URL         string       `json:"url"`
+	Done        bool         `json:"done"`
+	CreatedAt   time.Time    `json:"created_at"`
+	DueDate     *time.Time   `json:"due_date,omitempty"`
+	EndDate     *time.Time   `json:"end_date,omitempty"`
+	Priority    int          `json:"priority"`
+	RepeatAfter int          `json:"repeat_after"`
+	RepeatMode  int          `json:"repeat_mode"`
+	IsFavorite  bool         `json:"is_favorite"`
+	Project     *ProjectData `json:"project,omitempty"`
+	Labels      []LabelData  `json:"labels"`
+}
+
+// ProjectData is the project of a task in the /v1/data/vikunja route
+type ProjectData struct {
+	ID       int    `json:"id"`
+	Title    string `json:"title"`
+	HexColor string `json:"hex_color"`
+}
+
+// LabelData is a label of a task in the /v1/data/vikunja route
+type LabelData struct {
+	ID       int    `json:"id"`
+	Title    string `json:"title"`
+	HexColor string `json:"hex_color"`
+}
+
+// GetData returns the tasks as JSON
+func (v *Vikunja) GetData(c *gin.Context) {
+	data, ok := v.getQueryData(c)
+	if !ok {
+		return
+	}
+
+	projects := make(map[int]*Project)
+	for _, project := range data.Projects {
+		projects[project.ID] = project
+	}
+
+	response := Data{Version: sources.DataVersion, Tasks: []TaskData{}}
+	for _, task := range data.Tasks {
+		taskData := TaskData{
+			ID:          task.ID,
+			Title:       task.Title,
+			URL:         v.Address + "/tasks/" + strconv.Itoa(task.ID),
+			Done:        task.Done,
+			CreatedAt:   task.CreatedAt,
+			DueDate:     sources.OptionalTime(task.DueDate),
+			EndDate:     sources.OptionalTime(task.EndDate),
+			Priority:    task.Priority,
+			RepeatAfter: task.RepeatAfter,
+			RepeatMode:  task.RepeatMode,
+			IsFavorite:  task.IsFavorite,
+			Labels:      []LabelData{},
+		}
+		if project, ok := projects[task.ProjectID]; ok {
+			taskData.Project = &ProjectData{ID: project.ID, Title: project.Title, HexColor: project.HexColor}
+		}
+		for _, label := range task.Labels {
+			taskData.Labels = append(taskData.Labels, LabelData{ID: label.ID, Title: label.Title, HexColor: label.HexColor})
+		}
+		response.Tasks = append(response.Tasks, taskData)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
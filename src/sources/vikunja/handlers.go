@@ -0,0 +1,107 @@
+package vikunja
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/diogovalentte/homarr-iframes/src/sources"
+)
+
+// @Summary Vikunja tasks iFrame
+// @Description Returns an iFrame with not done Vikunja tasks. Uses a custom sort/order: due date (asc); end date (asc); priority (desc); created date (desc). When the due/end date is today, the date color will be orange, if it's past due, the date color will be red.
+// @Success 200 {string} string "HTML content"
+// @Produce html
+// @Param theme query string false "Homarr theme, defaults to light. If it's different from your Homarr theme, the background turns white" Example(light)
+// @Param limit query int false "Limits the number of items in the iFrame." Example(5)
+// @Param project_id query int false "Project ID to get tasks from. You can get it by going to the project page in Vikunja, the project ID should be on the URL. Example project page URL: https://vikunja.com/projects/2, the project ID is 2. Inbox tasks = 1, Favorite tasks = -1." Example(1)
+// @Param exclude_project_ids query string false "Project IDs to NOT get tasks from. You can get it by going to the project page in Vikunja, the project ID should be on the URL. Example project page URL: https://vikunja.com/projects/2, the project ID is 2. Inbox tasks = 1, Favorite tasks = -1." Example(1,5,7)
+// @Param api_url query string true "API URL used by your browser. Used by the iFrames to receive updates, if there is an update, the iFrame updates the changed items without reloading. If not specified, the iFrames will never try to reload. Also used by the button to set the task done, if not provided, the button will not appear (the button doesn't appear in repeating tasks.)" Example(https://sub.domain.com)
+// @Param showCreated query bool false "Shows the tasks' created date. Defaults to true." Example(false)
+// @Param showDue query bool false "Shows the tasks' due/end date and repeating dates. Defaults to true." Example(false)
+// @Param showPriority query bool false "Shows the tasks' priority. Defaults to true." Example(false)
+// @Param showProject query bool false "Shows the tasks' project. Defaults to true." Example(false)
+// @Param showFavoriteIcon query bool false "Shows a start icon in favorite tasks. Defaults to true." Example(false)
+// @Param showLabels query bool false "Shows the tasks' labels. Defaults to true." Example(false)
+// @Param background_position query string false "Background position of each task card. Use '%25' in place of '%', like '50%25 47.2%25' to get '50% 47.2%'. Defaults to 50% 49.5%." Example(top)
+// @Param background_size query string false "Background size of each task card. Use '%25' in place of '%'. Defaults to 105%." Example(105%25)
+// @Param background_filter query string false "Background filter of each task card. Use '%25' in place of '%'. Defaults to brightness(0.3)." Example(blur(5px))
+// @Router /iframe/vikunja [get]
+func iFrameHandler(c *gin.Context) {
+	v, err := New()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	v.GetiFrame(c)
+}
+
+// @Summary Get the hash of the Vikunja tasks
+// @Description Get the hash of the Vikunja tasks. Used by the iFrames to check updates and reload the iframe.
+// @Success 200 {object} sources.HashResponse
+// @Produce json
+// @Param limit query int false "Limits the number of items in the iFrame." Example(5)
+// @Param project_id query int false "Project ID to get tasks from. You can get it by going to the project page in Vikunja, the project ID should be on the URL. Example project page URL: https://vikunja.com/projects/2, the project ID is 2. Inbox tasks = 1, Favorite tasks = -1." Example(1)
+// @Router /hash/vikunja [get]
+func hashHandler(c *gin.Context) {
+	v, err := New()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	v.GetHash(c)
+}
+
+// @Summary Get the Vikunja tasks data
+// @Description Get the not done Vikunja tasks as JSON, in the same order as the iFrame. The due and end dates are omitted if the task doesn't have them.
+// @Success 200 {object} Data
+// @Produce json
+// @Param limit query int false "Limits the number of items." Example(5)
+// @Param project_id query int false "Project ID to get tasks from. Inbox tasks = 1, Favorite tasks = -1." Example(1)
+// @Param exclude_project_ids query string false "Project IDs to NOT get tasks from." Example(1,5,7)
+// @Router /data/vikunja [get]
+func dataHandler(c *gin.Context) {
+	v, err := New()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	v.GetData(c)
+}
+
+// @Summary Set Vikunja task done
+// @Description Set a Vikunja task as done. Requires the action token of the task in the X-Action-Token header (embedded in the iFrame), the ACTIONS_SECRET as a bearer token, or the AUTH_TRUSTED_HEADER.
+// @Success 200 {object} sources.MessageResponse "Task done"
+// @Failure 401 {object} sources.MessageResponse "Unauthorized"
+// @Failure 403 {object} sources.MessageResponse "Origin not allowed"
+// @Produce json
+// @Param taskId query int true "The task ID." Example(1)
+// @Param X-Action-Token header string false "Action token of the task."
+// @Param Authorization header string false "Bearer ACTIONS_SECRET."
+// @Router /iframe/vikunja/set_task_done [patch]
+func setTaskDoneHandler(c *gin.Context) {
+	taskIDStr := c.Query("taskId")
+	if taskIDStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "taskId is required"})
+		return
+	}
+
+	taskID, err := strconv.Atoi(taskIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "taskId must be an integer"})
+	}
+
+	v, err := New()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	err = v.SetTaskDone(taskID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+	sources.InvalidateCache("vikunja")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task done"})
+}
@@ -18,7 +18,40 @@ import (
 
 var defaultBackgroundImgURL = "https://avatars.githubusercontent.com/u/41270016"
 
-var v *Vikunja
+var instances = sources.NewInstances[*Vikunja]("vikunja")
+
+func init() {
+	sources.Register(sources.Integration{
+		Name:  "vikunja",
+		Title: "Vikunja",
+		Config: config.Schema{
+			Prefix: "VIKUNJA",
+			Vars: []config.Var{
+				{Key: "ADDRESS", Required: true, Validate: config.ValidateURL},
+				{Key: "INTERNAL_ADDRESS", Validate: config.ValidateURL},
+				{Key: "TOKEN", Required: true, Secret: true},
+				{Key: "BACKGROUND_IMG_URL", Default: defaultBackgroundImgURL, Validate: config.ValidateURL},
+			},
+		},
+		IFrame: iFrameHandler,
+		Hash:   hashHandler,
+		Data:   dataHandler,
+		Check: func(string) (string, error) {
+			v, err := New()
+			if err != nil {
+				return "", err
+			}
+			if _, err := v.GetProjects(); err != nil {
+				return "", err
+			}
+
+			return v.getVikunjaVersion()
+		},
+		Actions: []sources.Action{
+			{Method: http.MethodPatch, Path: "set_task_done", Item: "taskId", Handler: setTaskDoneHandler},
+		},
+	})
+}
 
 type Vikunja struct {
 	Address          string
@@ -28,34 +61,24 @@ type Vikunja struct {
 }
 
 func New() (*Vikunja, error) {
-	if v != nil {
-		return v, nil
-	}
-
-	address := config.GlobalConfigs.Vikunja.Address
-	internalAddress := config.GlobalConfigs.Vikunja.InternalAddress
-	token := config.GlobalConfigs.Vikunja.Token
-	backgroundImgURL := config.GlobalConfigs.Vikunja.BackgroundImgURL
-	if backgroundImgURL == "" {
-		backgroundImgURL = defaultBackgroundImgURL
-	}
-
-	newV := &Vikunja{}
-	err := newV.Init(address, internalAddress, token, backgroundImgURL)
-	if err != nil {
-		return nil, err
-	}
-
-	v = newV
+	return instances.Get("", func(string) (*Vikunja, error) {
+		newV := &Vikunja{}
+		err := newV.Init()
+		if err != nil {
+			return nil, err
+		}
 
-	return v, nil
+		return newV, nil
+	})
 }
 
 // Init sets the Vikunja properties from the configs
-func (v *Vikunja) Init(address, internalAddress, token, backgroundImageURL string) error {
-	if address == "" || token == "" {
-		return fmt.Errorf("VIKUNJA_ADDRESS and VIKUNJA_TOKEN variables should be set")
+func (v *Vikunja) Init() error {
+	sourceConfigs, err := config.Current().LoadSource("vikunja")
+	if err != nil {
+		return err
 	}
+	address, internalAddress, token := sourceConfigs.Get("ADDRESS"), sourceConfigs.Get("INTERNAL_ADDRESS"), sourceConfigs.Get("TOKEN")
 
 	v.Address = strings.TrimSuffix(address, "/")
 	if internalAddress == "" {
@@ -64,7 +87,7 @@ func (v *Vikunja) Init(address, internalAddress, token, backgroundImageURL strin
 		v.InternalAddress = strings.TrimSuffix(internalAddress, "/")
 	}
 	v.Token = token
-	v.BackgroundImgURL = backgroundImageURL
+	v.BackgroundImgURL = sourceConfigs.Get("BACKGROUND_IMG_URL")
 
 	return nil
 }
@@ -209,34 +232,23 @@ func (v *Vikunja) GetiFrame(c *gin.Context) {
 		}
 	}
 
-	tasks := []*Task{}
-	if limit != 0 {
-		tasks, err = v.GetTasks(limit, projectID, excludeProjectIDs)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-			return
-		}
-	}
-
-	projects, err := v.GetProjects()
+	data, err := v.getData(c.Request.URL.Query(), limit, projectID, excludeProjectIDs)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
+	tasks := data.Tasks
+
 	instanceProjects := make(map[int]*Project)
-	for _, project := range projects {
+	for _, project := range data.Projects {
 		instanceProjects[project.ID] = project
 	}
 
 	var html []byte
 	if len(tasks) < 1 {
-		var apiURLPath string
-		if apiURL != "" {
-			apiURLPath = apiURL + "/v1/hash/vikunja?limit=" + strconv.Itoa(limit) + "&project_id=" + strconv.Itoa(projectID) + "&exclude_project_ids=" + queryExcludeProjectIDs
-		}
-		html = sources.GetBaseNothingToShowiFrame("#226fff", v.BackgroundImgURL, "center", "cover", backgroundFilter, apiURLPath)
+		html = sources.GetBaseNothingToShowiFrame("#226fff", v.BackgroundImgURL, "center", "cover", backgroundFilter, sources.LiveUpdatesScript(apiURL, "vikunja"))
 	} else {
-		html, err = v.getTasksiFrame(tasks, theme, v.BackgroundImgURL, backgroundPosition, backgroundSize, backgroundFilter, apiURL, limit, projectID, queryExcludeProjectIDs, showCreated, showDue, showPriority, showProject, showFavoriteIcon, showLabels, instanceProjects)
+		html, err = v.getTasksiFrame(tasks, sources.NewActionSession(c.Request), theme, v.BackgroundImgURL, backgroundPosition, backgroundSize, backgroundFilter, apiURL, showCreated, showDue, showPriority, showProject, showFavoriteIcon, showLabels, instanceProjects)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 			return
@@ -246,7 +258,7 @@ func (v *Vikunja) GetiFrame(c *gin.Context) {
 	c.Data(http.StatusOK, "text/html", []byte(html))
 }
 
-func (v *Vikunja) getTasksiFrame(tasks []*Task, theme, backgroundImgURL, backgroundPosition, backgroundSize, backgroundFilter, apiURL string, limit, projectID int, excludeProjectIDs string, showCreated, showDue, showPriority, showProject, showFavoriteIcon, showLabels bool, instanceProjects map[int]*Project) ([]byte, error) {
+func (v *Vikunja) getTasksiFrame(tasks []*Task, actions sources.ActionSession, theme, backgroundImgURL, backgroundPosition, backgroundSize, backgroundFilter, apiURL string, showCreated, showDue, showPriority, showProject, showFavoriteIcon, showLabels bool, instanceProjects map[int]*Project) ([]byte, error) {
 	html := `
 <!doctype html>
 <html lang="en">
@@ -395,45 +407,16 @@ func (v *Vikunja) getTasksiFrame(tasks []*Task, theme, backgroundImgURL, backgro
         }
     </style>
 
-    <script>
-        let lastHash = null;
-
-        async function fetchData() {
-            try {
-                var url = '{{ .APIURL }}/v1/hash/vikunja?limit={{ .APILimit }}&project_id={{ .APIProjectID }}&exclude_project_ids={{ .APIExcludeProjectIDs }}';
-                const response = await fetch(url);
-                const data = await response.json();
-
-                if (lastHash === null) {
-                    lastHash = data.hash;
-                } else {
-                    if (data.hash !== lastHash) {
-                        lastHash = data.hash;
-                        location.reload();
-                    }
-                }
-            } catch (error) {
-                console.error('Error getting last update from the API:', error);
-            }
-        }
-
-        function fetchAndUpdate() {
-            fetchData();
-            setTimeout(fetchAndUpdate, 10000); // 10 seconds
-        }
-
-        {{ if .APIURL }}
-            fetchAndUpdate();
-        {{ end }}
-    </script>
+    {{ .LiveUpdates }}
 
     <script>
-      function setTaskDone(taskId) {
+      function setTaskDone(taskId, actionToken) {
         try {
             var xhr = new XMLHttpRequest();
             var url = '{{ .APIURL }}/v1/iframe/vikunja/set_task_done?taskId=' + encodeURIComponent(taskId);
             xhr.open('PATCH', url, true);
             xhr.setRequestHeader('Content-Type', 'application/json');
+            xhr.setRequestHeader('X-Action-Token', actionToken);
 
             xhr.onload = function () {
               if (xhr.status >= 200 && xhr.status < 300) {
@@ -534,7 +517,7 @@ func (v *Vikunja) getTasksiFrame(tasks []*Task, theme, backgroundImgURL, backgro
 
         {{ with . }}{{ if and ($.APIURL) (and (eq .RepeatAfter 0) (eq .RepeatMode 0)) }}
             <div class="set-task-done-container">
-                <button id="task-{{ .ID }}" onclick="setTaskDone('{{ .ID }}')" class="set-task-done-button" onmouseenter="this.style.cursor='pointer';">Done</button>
+                <button id="task-{{ .ID }}" onclick="setTaskDone('{{ .ID }}', '{{ getActionToken .ID }}')" class="set-task-done-button" onmouseenter="this.style.cursor='pointer';">Done</button>
             </div>
         {{ end }}{{ end }}
 
@@ -555,9 +538,7 @@ func (v *Vikunja) getTasksiFrame(tasks []*Task, theme, backgroundImgURL, backgro
 		Tasks:                         tasks,
 		Theme:                         theme,
 		APIURL:                        apiURL,
-		APILimit:                      limit,
-		APIProjectID:                  projectID,
-		APIExcludeProjectIDs:          excludeProjectIDs,
+		LiveUpdates:                   sources.LiveUpdatesScript(apiURL, "vikunja"),
 		VikunjaAddress:                v.Address,
 		BackgroundImageURL:            backgroundImgURL,
 		BackgroundPosition:            template.CSS(backgroundPosition),
@@ -574,6 +555,9 @@ func (v *Vikunja) getTasksiFrame(tasks []*Task, theme, backgroundImgURL, backgro
 	}
 
 	templateFuncs := template.FuncMap{
+		"getActionToken": func(taskID int) string {
+			return actions.Token("vikunja", "set_task_done", strconv.Itoa(taskID))
+		},
 		"getRepeatAfter": func(a int) string {
 			hours := float64(a) / 3600
 			if hours != float64(int(hours)) {
@@ -633,7 +617,7 @@ func (v *Vikunja) getTasksiFrame(tasks []*Task, theme, backgroundImgURL, backgro
 type iframeTemplateData struct {
 	Theme                         string
 	APIURL                        string
-	APIExcludeProjectIDs          string
+	LiveUpdates                   template.HTML
 	VikunjaAddress                string
 	BackgroundImageURL            string
 	BackgroundPosition            template.CSS
@@ -648,12 +632,31 @@ type iframeTemplateData struct {
 	ShowProject                   bool
 	ShowFavoriteIcon              bool
 	ShowLabels                    bool
-	APILimit                      int
-	APIProjectID                  int
 }
 
 // GetHash returns the hash of the tasks
 func (v *Vikunja) GetHash(c *gin.Context) {
+	data, ok := v.getQueryData(c)
+	if !ok {
+		return
+	}
+
+	var tasks []any
+	for _, task := range data.Tasks {
+		tasks = append(tasks, *task)
+	}
+	for _, project := range data.Projects {
+		tasks = append(tasks, *project)
+	}
+
+	hash := sources.GetHash(tasks, time.Now().Format("2006-01-02"))
+
+	c.JSON(http.StatusOK, gin.H{"hash": fmt.Sprintf("%x", hash)})
+}
+
+// getQueryData parses the query parameters used by the hash and data routes and returns the data.
+// If it fails, it writes the error response and returns false.
+func (v *Vikunja) getQueryData(c *gin.Context) (*iFrameData, bool) {
 	queryLimit := c.Query("limit")
 	var limit int
 	var err error
@@ -663,7 +666,7 @@ func (v *Vikunja) GetHash(c *gin.Context) {
 		limit, err = strconv.Atoi(queryLimit)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"message": "limit must be a number"})
-			return
+			return nil, false
 		}
 	}
 
@@ -673,7 +676,7 @@ func (v *Vikunja) GetHash(c *gin.Context) {
 		projectID, err = strconv.Atoi(queryProjectID)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"message": "project_id must be a number"})
-			return
+			return nil, false
 		}
 	}
 
@@ -685,36 +688,45 @@ func (v *Vikunja) GetHash(c *gin.Context) {
 			excludeProjectID, err := strconv.Atoi(excludeProjectIDStr)
 			if err != nil {
 				c.JSON(http.StatusBadRequest, gin.H{"message": "exclude_project_ids must be a comma separated list of numbers"})
-				return
+				return nil, false
 			}
 			excludeProjectIDs = append(excludeProjectIDs, &excludeProjectID)
 		}
 	}
 
-	pTasks := []*Task{}
-	if limit != 0 {
-		pTasks, err = v.GetTasks(limit, projectID, excludeProjectIDs)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-			return
-		}
-	}
-
-	instanceProjects, err := v.GetProjects()
+	data, err := v.getData(c.Request.URL.Query(), limit, projectID, excludeProjectIDs)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-		return
+		return nil, false
 	}
 
-	var tasks []any
-	for _, task := range pTasks {
-		tasks = append(tasks, *task)
-	}
-	for _, project := range instanceProjects {
-		tasks = append(tasks, *project)
-	}
+	return data, true
+}
 
-	hash := sources.GetHash(tasks, time.Now().Format("2006-01-02"))
+type iFrameData struct {
+	Tasks    []*Task
+	Projects []*Project
+}
 
-	c.JSON(http.StatusOK, gin.H{"hash": fmt.Sprintf("%x", hash)})
+// getData returns the tasks and projects used by the iFrame and hash routes.
+// The data is cached by the query parameters that change it.
+func (v *Vikunja) getData(query url.Values, limit, projectID int, excludeProjectIDs []*int) (*iFrameData, error) {
+	key := sources.CacheKey("vikunja", query, "limit", "project_id", "exclude_project_ids")
+	return sources.Cached(key, func() (*iFrameData, error) {
+		data := &iFrameData{Tasks: []*Task{}}
+		var err error
+		if limit != 0 {
+			data.Tasks, err = v.GetTasks(limit, projectID, excludeProjectIDs)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		data.Projects, err = v.GetProjects()
+		if err != nil {
+			return nil, err
+		}
+
+		return data, nil
+	})
 }